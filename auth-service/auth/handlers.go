@@ -2,36 +2,171 @@ package auth
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthLogin(c *gin.Context) {
+type AuthHandler struct {
+	store   TokenStore
+	keyring *Keyring
+}
+
+func NewAuthHandler(store TokenStore, keyring *Keyring) *AuthHandler {
+	return &AuthHandler{store: store, keyring: keyring}
+}
+
+func (h *AuthHandler) AuthLogin(c *gin.Context) {
 	var creds Creds
 	if err := c.Bind(&creds); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
 	}
 	// Validar credenciales con el Servicio de Usuarios
-	if !validCreds(creds.Email, creds.Password) {
+	identity, ok := validCreds(creds.Email, creds.Password)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	token, err := CreateJWT(creds.Email)
+	// Cada login inicia una nueva familia de refresh tokens.
+	familyID, err := randomID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	refreshToken, tokenHash, err := newRefreshToken(familyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	if err := h.store.CreateFamily(c.Request.Context(), familyID, identity, tokenHash, RefreshTokenTTL); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	pair, err := h.newTokenPair(identity, refreshToken)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, pair)
 }
 
-func validCreds(email, password string) bool {
-	res, err := http.Post("http://localhost:8080/login", "application/json", bytes.NewBuffer([]byte(`{"email":"`+email+`", "password":"`+password+`"}`)))
+func (h *AuthHandler) AuthRefresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	familyID, presentedHash, err := parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	refreshToken, nextHash, err := newRefreshToken(familyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	identity, err := h.store.Rotate(c.Request.Context(), familyID, presentedHash, nextHash, RefreshTokenTTL)
+	if err != nil {
+		if errors.Is(err, ErrTokenRevoked) || errors.Is(err, ErrTokenReused) || errors.Is(err, ErrTokenInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not refresh token"})
+		return
+	}
+
+	pair, err := h.newTokenPair(identity, refreshToken)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pair)
+}
+
+func (h *AuthHandler) AuthLogout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	familyID, presentedHash, err := parseRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Sin el secreto, conocer el ID de la familia no basta para cerrar la sesión de otro usuario.
+	if err := h.store.RevokeFamily(c.Request.Context(), familyID, presentedHash); err != nil {
+		if errors.Is(err, ErrTokenInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// JWKS publica las claves públicas para que otros servicios verifiquen los tokens.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keyring.JWKS())
+}
+
+func (h *AuthHandler) newTokenPair(identity Identity, refreshToken string) (TokenPair, error) {
+	token, err := CreateJWT(h.keyring, identity)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// validCreds valida las credenciales con el Servicio de Usuarios y retorna la identidad del usuario.
+func validCreds(email, password string) (Identity, bool) {
+	body, err := json.Marshal(Creds{Email: email, Password: password})
+	if err != nil {
+		return Identity{}, false
+	}
+
+	res, err := http.Post(userServiceURL()+"/login", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		return Identity{}, false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Identity{}, false
+	}
+
+	var identity Identity
+	if err := json.NewDecoder(res.Body).Decode(&identity); err != nil || identity.ID == 0 {
+		return Identity{}, false
+	}
+	return identity, true
+}
 
-	if err != nil || res.StatusCode != http.StatusOK {
-		return false
+func userServiceURL() string {
+	if url := os.Getenv("USER_SERVICE_URL"); url != "" {
+		return url
 	}
-	return true
+	return "http://localhost:8080"
 }
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testIdentity = Identity{ID: 1, Email: "email@example.com", Role: "inventory-manager"}
+
+func initRouter(t *testing.T, store TokenStore) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	keyring, err := NewKeyring([]*Key{{
+		ID:      "test",
+		Method:  jwt.SigningMethodEdDSA,
+		Private: private,
+		Public:  private.Public(),
+	}}, "")
+	if err != nil {
+		t.Fatalf("could not create keyring: %v", err)
+	}
+
+	h := NewAuthHandler(store, keyring)
+	router := gin.New()
+	router.POST("/auth/refresh", h.AuthRefresh)
+	router.POST("/auth/logout", h.AuthLogout)
+	return router
+}
+
+// loginToken crea una familia nueva como lo haría AuthLogin y retorna su refresh token.
+func loginToken(t *testing.T, store TokenStore, identity Identity) string {
+	t.Helper()
+
+	familyID, err := randomID()
+	if err != nil {
+		t.Fatalf("could not generate family id: %v", err)
+	}
+	token, hash, err := newRefreshToken(familyID)
+	if err != nil {
+		t.Fatalf("could not generate refresh token: %v", err)
+	}
+	if err := store.CreateFamily(context.Background(), familyID, identity, hash, RefreshTokenTTL); err != nil {
+		t.Fatalf("could not create family: %v", err)
+	}
+	return token
+}
+
+func postToken(router *gin.Engine, url, refreshToken string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(RefreshRequest{RefreshToken: refreshToken})
+	req := httptest.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodePair(t *testing.T, rr *httptest.ResponseRecorder) TokenPair {
+	t.Helper()
+
+	var pair TokenPair
+	if err := json.Unmarshal(rr.Body.Bytes(), &pair); err != nil {
+		t.Fatalf("failed to unmarshal response body: %v", err)
+	}
+	return pair
+}
+
+func TestAuthRefreshRotatesToken(t *testing.T) {
+	store := NewMemoryTokenStore()
+	router := initRouter(t, store)
+	token := loginToken(t, store, testIdentity)
+
+	rr := postToken(router, "/auth/refresh", token)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %v want %v", rr.Code, http.StatusOK)
+	}
+	pair := decodePair(t, rr)
+	if pair.Token == "" || pair.RefreshToken == "" || pair.RefreshToken == token {
+		t.Fatalf("expected a new token pair, got %+v", pair)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(pair.Token, claims); err != nil {
+		t.Fatalf("could not parse access token: %v", err)
+	}
+	if claims["sub"] != "1" || claims["email"] != testIdentity.Email || claims["role"] != testIdentity.Role {
+		t.Errorf("unexpected access token claims: %v", claims)
+	}
+
+	rr = postToken(router, "/auth/refresh", pair.RefreshToken)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status refreshing rotated token: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestAuthRefreshReuseRevokesFamily(t *testing.T) {
+	store := NewMemoryTokenStore()
+	router := initRouter(t, store)
+	token := loginToken(t, store, testIdentity)
+
+	rotated := decodePair(t, postToken(router, "/auth/refresh", token)).RefreshToken
+
+	// Reutilizar el token original revoca la familia...
+	if rr := postToken(router, "/auth/refresh", token); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status reusing token: got %v want %v", rr.Code, http.StatusUnauthorized)
+	}
+	// ...así que el token rotado legítimo tampoco sirve.
+	if rr := postToken(router, "/auth/refresh", rotated); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status after family revocation: got %v want %v", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthLogout(t *testing.T) {
+	store := NewMemoryTokenStore()
+	router := initRouter(t, store)
+	token := loginToken(t, store, testIdentity)
+
+	if rr := postToken(router, "/auth/logout", token); rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if rr := postToken(router, "/auth/refresh", token); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status after logout: got %v want %v", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthLogoutRequiresSecret(t *testing.T) {
+	tc := []struct {
+		Name    string
+		Token   func(token, rotated string) string
+		Status  int
+		Revoked bool
+	}{
+		{Name: "Current token", Token: func(token, rotated string) string { return rotated }, Status: http.StatusOK, Revoked: true},
+		{Name: "Rotated token", Token: func(token, rotated string) string { return token }, Status: http.StatusOK, Revoked: true},
+		{Name: "Wrong secret", Token: func(token, rotated string) string {
+			familyID, _, _ := strings.Cut(rotated, ".")
+			return familyID + ".guessed"
+		}, Status: http.StatusUnauthorized},
+		{Name: "Unknown family", Token: func(token, rotated string) string { return "family.secret" }, Status: http.StatusOK},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			store := NewMemoryTokenStore()
+			router := initRouter(t, store)
+			token := loginToken(t, store, testIdentity)
+			rotated := decodePair(t, postToken(router, "/auth/refresh", token)).RefreshToken
+
+			if rr := postToken(router, "/auth/logout", tc.Token(token, rotated)); rr.Code != tc.Status {
+				t.Fatalf("unexpected status: got %v want %v", rr.Code, tc.Status)
+			}
+
+			want := http.StatusOK
+			if tc.Revoked {
+				want = http.StatusUnauthorized
+			}
+			if rr := postToken(router, "/auth/refresh", rotated); rr.Code != want {
+				t.Fatalf("unexpected status after logout: got %v want %v", rr.Code, want)
+			}
+		})
+	}
+}
+
+func TestAuthRefreshWrongSecretKeepsFamily(t *testing.T) {
+	store := NewMemoryTokenStore()
+	router := initRouter(t, store)
+	token := loginToken(t, store, testIdentity)
+	familyID, _, _ := strings.Cut(token, ".")
+
+	// Un secreto que nunca se emitió no es una reutilización: no revoca la familia.
+	if rr := postToken(router, "/auth/refresh", familyID+".guessed"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unexpected status: got %v want %v", rr.Code, http.StatusUnauthorized)
+	}
+	if rr := postToken(router, "/auth/refresh", token); rr.Code != http.StatusOK {
+		t.Fatalf("unexpected status: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestAuthRefreshInvalidToken(t *testing.T) {
+	router := initRouter(t, NewMemoryTokenStore())
+
+	tc := []struct {
+		Name  string
+		Token string
+	}{
+		{Name: "Malformed token", Token: "not-a-token"},
+		{Name: "Unknown family", Token: "family.secret"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if rr := postToken(router, "/auth/refresh", tc.Token); rr.Code != http.StatusUnauthorized {
+				t.Fatalf("unexpected status: got %v want %v", rr.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
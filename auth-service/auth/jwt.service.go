@@ -1,17 +1,26 @@
 package auth
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func CreateJWT(email string) (string, error) {
+const (
+	// AccessTokenTTL es la vigencia de los tokens de acceso; es corta porque no se pueden revocar.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL es la vigencia de una familia de refresh tokens desde su última rotación.
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+func CreateJWT(keyring *Keyring, identity Identity) (string, error) {
 	claims := jwt.MapClaims{
-		"sub": email,
-		"exp": time.Now().Add(time.Hour * 2).Unix(),
+		"sub":   strconv.FormatUint(uint64(identity.ID), 10),
+		"email": identity.Email,
+		"role":  identity.Role,
+		"exp":   time.Now().Add(AccessTokenTTL).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte("SECRET_JWT"))
+	return keyring.Sign(claims)
 }
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		t.Fatalf("could not write key: %v", err)
+	}
+}
+
+// initKeysDir crea un directorio con una clave RSA retirada (solo pública),
+// una clave RSA PKCS#1 y una clave Ed25519 PKCS#8.
+func initKeysDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	retired, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+	retiredDER, err := x509.MarshalPKIXPublicKey(retired.Public())
+	if err != nil {
+		t.Fatalf("could not marshal public key: %v", err)
+	}
+	writePEM(t, dir, "2023-12.pem", "PUBLIC KEY", retiredDER)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+	writePEM(t, dir, "2024-01.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate ed25519 key: %v", err)
+	}
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatalf("could not marshal private key: %v", err)
+	}
+	writePEM(t, dir, "2024-02.pem", "PRIVATE KEY", edDER)
+
+	return dir
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := initKeysDir(t)
+
+	tc := []struct {
+		Name           string
+		SigningKID     string
+		ExpectedKID    string
+		ExpectedMethod string
+		ExpectedError  bool
+	}{
+		{Name: "Latest key by default", ExpectedKID: "2024-02", ExpectedMethod: "EdDSA"},
+		{Name: "Explicit RSA key", SigningKID: "2024-01", ExpectedKID: "2024-01", ExpectedMethod: "RS256"},
+		{Name: "Retired key cannot sign", SigningKID: "2023-12", ExpectedError: true},
+		{Name: "Unknown key", SigningKID: "missing", ExpectedError: true},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			keyring, err := LoadKeyring(dir, tc.SigningKID)
+			if tc.ExpectedError {
+				if err == nil {
+					t.Fatalf("expected an error loading signing key %q", tc.SigningKID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("could not load keyring: %v", err)
+			}
+
+			tokenString, err := CreateJWT(keyring, Identity{ID: 1, Email: "email@example.com", Role: "customer"})
+			if err != nil {
+				t.Fatalf("could not sign token: %v", err)
+			}
+
+			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+				key, _ := keyring.PublicKey(token.Header["kid"].(string))
+				return key.Public, nil
+			}, jwt.WithExpirationRequired())
+			if err != nil {
+				t.Fatalf("could not verify token: %v", err)
+			}
+			if token.Header["kid"] != tc.ExpectedKID || token.Method.Alg() != tc.ExpectedMethod {
+				t.Errorf("unexpected signing key: got %v/%v want %v/%v",
+					token.Header["kid"], token.Method.Alg(), tc.ExpectedKID, tc.ExpectedMethod)
+			}
+			if exp, _ := token.Claims.GetExpirationTime(); exp == nil || exp.After(time.Now().Add(AccessTokenTTL)) {
+				t.Errorf("unexpected expiration: %v", exp)
+			}
+		})
+	}
+}
+
+func TestKeyringJWKS(t *testing.T) {
+	keyring, err := LoadKeyring(initKeysDir(t), "")
+	if err != nil {
+		t.Fatalf("could not load keyring: %v", err)
+	}
+
+	set := keyring.JWKS()
+	if len(set.Keys) != 3 {
+		t.Fatalf("unexpected number of keys: got %v want %v", len(set.Keys), 3)
+	}
+
+	expected := map[string]string{"2023-12": "RSA", "2024-01": "RSA", "2024-02": "OKP"}
+	for _, key := range set.Keys {
+		if expected[key.Kid] != key.Kty {
+			t.Errorf("unexpected key type for %v: got %v want %v", key.Kid, key.Kty, expected[key.Kid])
+		}
+		if key.Kty == "RSA" && (key.N == "" || key.E != "AQAB") {
+			t.Errorf("unexpected RSA parameters for %v: %+v", key.Kid, key)
+		}
+		if key.Kty == "OKP" && (key.Crv != "Ed25519" || key.X == "") {
+			t.Errorf("unexpected OKP parameters for %v: %+v", key.Kid, key)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type tokenFamily struct {
+	identity Identity
+	current  string
+	// retired son los hashes de los tokens ya rotados, para distinguir una reutilización de un secreto inválido.
+	retired   map[string]bool
+	revoked   bool
+	expiresAt time.Time
+}
+
+// issued indica si hash es el token vigente o uno ya rotado de la familia.
+func (f *tokenFamily) issued(hash string) bool {
+	return hash == f.current || f.retired[hash]
+}
+
+// MemoryTokenStore implementa TokenStore en memoria; útil para desarrollo y pruebas.
+// Las familias vencidas se descartan al crear una nueva.
+type MemoryTokenStore struct {
+	mu       sync.Mutex
+	families map[string]*tokenFamily
+}
+
+// NewMemoryTokenStore crea un TokenStore en memoria vacío.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{families: make(map[string]*tokenFamily)}
+}
+
+func (s *MemoryTokenStore) CreateFamily(ctx context.Context, familyID string, identity Identity, tokenHash string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, family := range s.families {
+		if now.After(family.expiresAt) {
+			delete(s.families, id)
+		}
+	}
+
+	s.families[familyID] = &tokenFamily{
+		identity:  identity,
+		current:   tokenHash,
+		retired:   map[string]bool{},
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
+
+func (s *MemoryTokenStore) Rotate(ctx context.Context, familyID, presentedHash, nextHash string, ttl time.Duration) (Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	family, ok := s.families[familyID]
+	if !ok || family.revoked || time.Now().After(family.expiresAt) {
+		return Identity{}, ErrTokenRevoked
+	}
+
+	// Un token ya rotado indica que fue robado: se revoca toda la familia.
+	if family.retired[presentedHash] {
+		family.revoked = true
+		return Identity{}, ErrTokenReused
+	}
+	if family.current != presentedHash {
+		return Identity{}, ErrTokenInvalid
+	}
+
+	family.retired[presentedHash] = true
+	family.current = nextHash
+	family.expiresAt = time.Now().Add(ttl)
+	return family.identity, nil
+}
+
+func (s *MemoryTokenStore) RevokeFamily(ctx context.Context, familyID, presentedHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	family, ok := s.families[familyID]
+	if !ok || time.Now().After(family.expiresAt) {
+		return nil
+	}
+	if !family.issued(presentedHash) {
+		return ErrTokenInvalid
+	}
+	family.revoked = true
+	return nil
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryTokenStorePrunesExpiredFamilies(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenStore()
+
+	if err := store.CreateFamily(ctx, "expired", testIdentity, "hash", time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.CreateFamily(ctx, "revoked", testIdentity, "hash", time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.RevokeFamily(ctx, "revoked", "hash"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.CreateFamily(ctx, "active", testIdentity, "hash", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if err := store.CreateFamily(ctx, "new", testIdentity, "hash", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := []struct {
+		FamilyID string
+		Expected bool
+	}{
+		{FamilyID: "expired", Expected: false},
+		{FamilyID: "revoked", Expected: false},
+		{FamilyID: "active", Expected: true},
+		{FamilyID: "new", Expected: true},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.FamilyID, func(t *testing.T) {
+			store.mu.Lock()
+			_, ok := store.families[tc.FamilyID]
+			store.mu.Unlock()
+			if ok != tc.Expected {
+				t.Fatalf("unexpected family: got %v want %v", ok, tc.Expected)
+			}
+		})
+	}
+}
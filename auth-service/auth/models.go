@@ -4,3 +4,20 @@ type Creds struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// Identity es la identidad que user-service retorna en el login y que viaja en los tokens.
+type Identity struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
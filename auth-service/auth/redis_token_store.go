@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Los hashes de los tokens ya rotados se guardan en la misma clave como campos "retired:<hash>",
+// de modo que expiran junto con la familia.
+
+// rotateScript rota el token vigente de forma atómica. Retorna la identidad de la familia, o
+// "revoked"/"reused"/"invalid" como error para que el cliente los traduzca.
+var rotateScript = redis.NewScript(`
+local family = redis.call("HMGET", KEYS[1], "id", "current", "revoked", "email", "role")
+if not family[1] or family[3] == "1" then
+	return redis.error_reply("revoked")
+end
+if family[2] ~= ARGV[1] then
+	if redis.call("HEXISTS", KEYS[1], "retired:" .. ARGV[1]) == 1 then
+		redis.call("HSET", KEYS[1], "revoked", "1")
+		return redis.error_reply("reused")
+	end
+	return redis.error_reply("invalid")
+end
+redis.call("HSET", KEYS[1], "current", ARGV[2], "retired:" .. ARGV[1], "1")
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return {family[1], family[4], family[5]}
+`)
+
+// revokeScript marca la familia como revocada solo si existe, para no crear claves sin expiración,
+// y si el token presentado fue emitido para ella.
+var revokeScript = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "current")
+if not current then
+	return 1
+end
+if current ~= ARGV[1] and redis.call("HEXISTS", KEYS[1], "retired:" .. ARGV[1]) == 0 then
+	return redis.error_reply("invalid")
+end
+redis.call("HSET", KEYS[1], "revoked", "1")
+return 1
+`)
+
+// RedisTokenStore implementa TokenStore sobre Redis para compartir el estado entre instancias.
+type RedisTokenStore struct {
+	client *redis.Client
+}
+
+// NewRedisTokenStore crea un TokenStore respaldado por Redis.
+func NewRedisTokenStore(client *redis.Client) *RedisTokenStore {
+	return &RedisTokenStore{client: client}
+}
+
+func familyKey(familyID string) string {
+	return "auth:refresh_family:" + familyID
+}
+
+func (s *RedisTokenStore) CreateFamily(ctx context.Context, familyID string, identity Identity, tokenHash string, ttl time.Duration) error {
+	key := familyKey(familyID)
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, key,
+		"id", identity.ID, "email", identity.Email, "role", identity.Role,
+		"current", tokenHash, "revoked", "0")
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *RedisTokenStore) Rotate(ctx context.Context, familyID, presentedHash, nextHash string, ttl time.Duration) (Identity, error) {
+	values, err := rotateScript.Run(ctx, s.client, []string{familyKey(familyID)},
+		presentedHash, nextHash, ttl.Milliseconds()).StringSlice()
+	if err != nil {
+		switch err.Error() {
+		case "revoked":
+			return Identity{}, ErrTokenRevoked
+		case "reused":
+			return Identity{}, ErrTokenReused
+		case "invalid":
+			return Identity{}, ErrTokenInvalid
+		}
+		return Identity{}, err
+	}
+
+	id, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{ID: uint(id), Email: values[1], Role: values[2]}, nil
+}
+
+func (s *RedisTokenStore) RevokeFamily(ctx context.Context, familyID, presentedHash string) error {
+	err := revokeScript.Run(ctx, s.client, []string{familyKey(familyID)}, presentedHash).Err()
+	if err != nil && err.Error() == "invalid" {
+		return ErrTokenInvalid
+	}
+	return err
+}
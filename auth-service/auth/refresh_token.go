@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Los refresh tokens son opacos con el formato "<familia>.<secreto>". El store solo guarda
+// el hash del secreto, así que una fuga del store no permite reutilizar los tokens.
+
+func randomID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newRefreshToken genera un token para la familia indicada y retorna el token y el hash de su secreto.
+func newRefreshToken(familyID string) (string, string, error) {
+	secret, err := randomID()
+	if err != nil {
+		return "", "", err
+	}
+	return familyID + "." + secret, hashSecret(secret), nil
+}
+
+// parseRefreshToken separa un refresh token en su familia y el hash de su secreto.
+func parseRefreshToken(token string) (string, string, error) {
+	familyID, secret, ok := strings.Cut(token, ".")
+	if !ok || familyID == "" || secret == "" {
+		return "", "", errors.New("invalid refresh token")
+	}
+	return familyID, hashSecret(secret), nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTokenRevoked indica que la familia del refresh token fue revocada, expiró o no existe.
+	ErrTokenRevoked = errors.New("refresh token revoked")
+	// ErrTokenReused indica que se presentó un refresh token que ya había sido rotado.
+	ErrTokenReused = errors.New("refresh token reused")
+	// ErrTokenInvalid indica que el secreto no corresponde a ningún token emitido para la familia.
+	ErrTokenInvalid = errors.New("invalid refresh token")
+)
+
+// TokenStore guarda las familias de refresh tokens y su estado de revocación.
+// Una familia nace en el login y cada rotación reemplaza su token vigente. La familia guarda
+// la identidad del login, así que un cambio de rol se refleja en el siguiente login.
+type TokenStore interface {
+	// CreateFamily registra una nueva familia cuyo token vigente es tokenHash.
+	CreateFamily(ctx context.Context, familyID string, identity Identity, tokenHash string, ttl time.Duration) error
+
+	// Rotate reemplaza el token vigente de la familia por nextHash y retorna la identidad dueña de la familia.
+	// Si presentedHash es un token ya rotado, la familia completa queda revocada y se retorna ErrTokenReused.
+	// Si no es ningún token de la familia se retorna ErrTokenInvalid sin modificarla.
+	Rotate(ctx context.Context, familyID, presentedHash, nextHash string, ttl time.Duration) (Identity, error)
+
+	// RevokeFamily revoca la familia para que ninguno de sus tokens pueda volver a usarse. presentedHash debe
+	// ser un token emitido para la familia; si no lo es se retorna ErrTokenInvalid sin revocarla.
+	// Una familia que ya no existe no tiene nada que revocar.
+	RevokeFamily(ctx context.Context, familyID, presentedHash string) error
+}
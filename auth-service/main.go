@@ -1,14 +1,44 @@
 package main
 
 import (
+	"log"
+	"os"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jaider-nieto/ecommerce-go/auth-service/auth"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
+	keyring, err := auth.LoadKeyring(os.Getenv("JWT_KEYS_DIR"), os.Getenv("JWT_SIGNING_KID"))
+	if err != nil {
+		log.Fatalf("Error loading signing keys: %v", err)
+	}
+
+	authHandler := auth.NewAuthHandler(newTokenStore(), keyring)
+
 	router := gin.Default()
 
-	router.POST("/auth", auth.AuthLogin)
+	router.POST("/auth", authHandler.AuthLogin)
+	router.POST("/auth/refresh", authHandler.AuthRefresh)
+	router.POST("/auth/logout", authHandler.AuthLogout)
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
 
 	router.Run(":8081")
 }
+
+// newTokenStore elige el store de refresh tokens según TOKEN_STORE ("memory" por defecto o "redis").
+func newTokenStore() auth.TokenStore {
+	switch os.Getenv("TOKEN_STORE") {
+	case "redis":
+		return auth.NewRedisTokenStore(redis.NewClient(&redis.Options{
+			Addr:     os.Getenv("REDIS_ADR"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+		}))
+	case "", "memory":
+		return auth.NewMemoryTokenStore()
+	default:
+		log.Fatalf("unknown TOKEN_STORE %q", os.Getenv("TOKEN_STORE"))
+		return nil
+	}
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/jaider-nieto/ecommerce-go/products-service/docs"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/config"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/routes"
 	"github.com/joho/godotenv"
 	"github.com/swaggo/files"
@@ -18,17 +20,35 @@ import (
 // @description Tag service API in Go using Gin framework
 // @host localhost:8082
 // @basePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Fatalf("Error loading .env file")
 	}
 
+	jwksURL := os.Getenv("JWKS_URL")
+	if jwksURL == "" {
+		log.Fatalf("JWKS_URL is not set")
+	}
+
 	c := config.NewContainer()
 	router := gin.Default()
 	router.Use(gin.Logger())
+	router.Use(middlewares.CorrelationMiddleware())
 
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if c.ImagesDir != "" {
+		router.Static(config.LocalImagesPath, c.ImagesDir)
+	}
 
-	routes.ProductRoutes(router, c)
+	authMiddleware := middlewares.AuthMiddleware(auth.NewJWKS(jwksURL).KeyFunc)
+	routes.ProductRoutes(router, c.Products, authMiddleware)
+	routes.WarehouseRoutes(router, c.Warehouses, authMiddleware)
+	routes.ReviewRoutes(router, c.Reviews, authMiddleware)
+	routes.CategoryRoutes(router, c.Categories, authMiddleware)
+	routes.AlertRoutes(router, c.Products, authMiddleware)
+	routes.CacheRoutes(router, c.Products, authMiddleware)
 	router.Run(":" + os.Getenv("PORT"))
 }
@@ -0,0 +1,195 @@
+// El cliente JWKS está copiado en products-service/internal/auth y user-service/auth, porque cada
+// servicio se construye solo con su directorio. Las copias de jwks.go, jwks_test.go y
+// jwks_copy_test.go deben ser idénticas; TestJWKSCopiesMatch falla si se desincronizan.
+
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// jwksMaxAge es cada cuánto se vuelve a descargar el JWKS para descartar claves retiradas.
+	jwksMaxAge = time.Hour
+	// jwksMinRefresh limita las descargas provocadas por tokens con un kid desconocido.
+	jwksMinRefresh = time.Minute
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+// JWKS obtiene y cachea las claves públicas publicadas por auth-service.
+type JWKS struct {
+	url    string
+	client *http.Client
+
+	// fetchMu serializa las descargas; mu solo protege las claves, así que las búsquedas no esperan a la red.
+	fetchMu     sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// NewJWKS crea un cliente para el JWKS publicado en la URL indicada.
+func NewJWKS(url string) *JWKS {
+	return &JWKS{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   make(map[string]interface{}),
+	}
+}
+
+// KeyFunc resuelve la clave pública del token a partir de su kid. Si el kid no se conoce,
+// vuelve a descargar el JWKS para aceptar claves recién rotadas.
+func (j *JWKS) KeyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token kid is missing")
+	}
+
+	key, ok, stale := j.lookup(kid)
+	if !ok || stale {
+		if err := j.refresh(context.Background(), !ok); err != nil && !ok {
+			return nil, err
+		}
+		key, ok, _ = j.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	// Evita la confusión de algoritmos: el método del token debe coincidir con el tipo de clave.
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+	case ed25519.PublicKey:
+		if _, ok := token.Method.(*jwt.SigningMethodEd25519); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+	}
+
+	return key, nil
+}
+
+func (j *JWKS) lookup(kid string) (interface{}, bool, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	key, ok := j.keys[kid]
+	return key, ok, time.Since(j.fetchedAt) > jwksMaxAge
+}
+
+// refresh descarga el JWKS. Con force la descarga ocurre aunque la caché sea reciente,
+// siempre que haya pasado jwksMinRefresh desde el último intento, exitoso o no. Sin force, si otra
+// descarga está en curso se sigue usando la caché vencida en lugar de esperarla.
+func (j *JWKS) refresh(ctx context.Context, force bool) error {
+	if force {
+		j.fetchMu.Lock()
+	} else if !j.fetchMu.TryLock() {
+		return nil
+	}
+	defer j.fetchMu.Unlock()
+
+	j.mu.RLock()
+	attempted, fetched := j.attemptedAt, j.fetchedAt
+	j.mu.RUnlock()
+	if time.Since(attempted) < jwksMinRefresh || (!force && time.Since(fetched) < jwksMaxAge) {
+		return nil
+	}
+
+	keys, err := j.fetch(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.attemptedAt = time.Now()
+	if err != nil {
+		return err
+	}
+	j.keys = keys
+	j.fetchedAt = j.attemptedAt
+	return nil
+}
+
+// fetch descarga el JWKS y retorna sus claves por kid.
+func (j *JWKS) fetch(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching JWKS: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching JWKS: status %d", res.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error decoding JWKS: %v", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue // Ignora claves de tipos no soportados.
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
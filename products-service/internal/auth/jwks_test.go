@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer publica una clave Ed25519 como lo hace auth-service y cuenta las descargas.
+// Mientras failing sea verdadero responde con un error; block retiene cada respuesta hasta cerrarse.
+type jwksServer struct {
+	*httptest.Server
+	hits    atomic.Int32
+	failing atomic.Bool
+	block   chan struct{}
+}
+
+func initJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "OKP", "kid": "ed", "alg": "EdDSA", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(public)},
+			{"kty": "EC", "kid": "ec", "crv": "P-256"},
+		},
+	}
+
+	server := &jwksServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.hits.Add(1)
+		if server.block != nil {
+			<-server.block
+		}
+		if server.failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func tokenWithKid(method jwt.SigningMethod, kid string) *jwt.Token {
+	token := jwt.New(method)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token
+}
+
+func TestJWKSKeyFunc(t *testing.T) {
+	jwks := NewJWKS(initJWKSServer(t).URL)
+
+	tc := []struct {
+		Name  string
+		Token *jwt.Token
+		Valid bool
+	}{
+		{Name: "Known key", Token: tokenWithKid(jwt.SigningMethodEdDSA, "ed"), Valid: true},
+		{Name: "Missing kid", Token: tokenWithKid(jwt.SigningMethodEdDSA, "")},
+		{Name: "Unknown kid", Token: tokenWithKid(jwt.SigningMethodEdDSA, "missing")},
+		{Name: "Unsupported key type", Token: tokenWithKid(jwt.SigningMethodES256, "ec")},
+		{Name: "Algorithm confusion", Token: tokenWithKid(jwt.SigningMethodRS256, "ed")},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := jwks.KeyFunc(tc.Token)
+			if (err == nil) != tc.Valid {
+				t.Fatalf("unexpected error: got %v want valid %v", err, tc.Valid)
+			}
+		})
+	}
+}
+
+func TestJWKSFailedRefreshIsRateLimited(t *testing.T) {
+	server := initJWKSServer(t)
+	server.failing.Store(true)
+	jwks := NewJWKS(server.URL)
+
+	for i := 0; i < 3; i++ {
+		if _, err := jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "ed")); err == nil {
+			t.Fatalf("expected the key to be unavailable")
+		}
+	}
+	if hits := server.hits.Load(); hits != 1 {
+		t.Fatalf("unexpected JWKS downloads: got %v want %v", hits, 1)
+	}
+
+	// Con la caché vencida, un fallo tampoco provoca una descarga por solicitud.
+	server.failing.Store(false)
+	jwks.attemptedAt = time.Time{}
+	if _, err := jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "ed")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server.failing.Store(true)
+	jwks.fetchedAt = time.Now().Add(-2 * jwksMaxAge)
+	jwks.attemptedAt = jwks.fetchedAt
+	for i := 0; i < 3; i++ {
+		if _, err := jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "ed")); err != nil {
+			t.Fatalf("expected the stale key to be used: %v", err)
+		}
+	}
+	if hits := server.hits.Load(); hits != 3 {
+		t.Fatalf("unexpected JWKS downloads: got %v want %v", hits, 3)
+	}
+}
+
+func TestJWKSLookupDoesNotWaitForFetch(t *testing.T) {
+	server := initJWKSServer(t)
+	jwks := NewJWKS(server.URL)
+	if _, err := jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "ed")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Un kid desconocido fuerza una descarga que queda retenida en el servidor.
+	server.block = make(chan struct{})
+	jwks.attemptedAt = time.Time{}
+	forced := make(chan struct{})
+	go func() {
+		jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "rotated"))
+		close(forced)
+	}()
+	for server.hits.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	resolved := make(chan error)
+	go func() {
+		_, err := jwks.KeyFunc(tokenWithKid(jwt.SigningMethodEdDSA, "ed"))
+		resolved <- err
+	}()
+	select {
+	case err := <-resolved:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("the lookup waited for the JWKS download")
+	}
+
+	close(server.block)
+	<-forced
+}
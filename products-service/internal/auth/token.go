@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParseToken valida la firma, la expiración y el sujeto del token y retorna sus claims.
+func ParseToken(tokenString string, keyFunc jwt.Keyfunc) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New("token expired")
+		}
+		return nil, errors.New("invalid token")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token subject is missing")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// Backends de cache que admite CACHE_BACKEND.
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
+	CacheBackendTiered = "tiered"
+)
+
+// NewProductCache crea el cache de productos según CACHE_BACKEND:
+//
+//	redis   (por defecto) Redis compartido por todas las instancias
+//	memory  un LRU en memoria del proceso; no necesita Redis
+//	tiered  un LRU local delante de Redis, invalidado entre instancias por pub/sub
+//
+// CACHE_MAX_ENTRIES limita las entradas del LRU (10000 por defecto) y CACHE_LOCAL_TTL la vigencia
+// de las copias locales en modo tiered (10s por defecto). Los backends con Redis van detrás del
+// circuit breaker, de modo que el servicio sigue atendiendo desde MongoDB si Redis falla.
+func NewProductCache(ctx context.Context) interfaces.ProductRedisRepositoryInterface {
+	backend := os.Getenv("CACHE_BACKEND")
+	if backend == "" {
+		backend = CacheBackendRedis
+	}
+
+	cachePrefix := os.Getenv("CACHE_PREFIX")
+	if cachePrefix == "" {
+		cachePrefix = "products-service"
+	}
+
+	switch backend {
+	case CacheBackendRedis:
+		redisCache := repository.NewProductRedisRepository(newRedisClient(), cachePrefix)
+		return repository.NewProductCacheBreaker(redisCache, repository.DefaultBreakerOptions())
+	case CacheBackendMemory:
+		return repository.NewProductLRURepository(cacheMaxEntries())
+	case CacheBackendTiered:
+		redisCache := repository.NewProductRedisRepository(newRedisClient(), cachePrefix)
+		tiered := repository.NewProductTieredRepository(repository.NewProductLRURepository(cacheMaxEntries()), redisCache, cacheLocalTTL())
+		go tiered.Listen(ctx)
+		return repository.NewProductCacheBreaker(tiered, repository.DefaultBreakerOptions())
+	default:
+		log.Fatalf("invalid CACHE_BACKEND: %q", backend)
+		return nil
+	}
+}
+
+func newRedisClient() *redis.Client {
+	return InitRedisClient(os.Getenv("REDIS_ADR"), os.Getenv("REDIS_PASSWORD"))
+}
+
+func cacheMaxEntries() int {
+	value := os.Getenv("CACHE_MAX_ENTRIES")
+	if value == "" {
+		return 10000
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid CACHE_MAX_ENTRIES: %q", value)
+	}
+	return n
+}
+
+func cacheLocalTTL() time.Duration {
+	value := os.Getenv("CACHE_LOCAL_TTL")
+	if value == "" {
+		return 10 * time.Second
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid CACHE_LOCAL_TTL: %q", value)
+	}
+	return d
+}
+
+// NewCacheOptions lee la vigencia del cache de CACHE_TTL_PRODUCT, CACHE_TTL_LIST y CACHE_STALE_TTL
+// (duraciones como "30s" o "5m"); las variables vacías conservan el valor por defecto. Solo
+// CACHE_STALE_TTL admite 0, que desactiva stale-while-revalidate.
+func NewCacheOptions() service.CacheOptions {
+	options := service.DefaultCacheOptions()
+
+	for name, target := range map[string]*time.Duration{
+		"CACHE_TTL_PRODUCT": &options.ProductTTL,
+		"CACHE_TTL_LIST":    &options.ListTTL,
+		"CACHE_STALE_TTL":   &options.StaleTTL,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 || d == 0 && name != "CACHE_STALE_TTL" {
+			log.Fatalf("invalid %s: %q", name, value)
+		}
+		*target = d
+	}
+
+	return options
+}
@@ -1,23 +1,98 @@
 package config
 
 import (
+	"context"
+	"log"
 	"os"
+	"time"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
 )
 
-func NewContainer() *controller.ProductController {
+// Container agrupa los controladores que expone el servicio.
+type Container struct {
+	Products   *controller.ProductController
+	Warehouses *controller.WarehouseController
+	Reviews    *controller.ReviewController
+	Categories *controller.CategoryController
+	// ImagesDir es el directorio de imágenes a servir en LocalImagesPath; vacío si se usa S3.
+	ImagesDir string
+}
+
+func NewContainer() *Container {
 
 	mongoURI := os.Getenv("MONGO_URI")
 	clientMongo := InitMongoDB(mongoURI)
-	clientRedis := InitRedisClient(os.Getenv("REDIS_ADR"), os.Getenv("REDIS_PASSWORD"))
-
-	productCacheRepository := repository.NewProductRedisRepository(clientRedis)
+	productCacheRepository := NewProductCache(context.Background())
 	productRepository := repository.NewProductRepository(GetMongoCollection(clientMongo, "products_db", "products"))
-	productService := service.NewProductService(productRepository, productCacheRepository)
+	if err := productRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := productRepository.MigrateLegacyRatings(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+	legacyCurrency, legacyScale := LegacyPrices()
+	if err := productRepository.MigrateLegacyPrices(context.Background(), legacyCurrency, legacyScale); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	reservationRepository := repository.NewReservationRepository(GetMongoCollection(clientMongo, "products_db", "reservations"))
+	if err := reservationRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	movementRepository := repository.NewMovementRepository(GetMongoCollection(clientMongo, "products_db", "movements"))
+	if err := movementRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	priceHistoryRepository := repository.NewPriceHistoryRepository(GetMongoCollection(clientMongo, "products_db", "price_history"))
+	if err := priceHistoryRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	warehouseRepository := repository.NewWarehouseRepository(GetMongoCollection(clientMongo, "products_db", "warehouses"))
+	if err := warehouseRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	reviewRepository := repository.NewReviewRepository(GetMongoCollection(clientMongo, "products_db", "reviews"))
+	if err := reviewRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	categoryRepository := repository.NewCategoryRepository(GetMongoCollection(clientMongo, "products_db", "categories"))
+	if err := categoryRepository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	imageStorage, imagesDir := NewImageStorage()
+
+	productService := service.NewProductService(productRepository, productCacheRepository, reservationRepository, movementRepository, priceHistoryRepository, reviewRepository, warehouseRepository, NewAlertNotifier(), imageStorage, NewCacheOptions())
 	productController := controller.NewProductController(productService)
+	warehouseController := controller.NewWarehouseController(service.NewWarehouseService(warehouseRepository, productRepository, productCacheRepository))
+	reviewController := controller.NewReviewController(service.NewReviewService(reviewRepository, productRepository, productCacheRepository))
+
+	// Las categorías válidas y sus reglas de reposición se resuelven contra la colección a través de la copia en memoria.
+	categoryService := service.NewCategoryService(categoryRepository, productRepository, productCacheRepository)
+	if err := categoryService.SeedDefaults(context.Background()); err != nil {
+		log.Fatalf("%v", err)
+	}
+	utils.SetCategoryLookup(categoryService.Exists)
+	utils.SetReorderRulesLookup(categoryService.ReorderRules)
+	categoryController := controller.NewCategoryController(categoryService)
+
+	// Libera en segundo plano las reservas de stock que expiran.
+	go productService.RunReservationSweeper(context.Background(), time.Minute)
+	// Genera las miniaturas de las imágenes nuevas y de las que quedaron pendientes.
+	go productService.RunThumbnailWorker(context.Background(), time.Minute)
+	// Elimina definitivamente los productos que superaron su tiempo en la papelera.
+	go productService.RunTrashPurger(context.Background(), time.Hour, TrashRetention())
+	// Recarga las categorías modificadas por otras instancias.
+	go categoryService.RunCategoryRefresher(context.Background(), time.Minute)
 
-	return productController
+	return &Container{Products: productController, Warehouses: warehouseController, Reviews: reviewController, Categories: categoryController, ImagesDir: imagesDir}
 }
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// LegacyPrices indica cómo interpretar los precios guardados antes de tener moneda: LEGACY_PRICE_CURRENCY
+// es su moneda (COP por defecto) y LEGACY_PRICE_UNIT indica si estaban en unidades mayores ("major",
+// por defecto) o menores ("minor"). Retorna la moneda y cuántas unidades menores valía cada unidad antigua.
+func LegacyPrices() (string, int64) {
+	currency := strings.ToUpper(os.Getenv("LEGACY_PRICE_CURRENCY"))
+	if currency == "" {
+		currency = "COP"
+	}
+	if _, ok := constants.CurrencyMinorUnits[currency]; !ok {
+		log.Fatalf("invalid LEGACY_PRICE_CURRENCY: %q", currency)
+	}
+
+	switch unit := os.Getenv("LEGACY_PRICE_UNIT"); unit {
+	case "", "major":
+		return currency, models.CurrencyScale(currency)
+	case "minor":
+		return currency, 1
+	default:
+		log.Fatalf("invalid LEGACY_PRICE_UNIT: %q", unit)
+		return "", 0
+	}
+}
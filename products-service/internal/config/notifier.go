@@ -0,0 +1,28 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+)
+
+// NewAlertNotifier elige el destino de las alertas de stock según ALERT_NOTIFIER: log (por defecto), webhook o memory.
+func NewAlertNotifier() interfaces.AlertNotifierInterface {
+	switch os.Getenv("ALERT_NOTIFIER") {
+	case "", "log":
+		return notifier.NewLogNotifier()
+	case "webhook":
+		url := os.Getenv("ALERT_WEBHOOK_URL")
+		if url == "" {
+			log.Fatalf("ALERT_WEBHOOK_URL is not set")
+		}
+		return notifier.NewWebhookNotifier(url)
+	case "memory":
+		return notifier.NewMemoryNotifier()
+	default:
+		log.Fatalf("unknown ALERT_NOTIFIER %q", os.Getenv("ALERT_NOTIFIER"))
+		return nil
+	}
+}
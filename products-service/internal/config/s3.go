@@ -1,16 +1,30 @@
 package config
 
 import (
+	"os"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
-func InitS3Client() *s3.S3 {
+// InitS3Client crea un cliente de S3. Con endpoint vacío usa AWS; con uno propio (MinIO, por ejemplo)
+// usa direccionamiento por ruta, que es el que admiten los servicios compatibles. La región se lee
+// de AWS_REGION y las credenciales de la cadena habitual del SDK (AWS_ACCESS_KEY_ID, etc.).
+func InitS3Client(endpoint string) *s3.S3 {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	config := &aws.Config{Region: aws.String(region)}
+	if endpoint != "" {
+		config.Endpoint = aws.String(endpoint)
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+
 	//Crear una nueva sesion de AWS
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String("us-east-1"),
-	}))
+	sess := session.Must(session.NewSession(config))
 
 	return s3.New(sess)
 }
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/storage"
+)
+
+// LocalImagesPath es la ruta bajo la que el servicio sirve las imágenes del almacenamiento local.
+const LocalImagesPath = "/images"
+
+// NewImageStorage crea el almacenamiento de imágenes según IMAGE_STORAGE:
+//
+//	local (por defecto) un directorio, IMAGE_STORAGE_DIR ("uploads" por defecto), que el propio servicio sirve
+//	s3                  el bucket S3_BUCKET mediante el cliente de InitS3Client
+//
+// Con S3_ENDPOINT se usa un servicio compatible como MinIO. S3_PUBLIC_ENDPOINT, si es distinto, es el
+// endpoint con que los clientes alcanzan ese servicio y el que se firma en las URLs de subida directa.
+// IMAGE_BASE_URL es la URL pública de las imágenes; en modo local debe apuntar a LocalImagesPath
+// del servicio. Retorna también el directorio a servir, vacío si no es local.
+func NewImageStorage() (interfaces.ImageStorageInterface, string) {
+	baseURL := os.Getenv("IMAGE_BASE_URL")
+
+	switch backend := os.Getenv("IMAGE_STORAGE"); backend {
+	case "", "local":
+		dir := os.Getenv("IMAGE_STORAGE_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		if baseURL == "" {
+			baseURL = LocalImagesPath
+		}
+		return storage.NewLocalStorage(dir, baseURL), dir
+	case "s3":
+		bucket := os.Getenv("S3_BUCKET")
+		if bucket == "" {
+			log.Fatalf("S3_BUCKET is not set")
+		}
+		client := InitS3Client(os.Getenv("S3_ENDPOINT"))
+		presigner := client
+		if endpoint := os.Getenv("S3_PUBLIC_ENDPOINT"); endpoint != "" {
+			presigner = InitS3Client(endpoint)
+		}
+		return storage.NewS3Storage(client, presigner, bucket, baseURL), ""
+	default:
+		log.Fatalf("invalid IMAGE_STORAGE: %q", backend)
+		return nil, ""
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+)
+
+// TrashRetention lee de TRASH_RETENTION (una duración como "720h") cuánto tiempo permanece un producto
+// en la papelera antes de purgarse; vacía conserva el valor por defecto de 30 días.
+func TrashRetention() time.Duration {
+	value := os.Getenv("TRASH_RETENTION")
+	if value == "" {
+		return service.DefaultTrashRetention
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid TRASH_RETENTION: %q", value)
+	}
+	return d
+}
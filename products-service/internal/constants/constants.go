@@ -1,3 +1,55 @@
 package constants
 
 var AllowCategories = []string{"electronics", "clothing", "home", "beauty", "books", "toys", "games", "sports", "automotive", "health"}
+
+// Roles emitidos por auth-service en el claim "role".
+const (
+	RoleAdmin            = "admin"
+	RoleInventoryManager = "inventory-manager"
+	RoleCustomer         = "customer"
+)
+
+// ReorderRule define cuándo reponer un producto: al llegar a Point unidades disponibles se piden Quantity.
+type ReorderRule struct {
+	Point    uint
+	Quantity uint
+}
+
+// CategoryReorderRules son los puntos de reorden con los que se crean las categorías de AllowCategories.
+// Luego cada categoría guarda el suyo y un producto puede sobrescribirlo con sus propios
+// reorder_point y reorder_quantity.
+var CategoryReorderRules = map[string]ReorderRule{
+	"electronics": {Point: 5, Quantity: 20},
+	"clothing":    {Point: 10, Quantity: 50},
+	"home":        {Point: 5, Quantity: 25},
+	"beauty":      {Point: 10, Quantity: 40},
+	"books":       {Point: 3, Quantity: 15},
+	"toys":        {Point: 5, Quantity: 30},
+	"games":       {Point: 5, Quantity: 20},
+	"sports":      {Point: 5, Quantity: 25},
+	"automotive":  {Point: 2, Quantity: 10},
+	"health":      {Point: 10, Quantity: 40},
+}
+
+// MaxImageSize es el tamaño máximo en bytes de una imagen de producto.
+const MaxImageSize = 5 << 20
+
+// MaxImportSize es el tamaño máximo en bytes de un archivo de importación de productos.
+const MaxImportSize = 20 << 20
+
+// ImageExtensions son los tipos de imagen aceptados y la extensión con que se guardan.
+var ImageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// MaxDirectImageSize es el tamaño máximo en bytes de una imagen subida directamente al almacenamiento.
+const MaxDirectImageSize = 20 << 20
+
+// ThumbnailSize es el lado en píxeles del cuadrado en que caben las miniaturas.
+const ThumbnailSize = 320
+
+// MaxBulkProducts es la cantidad máxima de productos que puede afectar una operación masiva.
+const MaxBulkProducts = 10000
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLowStockAlerts maneja la solicitud para listar los productos con stock bajo.
+// @Summary List low-stock products
+// @Description List products whose available stock is at or below their reorder point (product override or category default), lowest stock first
+// @Tags alerts
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Success 200 {array} models.LowStockAlert
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Router /alerts/low-stock [get]
+func (ctrl *ProductController) GetLowStockAlerts(c *gin.Context) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alerts, err := ctrl.service.GetLowStockAlerts(c.Request.Context(), page, size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
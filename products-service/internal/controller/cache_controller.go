@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// GetCacheStats maneja la solicitud para consultar el estado y los contadores del cache de productos.
+// @Summary Cache statistics
+// @Description Circuit breaker state (closed, open, half-open) and hit, miss, stale, coalesced, refresh and error counters of the product cache, per key family (product, list), since the service started
+// @Tags cache
+// @Produce json
+// @Success 200 {object} models.CacheStatus
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Router /cache/stats [get]
+func (ctrl *ProductController) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, ctrl.service.CacheStatus())
+}
+
+// Health maneja la solicitud de estado del servicio. El servicio sigue atendiendo sin cache,
+// así que un cache caído se reporta como degradado sin cambiar el código HTTP.
+// @Summary Health check
+// @Description Service health; status is degraded while the cache circuit is not closed
+// @Tags cache
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health [get]
+func (ctrl *ProductController) Health(c *gin.Context) {
+	state := ctrl.service.CacheStatus().State
+
+	status := "ok"
+	if state == models.CacheStateOpen || state == models.CacheStateHalfOpen {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": status, "cache": state})
+}
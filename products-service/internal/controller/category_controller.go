@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+)
+
+// CategoryController maneja las solicitudes relacionadas con categorías.
+type CategoryController struct {
+	service *service.CategoryService
+}
+
+// NewCategoryController crea una nueva instancia de CategoryController.
+func NewCategoryController(service *service.CategoryService) *CategoryController {
+	return &CategoryController{service: service}
+}
+
+// GetCategories maneja la solicitud para listar las categorías.
+// @Summary List categories
+// @Description List all categories ordered by slug, or as a tree of root categories with their subcategories
+// @Tags categories
+// @Produce json
+// @Param tree query bool false "Return the categories as a tree"
+// @Success 200 {array} models.Category
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
+// @Router /categories [get]
+func (ctrl *CategoryController) GetCategories(c *gin.Context) {
+	if c.Query("tree") == "true" {
+		tree, err := ctrl.service.GetCategoryTree(c.Request.Context())
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, tree)
+		return
+	}
+
+	categories, err := ctrl.service.GetAllCategories(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// GetCategory maneja la solicitud para obtener una categoría por slug.
+// @Summary Get a category
+// @Description Retrieve a category by its slug
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} models.Category
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Security BearerAuth
+// @Router /categories/{slug} [get]
+func (ctrl *CategoryController) GetCategory(c *gin.Context) {
+	category, err := ctrl.service.GetOneCategory(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// PostCategory maneja la solicitud para crear una categoría.
+// @Summary Create category
+// @Description Create a category; the slug is derived from the name when omitted and parent is an optional category slug. reorder_point and reorder_quantity are the default reorder rule of its products (0 disables their low stock alerts)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body models.Category true "Category data"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Duplicate slug"
+// @Security BearerAuth
+// @Router /categories [post]
+func (ctrl *CategoryController) PostCategory(c *gin.Context) {
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := ctrl.service.CreateCategory(c.Request.Context(), category)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateCategory maneja la solicitud para modificar una categoría.
+// @Summary Update a category
+// @Description Update a category's name, parent, reorder_point or reorder_quantity; a null or empty parent makes it a root category and a reorder_point of 0 disables the low stock alerts of products without their own rule
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param updates body map[string]interface{} true "Category fields to update"
+// @Success 200 {object} string "updated category"
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Security BearerAuth
+// @Router /categories/{slug} [patch]
+func (ctrl *CategoryController) UpdateCategory(c *gin.Context) {
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := ctrl.service.UpdateCategory(c.Request.Context(), c.Param("slug"), updates); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "updated category")
+}
+
+// DeleteCategory maneja la solicitud para eliminar una categoría.
+// @Summary Delete a category
+// @Description Delete a category without subcategories; refused while it has products unless reassign_to names the category they move to
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param reassign_to query string false "Category slug that receives the products"
+// @Success 200 {object} string "deleted category"
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 409 {object} map[string]string "Category still has products or subcategories"
+// @Security BearerAuth
+// @Router /categories/{slug} [delete]
+func (ctrl *CategoryController) DeleteCategory(c *gin.Context) {
+	if err := ctrl.service.DeleteCategory(c.Request.Context(), c.Param("slug"), c.Query("reassign_to")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "deleted category")
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// errorStatus traduce los errores de dominio a su código HTTP; el resto se reporta como 400.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrReservationNotFound),
+		errors.Is(err, models.ErrWarehouseNotFound), errors.Is(err, models.ErrImageNotFound),
+		errors.Is(err, models.ErrReviewNotFound), errors.Is(err, models.ErrCategoryNotFound),
+		errors.Is(err, models.ErrVariantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInsufficientStock), errors.Is(err, models.ErrReservationClosed),
+		errors.Is(err, models.ErrWarehouseInUse), errors.Is(err, models.ErrDuplicateWarehouse),
+		errors.Is(err, models.ErrDuplicateReview), errors.Is(err, models.ErrDuplicateCategory),
+		errors.Is(err, models.ErrCategoryInUse), errors.Is(err, models.ErrCategoryHasChildren),
+		errors.Is(err, models.ErrDuplicateSKU), errors.Is(err, models.ErrDuplicateBarcode), errors.Is(err, models.ErrDuplicateImage),
+		errors.Is(err, models.ErrVariantReserved), errors.Is(err, models.ErrProductHasStock),
+		errors.Is(err, models.ErrProductReserved):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, models.ErrUnsupportedImage), errors.Is(err, models.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, models.ErrPresignUnsupported):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusBadRequest
+	}
+}
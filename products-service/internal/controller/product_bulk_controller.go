@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// BulkProducts maneja la solicitud para actualizar o eliminar varios productos a la vez.
+// @Summary Bulk update or delete products
+// @Description Update or delete, in a single write, the products given by ids or matching a filter with the listing criteria (up to 10000 products). Send either an update document, with the same fields as PATCH /products/{user_id} except sku and barcodes and with an explicit price currency, or delete: true to move them to the trash. Products with variants whose price currency would change, and products with open reservations when deleting, are skipped.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param request body models.BulkRequest true "Products and operation"
+// @Success 200 {object} models.BulkResult
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Router /products/bulk [post]
+func (ctrl *ProductController) BulkProducts(c *gin.Context) {
+	var request models.BulkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := ctrl.service.BulkProducts(c.Request.Context(), request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/xlsx"
+)
+
+// exportContentTypes son los tipos de contenido de cada formato de exportación.
+var exportContentTypes = map[string]string{
+	models.ProductFormatCSV:    "text/csv; charset=utf-8",
+	models.ProductFormatNDJSON: "application/x-ndjson",
+	models.ProductFormatXLSX:   xlsx.ContentType,
+}
+
+// ExportProducts maneja la solicitud para descargar el catálogo.
+// @Summary Export products
+// @Description Download every product matching the listing filters as CSV, NDJSON or XLSX, streamed as it is read. Besides the product fields, each row has reserved and stock_value, the available stock times the price (the sum over variants for products with variants) in minor units of the product currency, left empty when it does not fit in a 64-bit integer. CSV text cells starting with =, +, - or @ are prefixed with an apostrophe so spreadsheets do not run them as formulas; a CSV export can be imported back.
+// @Tags products
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv, ndjson or xlsx" default(csv)
+// @Param q query string false "Text search on title and description"
+// @Param category query string false "Category"
+// @Param attr.size query string false "Variant attribute, e.g. attr.size=M; any attr.<name> is accepted and all must match the same variant"
+// @Param min_price query int false "Minimum price in minor units of the currency, e.g. cents"
+// @Param max_price query int false "Maximum price in minor units of the currency, e.g. cents"
+// @Param currency query string false "Only products priced in this ISO 4217 currency"
+// @Param in_stock query bool false "Only products with available stock"
+// @Param min_rating query number false "Minimum average rating (0-5)"
+// @Param sort query string false "Sort by price, stock, rating or created; prefix with - for descending"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 415 {object} map[string]string "Unsupported file format"
+// @Security BearerAuth
+// @Router /products/export [get]
+func (ctrl *ProductController) ExportProducts(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", models.ProductFormatCSV))
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("%v: %q, use csv, ndjson or xlsx", models.ErrUnsupportedFormat, format)})
+		return
+	}
+
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products-%s.%s"`, time.Now().UTC().Format("2006-01-02"), format))
+	if err := ctrl.service.ExportProducts(c.Request.Context(), c.Writer, format, filter); err != nil {
+		// Una vez enviada parte del archivo ya no se puede cambiar la respuesta.
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("product export failed after sending part of the file: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// multipartOverhead es el margen sobre MaxImageSize que se admite para los encabezados del formulario.
+const multipartOverhead = 1 << 20
+
+// UploadProductImage maneja la solicitud para subir una imagen de un producto.
+// @Summary Upload product image
+// @Description Upload a JPEG, PNG, GIF or WebP image (up to 5 MiB) as the multipart field "image"; the type is detected from the content. A thumbnail is generated in the background. Use direct uploads for larger images.
+// @Tags images
+// @Accept multipart/form-data
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param image formData file true "Image file"
+// @Success 201 {object} models.ProductImage
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Security BearerAuth
+// @Router /products/{user_id}/images [post]
+func (ctrl *ProductController) UploadProductImage(c *gin.Context) {
+	// Corta la lectura del cuerpo en cuanto supera el tamaño admitido.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxImageSize+multipartOverhead)
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("image is too large: maximum size is %d bytes", constants.MaxImageSize)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing image file"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	image, err := ctrl.service.AddProductImage(c.Request.Context(), c.Param("user_id"), file, header.Size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+// CreateImageUpload maneja la solicitud de una URL para subir una imagen directamente al almacenamiento.
+// @Summary Create direct image upload
+// @Description Returns a pre-signed URL to PUT a JPEG, PNG, GIF or WebP image (up to 20 MiB) straight to S3, bypassing the service. Send the returned headers with the upload, then confirm it with the returned key. Only available with S3 storage.
+// @Tags images
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param upload body models.ImageUploadRequest true "Image type and size"
+// @Success 201 {object} models.ImageUpload
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Failure 501 {object} map[string]string "Storage does not support direct uploads"
+// @Security BearerAuth
+// @Router /products/{user_id}/images/uploads [post]
+func (ctrl *ProductController) CreateImageUpload(c *gin.Context) {
+	var request models.ImageUploadRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	upload, err := ctrl.service.CreateImageUpload(c.Request.Context(), c.Param("user_id"), request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// ConfirmImageUpload maneja la solicitud para asociar al producto una imagen subida directamente.
+// @Summary Confirm direct image upload
+// @Description Verifies the uploaded object (size and detected type) and adds it to the product's images; an invalid object is deleted. Confirming the same key again returns the same image.
+// @Tags images
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param confirmation body models.ImageUploadConfirmation true "Upload key"
+// @Success 201 {object} models.ProductImage
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found or object not uploaded"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Security BearerAuth
+// @Router /products/{user_id}/images/confirm [post]
+func (ctrl *ProductController) ConfirmImageUpload(c *gin.Context) {
+	var confirmation models.ImageUploadConfirmation
+	if err := c.ShouldBindJSON(&confirmation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	image, err := ctrl.service.ConfirmImageUpload(c.Request.Context(), c.Param("user_id"), confirmation.Key)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+// DeleteProductImage maneja la solicitud para eliminar una imagen de un producto.
+// @Summary Delete product image
+// @Description Remove an image from a product and delete it from storage
+// @Tags images
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} string
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or image not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/images/{image_id} [delete]
+func (ctrl *ProductController) DeleteProductImage(c *gin.Context) {
+	if err := ctrl.service.DeleteProductImage(c.Request.Context(), c.Param("user_id"), c.Param("image_id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "deleted image")
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// importFormats asocia las extensiones y los tipos de contenido admitidos a su formato de importación.
+var importFormats = map[string]string{
+	".csv":                 models.ProductFormatCSV,
+	".ndjson":              models.ProductFormatNDJSON,
+	".jsonl":               models.ProductFormatNDJSON,
+	"text/csv":             models.ProductFormatCSV,
+	"application/x-ndjson": models.ProductFormatNDJSON,
+	"application/jsonl":    models.ProductFormatNDJSON,
+}
+
+// ImportProducts maneja la solicitud para importar productos desde un archivo.
+// @Summary Import products
+// @Description Create or update products by SKU from a CSV or NDJSON file (up to 20 MiB), sent as the multipart field "file" or as the raw request body. Each row is validated like POST /products and the response reports every row as created, updated or failed. Existing products only get their catalogue fields replaced (title, description, category, price, barcodes and reorder rules); their stock and variants are left unchanged. CSV columns: sku, title, description, category, price_amount (minor units), price_currency, stock, barcodes (separated by |), reorder_point, reorder_quantity; the read-only id, reserved and stock_value columns of an export are ignored, and the apostrophe an export puts before a leading =, +, - or @ is removed.
+// @Tags products
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param file formData file false "CSV or NDJSON file"
+// @Param format query string false "csv or ndjson; by default taken from the file extension or the Content-Type"
+// @Param dry_run query bool false "Validate and report without writing"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 415 {object} map[string]string "Unsupported file format"
+// @Security BearerAuth
+// @Router /products/import [post]
+func (ctrl *ProductController) ImportProducts(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run parameter"})
+		return
+	}
+
+	// Corta la lectura del cuerpo en cuanto supera el tamaño admitido.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxImportSize+multipartOverhead)
+
+	var body io.Reader = c.Request.Body
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	format := importFormats[mediaType]
+	if strings.HasPrefix(mediaType, "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			importError(c, err, "missing import file")
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+
+		body = file
+		format = importFormats[strings.ToLower(filepath.Ext(header.Filename))]
+	}
+	if value := c.Query("format"); value != "" {
+		format = strings.ToLower(value)
+	}
+
+	report, err := ctrl.service.ImportProducts(c.Request.Context(), body, format, dryRun)
+	if err != nil {
+		importError(c, err, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// importError responde 413 si el archivo superó el tamaño admitido y, si no, el código del error.
+func importError(c *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file is too large: maximum size is %d bytes", constants.MaxImportSize)})
+		return
+	}
+	c.JSON(errorStatus(err), gin.H{"error": message})
+}
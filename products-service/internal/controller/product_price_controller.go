@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPriceHistory maneja la solicitud para consultar el historial de precios de un producto.
+// @Summary List price history
+// @Description List every price change of a product and its variants, newest first, with the user who made it
+// @Tags products
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Success 200 {array} models.PriceChange
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/price-history [get]
+func (ctrl *ProductController) GetPriceHistory(c *gin.Context) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes, err := ctrl.service.GetPriceHistory(c.Request.Context(), c.Param("user_id"), page, size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// IncrementStock maneja la solicitud para sumar unidades al stock de un producto.
+// @Summary Increment stock
+// @Description Atomically add units to a product's available stock, in a warehouse or unassigned when warehouse_id is empty; products with variants require variant_id
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param adjustment body models.StockAdjustment true "Units to add"
+// @Success 200 {object} models.StockLevel
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or warehouse not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/stock/increment [post]
+func (ctrl *ProductController) IncrementStock(c *gin.Context) {
+	var adjustment models.StockAdjustment
+	if err := c.ShouldBindJSON(&adjustment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := ctrl.service.IncrementStock(c.Request.Context(), c.Param("user_id"), adjustment.WarehouseID, adjustment.VariantID, adjustment.Quantity)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewStockLevel(product))
+}
+
+// DecrementStock maneja la solicitud para restar unidades del stock de un producto.
+// @Summary Decrement stock
+// @Description Atomically remove units from a product's available stock in a warehouse (or unassigned) or in a variant; never goes below zero
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param adjustment body models.StockAdjustment true "Units to remove"
+// @Success 200 {object} models.StockLevel
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or warehouse not found"
+// @Failure 409 {object} map[string]string "Insufficient stock"
+// @Security BearerAuth
+// @Router /products/{user_id}/stock/decrement [post]
+func (ctrl *ProductController) DecrementStock(c *gin.Context) {
+	var adjustment models.StockAdjustment
+	if err := c.ShouldBindJSON(&adjustment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := ctrl.service.DecrementStock(c.Request.Context(), c.Param("user_id"), adjustment.WarehouseID, adjustment.VariantID, adjustment.Quantity)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewStockLevel(product))
+}
+
+// TransferStock maneja la solicitud para mover stock entre almacenes.
+// @Summary Transfer stock
+// @Description Atomically move available units between two warehouses; an empty warehouse ID means unassigned stock
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param transfer body models.StockTransfer true "Units to move"
+// @Success 200 {object} models.StockLevel
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or warehouse not found"
+// @Failure 409 {object} map[string]string "Insufficient stock"
+// @Security BearerAuth
+// @Router /products/{user_id}/stock/transfer [post]
+func (ctrl *ProductController) TransferStock(c *gin.Context) {
+	var transfer models.StockTransfer
+	if err := c.ShouldBindJSON(&transfer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := ctrl.service.TransferStock(c.Request.Context(), c.Param("user_id"), transfer)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewStockLevel(product))
+}
+
+// ReserveStock maneja la solicitud para reservar unidades de un producto.
+// @Summary Reserve stock
+// @Description Move units from available to reserved stock for a limited time (ttl_seconds, default 900); products with variants require variant_id
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param reservation body models.ReservationRequest true "Units to reserve"
+// @Success 201 {object} models.ReservationResult
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Insufficient stock"
+// @Security BearerAuth
+// @Router /products/{user_id}/reservations [post]
+func (ctrl *ProductController) ReserveStock(c *gin.Context) {
+	var req models.ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	reservation, product, err := ctrl.service.ReserveStock(c.Request.Context(), c.Param("user_id"), req.WarehouseID, req.VariantID, req.Quantity, ttl)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ReservationResult{Reservation: *reservation, Stock: models.NewStockLevel(product)})
+}
+
+// CommitReservation maneja la solicitud para confirmar una reserva.
+// @Summary Commit reservation
+// @Description Confirm an active reservation, removing its units from the inventory
+// @Tags stock
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param reservation_id path string true "Reservation ID"
+// @Success 200 {object} models.StockLevel
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Reservation not found"
+// @Failure 409 {object} map[string]string "Reservation is no longer active"
+// @Security BearerAuth
+// @Router /products/{user_id}/reservations/{reservation_id}/commit [post]
+func (ctrl *ProductController) CommitReservation(c *gin.Context) {
+	product, err := ctrl.service.CommitReservation(c.Request.Context(), c.Param("user_id"), c.Param("reservation_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewStockLevel(product))
+}
+
+// ReleaseReservation maneja la solicitud para cancelar una reserva.
+// @Summary Release reservation
+// @Description Cancel an active reservation, returning its units to the available stock
+// @Tags stock
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param reservation_id path string true "Reservation ID"
+// @Success 200 {object} models.StockLevel
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Reservation not found"
+// @Failure 409 {object} map[string]string "Reservation is no longer active"
+// @Security BearerAuth
+// @Router /products/{user_id}/reservations/{reservation_id} [delete]
+func (ctrl *ProductController) ReleaseReservation(c *gin.Context) {
+	product, err := ctrl.service.ReleaseReservation(c.Request.Context(), c.Param("user_id"), c.Param("reservation_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewStockLevel(product))
+}
+
+// GetMovements maneja la solicitud para consultar el historial de inventario de un producto.
+// @Summary List stock movements
+// @Description List a product's inventory movements, newest first, optionally between two dates (RFC 3339 or YYYY-MM-DD)
+// @Tags stock
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Param from query string false "Only movements at or after this date"
+// @Param to query string false "Only movements up to this date"
+// @Success 200 {array} models.Movement
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Router /products/{user_id}/movements [get]
+func (ctrl *ProductController) GetMovements(c *gin.Context) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var filter models.MovementFilter
+	if filter.From, err = parseTimeParam(c, "from", false); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.To, err = parseTimeParam(c, "to", true); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movements, err := ctrl.service.GetMovements(c.Request.Context(), c.Param("user_id"), filter, page, size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
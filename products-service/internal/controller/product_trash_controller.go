@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash maneja la solicitud para listar los productos de la papelera.
+// @Summary List deleted products
+// @Description List the products in the trash, most recently deleted first; they are purged once the retention window passes
+// @Tags products
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Success 200 {array} models.Product
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Security BearerAuth
+// @Router /products/trash [get]
+func (ctrl *ProductController) GetTrash(c *gin.Context) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, err := ctrl.service.GetTrash(c.Request.Context(), page, size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// RestoreProduct maneja la solicitud para sacar un producto de la papelera.
+// @Summary Restore a deleted product
+// @Description Restore a product from the trash with its reviews, stock and history intact
+// @Tags products
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found in the trash"
+// @Security BearerAuth
+// @Router /products/{user_id}/restore [post]
+func (ctrl *ProductController) RestoreProduct(c *gin.Context) {
+	product, err := ctrl.service.RestoreProduct(c.Request.Context(), c.Param("user_id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// PostVariant maneja la solicitud para agregar una variante a un producto.
+// @Summary Add a product variant
+// @Description Add a variant with its own SKU, attributes, price and initial stock; the product stock becomes the sum of its variants
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param variant body models.Variant true "Variant data"
+// @Success 201 {object} models.Product
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Duplicate SKU or product stock outside its variants"
+// @Security BearerAuth
+// @Router /products/{user_id}/variants [post]
+func (ctrl *ProductController) PostVariant(c *gin.Context) {
+	var variant models.Variant
+	if err := c.ShouldBindJSON(&variant); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := ctrl.service.AddVariant(c.Request.Context(), c.Param("user_id"), variant)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, product)
+}
+
+// UpdateVariant maneja la solicitud para modificar una variante.
+// @Summary Update a product variant
+// @Description Update a variant's SKU, attributes or price; its stock changes through the stock endpoints
+// @Tags variants
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param variant_id path string true "Variant ID"
+// @Param updates body map[string]interface{} true "Variant fields to update"
+// @Success 200 {object} string "updated variant"
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or variant not found"
+// @Failure 409 {object} map[string]string "Duplicate SKU"
+// @Security BearerAuth
+// @Router /products/{user_id}/variants/{variant_id} [patch]
+func (ctrl *ProductController) UpdateVariant(c *gin.Context) {
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := ctrl.service.UpdateVariant(c.Request.Context(), c.Param("user_id"), c.Param("variant_id"), updates); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "updated variant")
+}
+
+// DeleteVariant maneja la solicitud para eliminar una variante.
+// @Summary Delete a product variant
+// @Description Delete a variant without reserved units; its available stock leaves the inventory
+// @Tags variants
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param variant_id path string true "Variant ID"
+// @Success 200 {object} string "deleted variant"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product or variant not found"
+// @Failure 409 {object} map[string]string "Variant has reserved units"
+// @Security BearerAuth
+// @Router /products/{user_id}/variants/{variant_id} [delete]
+func (ctrl *ProductController) DeleteVariant(c *gin.Context) {
+	if err := ctrl.service.DeleteVariant(c.Request.Context(), c.Param("user_id"), c.Param("variant_id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "deleted variant")
+}
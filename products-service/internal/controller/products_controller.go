@@ -2,7 +2,6 @@ package controller
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
@@ -11,51 +10,65 @@ import (
 
 // ProductController maneja las solicitudes relacionadas con productos.
 type ProductController struct {
-	service service.ProductService // Servicio para manejar la lógica de negocio de productos
+	service *service.ProductService // Servicio para manejar la lógica de negocio de productos
 }
 
 // NewProductController crea una nueva instancia de ProductController.
 func NewProductController(service *service.ProductService) *ProductController {
-	return &ProductController{service: *service}
+	return &ProductController{service: service}
 }
 
-// GetProduct maneja la solicitud para obtener un producto específico por ID.
-// @Summary Get a product
-// @Description Retrieve a product by user_id from the database
+// GetProducts maneja la solicitud para obtener los productos paginados.
+// @Summary Get all products
+// @Description Search, filter, sort and page through products. Pass cursor (empty for the first page) to page by ID instead of page number; sort is not available in cursor mode.
 // @Tags products
 // @Accept json
 // @Produce json
-// @Param user_id path string true "User ID" example("12345")
-// @Success 200 {object} models.Product "Product data"
-// @Failure 400 {object} map[string]string "Error description"
-// @Failure 404 {object} map[string]string "Product not found"
-// @Router /products/{user_id} [get]
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Param cursor query string false "ID of the last product received; enables cursor mode"
+// @Param q query string false "Text search on title and description"
+// @Param category query string false "Category"
+// @Param attr.size query string false "Variant attribute, e.g. attr.size=M; any attr.<name> is accepted and all must match the same variant"
+// @Param min_price query int false "Minimum price in minor units of the currency, e.g. cents"
+// @Param max_price query int false "Maximum price in minor units of the currency, e.g. cents"
+// @Param currency query string false "Only products priced in this ISO 4217 currency"
+// @Param in_stock query bool false "Only products with available stock"
+// @Param min_rating query number false "Minimum average rating (0-5)"
+// @Param sort query string false "Sort by price, stock, rating or created; prefix with - for descending"
+// @Success 200 {object} models.ProductPage
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
+// @Router /products [get]
 func (ctrl *ProductController) GetProducts(c *gin.Context) {
-	page := c.DefaultQuery("page", "1")      // Si no se pasa el parámetro, usa 1
-	pageSize := c.DefaultQuery("size", "10") // Si no se pasa el parámetro, usa 10
-
-	// Convierte los parámetros a enteros
-	pageInt, err := strconv.Atoi(page)
-	if err != nil || pageInt < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page parameter"})
+	// Lee page, size y cursor; si no se pasan, usa la página 1 con 10 productos
+	pageRequest, err := parsePageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	pageSizeInt, err := strconv.Atoi(pageSize)
-	if err != nil || pageSizeInt < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size parameter"})
+	// Lee los criterios de búsqueda, filtrado y orden
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Llama al servicio para obtener todos los productos
-	products, err := ctrl.service.GetAllProducts(c.Request.Context(), pageInt, pageSizeInt)
+	// Llama al servicio para obtener los productos
+	result, err := ctrl.service.GetAllProducts(c.Request.Context(), filter, pageRequest)
 	if err != nil {
 		// Retorna un error si ocurre al obtener productos
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, products)
+	// Los enlaces dependen de la URL de la solicitud, así que no se guardan en caché.
+	page := *result
+	page.Next, page.Prev = pageLinks(c, result, pageRequest)
+
+	c.JSON(http.StatusOK, page)
 }
 
 // GetProduct maneja la solicitud para obtener un producto específico por ID.
@@ -67,6 +80,8 @@ func (ctrl *ProductController) GetProducts(c *gin.Context) {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} models.Product
 // @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
 // @Router /products/{user_id} [get]
 func (ctr *ProductController) GetProduct(c *gin.Context) {
 	// Llama al servicio para obtener un producto por ID
@@ -80,15 +95,63 @@ func (ctr *ProductController) GetProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
+// GetProductBySKU maneja la solicitud para obtener un producto por su SKU.
+// @Summary Get a product by SKU
+// @Description Retrieve a product by its SKU; the lookup is case-insensitive
+// @Tags products
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string "Invalid SKU"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Security BearerAuth
+// @Router /products/by-sku/{sku} [get]
+func (ctrl *ProductController) GetProductBySKU(c *gin.Context) {
+	product, err := ctrl.service.GetProductBySKU(c.Request.Context(), c.Param("sku"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// GetProductByBarcode maneja la solicitud para obtener un producto por su código de barras.
+// @Summary Get a product by barcode
+// @Description Retrieve a product by one of its EAN-13 or UPC-A barcodes
+// @Tags products
+// @Produce json
+// @Param code path string true "EAN-13 or UPC-A barcode"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} map[string]string "Invalid barcode"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Security BearerAuth
+// @Router /products/by-barcode/{code} [get]
+func (ctrl *ProductController) GetProductByBarcode(c *gin.Context) {
+	product, err := ctrl.service.GetProductByBarcode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
 // PostProduct maneja la solicitud para crear un nuevo producto.
 // @Summary Create product
-// @Description Create a new product in MongoDB
+// @Description Create a new product in MongoDB; the SKU is required and unique, barcodes are optional EAN-13 or UPC-A codes, and the price is an amount in minor units with an ISO 4217 currency
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param product body models.Product true "Product Data"
 // @Success 200 {object} string
 // @Failure 400 {object} error "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Duplicate SKU or barcode"
+// @Security BearerAuth
 // @Router /products [post]
 func (ctrl *ProductController) PostProduct(c *gin.Context) {
 	var product models.Product
@@ -109,7 +172,7 @@ func (ctrl *ProductController) PostProduct(c *gin.Context) {
 	err := ctrl.service.CreateProduct(c.Request.Context(), product)
 	if err != nil {
 		// Retorna un error si ocurre al crear el producto
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -118,19 +181,23 @@ func (ctrl *ProductController) PostProduct(c *gin.Context) {
 
 // DeleteProduct maneja la solicitud para borrar un producto.
 // @Summary Delete a product
-// @Description Delete a product by its user_id
+// @Description Move a product to the trash by its user_id; it can be restored until the trash retention window purges it. Products with open reservations cannot be deleted
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param user_id path string true "User ID"
 // @Success 200 {object} string "deleted product"
 // @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Product has open reservations"
+// @Security BearerAuth
 // @Router /products/{user_id} [delete]
 func (ctrl *ProductController) DeleteProduct(c *gin.Context) {
-	// Llama al servicio para borrar el producto utilizando el user_id del parámetro de la URL
+	// Llama al servicio para enviar el producto a la papelera utilizando el user_id del parámetro de la URL
 	if err := ctrl.service.DeleteProduct(c.Request.Context(), c.Param("user_id")); err != nil {
-		// Si ocurre un error al borrar, retorna un mensaje de error con el código 400
-		c.JSON(http.StatusBadRequest, err)
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -139,7 +206,7 @@ func (ctrl *ProductController) DeleteProduct(c *gin.Context) {
 
 // UpdateProduct maneja la solicitud para actualizar un producto.
 // @Summary Update a product
-// @Description Update a product's fields using its user_id
+// @Description Update a product's fields using its user_id; price is {"amount": <minor units>, "currency": "<ISO 4217>"} and the currency defaults to the current one
 // @Tags products
 // @Accept json
 // @Produce json
@@ -147,6 +214,10 @@ func (ctrl *ProductController) DeleteProduct(c *gin.Context) {
 // @Param updates body map[string]interface{} true "Product fields to update"
 // @Success 200 {object} string "updated product"
 // @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Duplicate SKU or barcode"
+// @Security BearerAuth
 // @Router /products/{user_id} [patch]
 func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
 	// Declara un mapa para almacenar los campos a actualizar enviados en el cuerpo de la solicitud
@@ -161,8 +232,8 @@ func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
 
 	// Llama al servicio para actualizar el producto utilizando el user_id y los campos proporcionados
 	if err := ctrl.service.UpdateProduct(c.Request.Context(), c.Param("user_id"), updates); err != nil {
-		// Si ocurre un error al actualizar, retorna un mensaje de error con el código 400
-		c.JSON(http.StatusBadRequest, err)
+		// Si ocurre un error al actualizar, retorna el mensaje con el código que corresponde al error
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
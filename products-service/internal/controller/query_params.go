@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxPageSize es el tamaño máximo de una página.
+const maxPageSize = 100
+
+// parsePagination lee los parámetros page y size, con valores por defecto 1 y 10. size no puede superar
+// maxPageSize y page se limita para que page*size, el desplazamiento de la página, no desborde un int.
+func parsePagination(c *gin.Context) (int, int, error) {
+	size, err := strconv.Atoi(c.DefaultQuery("size", "10"))
+	if err != nil || size < 1 || size > maxPageSize {
+		return 0, 0, errors.New("Invalid size parameter")
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 || page > math.MaxInt/size {
+		return 0, 0, errors.New("Invalid page parameter")
+	}
+
+	return page, size, nil
+}
+
+// parsePageRequest lee la paginación del listado. La presencia del parámetro cursor activa el modo
+// cursor: su valor es el ID del último producto recibido, o vacío para la primera página.
+func parsePageRequest(c *gin.Context) (models.PageRequest, error) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		return models.PageRequest{}, err
+	}
+
+	request := models.PageRequest{Page: page, Size: size}
+	if after, ok := c.GetQuery("cursor"); ok {
+		if after != "" && !primitive.IsValidObjectID(after) {
+			return request, errors.New("Invalid cursor parameter")
+		}
+		request.CursorMode = true
+		request.After = after
+	}
+	return request, nil
+}
+
+// pageLinks arma los enlaces a la página siguiente y anterior conservando el resto de parámetros de la solicitud.
+func pageLinks(c *gin.Context, result *models.ProductPage, request models.PageRequest) (next, prev *string) {
+	if request.CursorMode {
+		if result.NextCursor != "" {
+			next = queryLink(c, func(q url.Values) { q.Del("page"); q.Set("cursor", result.NextCursor) })
+		}
+		return next, nil
+	}
+	return offsetLinks(c, request.Page, request.Size, result.Total)
+}
+
+// offsetLinks arma los enlaces de una paginación por número de página.
+func offsetLinks(c *gin.Context, page, size int, total int64) (next, prev *string) {
+	// Se compara contra el número de páginas para no multiplicar page por size.
+	if size > 0 && int64(page) < (total+int64(size)-1)/int64(size) {
+		next = queryLink(c, func(q url.Values) { q.Set("page", strconv.Itoa(page+1)) })
+	}
+	if page > 1 {
+		prev = queryLink(c, func(q url.Values) { q.Set("page", strconv.Itoa(page-1)) })
+	}
+	return next, prev
+}
+
+// queryLink retorna la URL de la solicitud con los parámetros modificados por set.
+func queryLink(c *gin.Context, set func(q url.Values)) *string {
+	u := *c.Request.URL
+	q := u.Query()
+	set(q)
+	u.RawQuery = q.Encode()
+	s := u.RequestURI()
+	return &s
+}
+
+// parseTimeParam lee un parámetro de fecha en formato RFC 3339 o YYYY-MM-DD. Con endOfDay,
+// una fecha sin hora se interpreta como el final de ese día para que el rango la incluya.
+func parseTimeParam(c *gin.Context, name string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("Invalid " + name + " parameter")
+	}
+	if endOfDay {
+		t = t.Add(24 * time.Hour)
+	}
+	return &t, nil
+}
+
+// parseProductFilter lee los parámetros de búsqueda, filtrado y orden del listado de productos.
+func parseProductFilter(c *gin.Context) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Category: strings.ToLower(c.Query("category")),
+		Sort:     c.Query("sort"),
+	}
+
+	if filter.Category != "" && !utils.IsValidCategory(filter.Category) {
+		return filter, errors.New("Invalid category parameter")
+	}
+
+	var err error
+	if filter.MinPrice, err = parseUintParam(c, "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = parseUintParam(c, "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return filter, errors.New("min_price cannot be greater than max_price")
+	}
+	if value := c.Query("currency"); value != "" {
+		price, err := models.Money{Currency: value}.Normalize()
+		if err != nil {
+			return filter, errors.New("Invalid currency parameter")
+		}
+		filter.Currency = price.Currency
+	}
+
+	if value := c.Query("in_stock"); value != "" {
+		if filter.InStock, err = strconv.ParseBool(value); err != nil {
+			return filter, errors.New("Invalid in_stock parameter")
+		}
+	}
+
+	if value := c.Query("min_rating"); value != "" {
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil || rating < 0 || rating > 5 {
+			return filter, errors.New("Invalid min_rating parameter")
+		}
+		filter.MinRating = &rating
+	}
+
+	// Los atributos de variante llegan como attr.<nombre>=<valor>, por ejemplo attr.size=M.
+	for key, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(key, "attr.") || len(values) == 0 {
+			continue
+		}
+		name, value, err := utils.NormalizeAttribute(strings.TrimPrefix(key, "attr."), values[0])
+		if err != nil {
+			return filter, errors.New("Invalid " + key + " parameter")
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = map[string]string{}
+		}
+		filter.Attributes[name] = value
+	}
+
+	if filter.Sort != "" {
+		if _, ok := c.GetQuery("cursor"); ok {
+			return filter, errors.New("sort is not supported in cursor mode")
+		}
+		valid := false
+		for _, field := range models.ProductSortFields {
+			if strings.TrimPrefix(filter.Sort, "-") == field {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return filter, errors.New("Invalid sort parameter")
+		}
+	}
+
+	return filter, nil
+}
+
+// parseUintParam lee un parámetro entero no negativo opcional.
+func parseUintParam(c *gin.Context, name string) (*uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return nil, errors.New("Invalid " + name + " parameter")
+	}
+	u := uint(n)
+	return &u, nil
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testContext(target string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	tc := []struct {
+		Name          string
+		URL           string
+		ExpectedPage  int
+		ExpectedSize  int
+		ExpectedError string
+	}{
+		{Name: "Defaults", URL: "/products", ExpectedPage: 1, ExpectedSize: 10},
+		{Name: "Maximum size", URL: "/products?page=3&size=100", ExpectedPage: 3, ExpectedSize: 100},
+		{Name: "Size above the maximum", URL: "/products?size=1000000000", ExpectedError: "Invalid size parameter"},
+		{Name: "Zero size", URL: "/products?size=0", ExpectedError: "Invalid size parameter"},
+		{Name: "Invalid page", URL: "/products?page=0", ExpectedError: "Invalid page parameter"},
+		{Name: "Last page whose offset fits", URL: "/products?page=922337203685477580", ExpectedPage: 922337203685477580, ExpectedSize: 10},
+		{Name: "Page whose offset overflows", URL: "/products?page=922337203685477581", ExpectedError: "Invalid page parameter"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			page, size, err := parsePagination(testContext(tc.URL))
+			if tc.ExpectedError != "" {
+				if err == nil || err.Error() != tc.ExpectedError {
+					t.Fatalf("unexpected error: got %v want %v", err, tc.ExpectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page != tc.ExpectedPage || size != tc.ExpectedSize {
+				t.Fatalf("unexpected pagination: got %v/%v want %v/%v", page, size, tc.ExpectedPage, tc.ExpectedSize)
+			}
+		})
+	}
+}
+
+func TestOffsetLinks(t *testing.T) {
+	tc := []struct {
+		Name         string
+		Page         int
+		Size         int
+		Total        int64
+		ExpectedNext string
+		ExpectedPrev string
+	}{
+		{Name: "First page", Page: 1, Size: 10, Total: 25, ExpectedNext: "/products?page=2"},
+		{Name: "Middle page", Page: 2, Size: 10, Total: 25, ExpectedNext: "/products?page=3", ExpectedPrev: "/products?page=1"},
+		{Name: "Exact last page", Page: 2, Size: 10, Total: 20, ExpectedPrev: "/products?page=1"},
+		{Name: "Page beyond the total", Page: 922337203685477580, Size: 10, Total: 25, ExpectedPrev: "/products?page=922337203685477579"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			next, prev := offsetLinks(testContext("/products"), tc.Page, tc.Size, tc.Total)
+			if got := deref(next); got != tc.ExpectedNext {
+				t.Fatalf("unexpected next link: got %v want %v", got, tc.ExpectedNext)
+			}
+			if got := deref(prev); got != tc.ExpectedPrev {
+				t.Fatalf("unexpected prev link: got %v want %v", got, tc.ExpectedPrev)
+			}
+		})
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
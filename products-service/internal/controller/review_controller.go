@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+)
+
+// ReviewController maneja las solicitudes relacionadas con reseñas de productos.
+type ReviewController struct {
+	service *service.ReviewService
+}
+
+// NewReviewController crea una nueva instancia de ReviewController.
+func NewReviewController(service *service.ReviewService) *ReviewController {
+	return &ReviewController{service: service}
+}
+
+// GetReviews maneja la solicitud para listar las reseñas de un producto.
+// @Summary Get product reviews
+// @Description Visible reviews of a product, newest first. Moderators can pass include_hidden=true to also see hidden ones.
+// @Tags reviews
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10) maximum(100)
+// @Param include_hidden query bool false "Include hidden reviews (moderators only)"
+// @Success 200 {object} models.ReviewPage
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/reviews [get]
+func (ctrl *ReviewController) GetReviews(c *gin.Context) {
+	page, size, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := ctrl.service.GetReviews(c.Request.Context(), c.Param("user_id"), c.Query("include_hidden") == "true", page, size)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	result.Next, result.Prev = offsetLinks(c, page, size, result.Total)
+	c.JSON(http.StatusOK, result)
+}
+
+// PostReview maneja la solicitud para publicar una reseña de un producto.
+// @Summary Create review
+// @Description Publish the caller's review of a product; the author is taken from the token and each user can review a product once
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param review body models.ReviewRequest true "Rating (1-5) and text"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Already reviewed"
+// @Security BearerAuth
+// @Router /products/{user_id}/reviews [post]
+func (ctrl *ReviewController) PostReview(c *gin.Context) {
+	var request models.ReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := ctrl.service.CreateReview(c.Request.Context(), c.Param("user_id"), request)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// UpdateReview maneja la solicitud del autor para modificar su reseña.
+// @Summary Update review
+// @Description Change the rating or text of the caller's own review
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param review_id path string true "Review ID"
+// @Param review body models.ReviewUpdate true "Fields to change"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the author"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/reviews/{review_id} [patch]
+func (ctrl *ReviewController) UpdateReview(c *gin.Context) {
+	var update models.ReviewUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := ctrl.service.UpdateReview(c.Request.Context(), c.Param("user_id"), c.Param("review_id"), update)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// ModerateReview maneja la solicitud para ocultar una reseña o volver a mostrarla.
+// @Summary Moderate review
+// @Description Hide a review or make it visible again; hidden reviews do not count towards the product rating
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param review_id path string true "Review ID"
+// @Param moderation body models.ReviewModeration true "New status"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/reviews/{review_id}/status [put]
+func (ctrl *ReviewController) ModerateReview(c *gin.Context) {
+	var moderation models.ReviewModeration
+	if err := c.ShouldBindJSON(&moderation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := ctrl.service.ModerateReview(c.Request.Context(), c.Param("user_id"), c.Param("review_id"), moderation.Status)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview maneja la solicitud para eliminar una reseña.
+// @Summary Delete review
+// @Description Delete a review; allowed for its author and for moderators
+// @Tags reviews
+// @Produce json
+// @Param user_id path string true "Product ID"
+// @Param review_id path string true "Review ID"
+// @Success 200 {object} string
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Security BearerAuth
+// @Router /products/{user_id}/reviews/{review_id} [delete]
+func (ctrl *ReviewController) DeleteReview(c *gin.Context) {
+	if err := ctrl.service.DeleteReview(c.Request.Context(), c.Param("user_id"), c.Param("review_id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "deleted review")
+}
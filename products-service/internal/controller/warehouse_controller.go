@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/service"
+)
+
+// WarehouseController maneja las solicitudes relacionadas con almacenes.
+type WarehouseController struct {
+	service *service.WarehouseService
+}
+
+// NewWarehouseController crea una nueva instancia de WarehouseController.
+func NewWarehouseController(service *service.WarehouseService) *WarehouseController {
+	return &WarehouseController{service: service}
+}
+
+// GetWarehouses maneja la solicitud para listar los almacenes.
+// @Summary List warehouses
+// @Description List all warehouses ordered by code
+// @Tags warehouses
+// @Produce json
+// @Success 200 {array} models.Warehouse
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Security BearerAuth
+// @Router /warehouses [get]
+func (ctrl *WarehouseController) GetWarehouses(c *gin.Context) {
+	warehouses, err := ctrl.service.GetAllWarehouses(c.Request.Context())
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// GetWarehouse maneja la solicitud para obtener un almacén por ID.
+// @Summary Get a warehouse
+// @Description Retrieve a warehouse by its ID
+// @Tags warehouses
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} models.Warehouse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Warehouse not found"
+// @Security BearerAuth
+// @Router /warehouses/{id} [get]
+func (ctrl *WarehouseController) GetWarehouse(c *gin.Context) {
+	warehouse, err := ctrl.service.GetOneWarehouse(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// PostWarehouse maneja la solicitud para crear un almacén.
+// @Summary Create warehouse
+// @Description Create a warehouse; codes are unique and stored uppercase
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param warehouse body models.Warehouse true "Warehouse data"
+// @Success 201 {object} models.Warehouse
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Duplicate code"
+// @Security BearerAuth
+// @Router /warehouses [post]
+func (ctrl *WarehouseController) PostWarehouse(c *gin.Context) {
+	var warehouse models.Warehouse
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := ctrl.service.CreateWarehouse(c.Request.Context(), warehouse)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateWarehouse maneja la solicitud para modificar un almacén.
+// @Summary Update a warehouse
+// @Description Update a warehouse's code, name or address
+// @Tags warehouses
+// @Accept json
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Param updates body map[string]interface{} true "Warehouse fields to update"
+// @Success 200 {object} string "updated warehouse"
+// @Failure 400 {object} map[string]string "error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Warehouse not found"
+// @Failure 409 {object} map[string]string "Duplicate code"
+// @Security BearerAuth
+// @Router /warehouses/{id} [patch]
+func (ctrl *WarehouseController) UpdateWarehouse(c *gin.Context) {
+	var updates map[string]interface{}
+	if err := c.ShouldBindJSON(&updates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := ctrl.service.UpdateWarehouse(c.Request.Context(), c.Param("id"), updates); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "updated warehouse")
+}
+
+// DeleteWarehouse maneja la solicitud para eliminar un almacén.
+// @Summary Delete a warehouse
+// @Description Delete a warehouse; refused while any product still holds stock in it
+// @Tags warehouses
+// @Produce json
+// @Param id path string true "Warehouse ID"
+// @Success 200 {object} string "deleted warehouse"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Warehouse not found"
+// @Failure 409 {object} map[string]string "Warehouse still holds stock"
+// @Security BearerAuth
+// @Router /warehouses/{id} [delete]
+func (ctrl *WarehouseController) DeleteWarehouse(c *gin.Context) {
+	if err := ctrl.service.DeleteWarehouse(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, "deleted warehouse")
+}
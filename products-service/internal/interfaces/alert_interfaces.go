@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// AlertNotifierInterface define el destino de las alertas de stock bajo.
+type AlertNotifierInterface interface {
+	// NotifyLowStock envía una alerta de stock bajo.
+	NotifyLowStock(ctx context.Context, alert models.LowStockAlert) error
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// CategoryRepositoryInterface define los métodos para persistir categorías.
+type CategoryRepositoryInterface interface {
+	// FindAll retorna todas las categorías ordenadas por slug.
+	FindAll(ctx context.Context) ([]models.Category, error)
+	// FindOne busca una categoría por su slug.
+	FindOne(ctx context.Context, slug string) (*models.Category, error)
+	// Create inserta una categoría y la retorna con su ID.
+	Create(ctx context.Context, category models.Category) (*models.Category, error)
+	// Update modifica los campos indicados de una categoría.
+	Update(ctx context.Context, slug string, fields map[string]interface{}) error
+	// Delete elimina una categoría por su slug.
+	Delete(ctx context.Context, slug string) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// MovementRepositoryInterface define los métodos del historial de movimientos de inventario.
+type MovementRepositoryInterface interface {
+	// Create agrega un movimiento al historial.
+	Create(ctx context.Context, movement models.Movement) error
+	// FindByProduct retorna los movimientos de un producto, del más reciente al más antiguo, con paginación.
+	FindByProduct(ctx context.Context, productID string, filter models.MovementFilter, page, size int) ([]models.Movement, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// PriceHistoryRepositoryInterface define los métodos del historial de precios.
+type PriceHistoryRepositoryInterface interface {
+	// Create agrega un cambio de precio al historial.
+	Create(ctx context.Context, change models.PriceChange) error
+	// FindByProduct retorna los cambios de precio de un producto, del más reciente al más antiguo, con paginación.
+	FindByProduct(ctx context.Context, productID string, page, size int) ([]models.PriceChange, error)
+}
@@ -2,35 +2,110 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
 )
 
 // ProductMongoRepositoryInterface define los métodos para interactuar con productos en MongoDB.
 type ProductMongoRepositoryInterface interface {
-	// FindAll retorna una lista de todos los productos.
-	FindAll(ctx context.Context, page, size int) ([]models.Product, error)
+	// FindAll retorna los productos que cumplen el filtro, ordenados y paginados, y el total de coincidencias.
+	FindAll(ctx context.Context, filter models.ProductFilter, page models.PageRequest) ([]models.Product, int64, error)
+	// StreamAll llama a fn con cada producto que cumple el filtro, en orden, sin cargarlos todos en memoria.
+	StreamAll(ctx context.Context, filter models.ProductFilter, fn func(product *models.Product) error) error
 	// FindOne busca un producto por su ID y lo retorna.
 	FindOne(ctx context.Context, id string) (*models.Product, error)
-	// Create inserta un nuevo producto y retorna el resultado de la operación.
-	Create(ctx context.Context, product models.Product) error
-	// Delete Elimina un producto por su ID.
+	// FindBySKU busca un producto por su SKU normalizado.
+	FindBySKU(ctx context.Context, sku string) (*models.Product, error)
+	// FindByBarcode busca un producto por uno de sus códigos de barras en formato EAN-13.
+	FindByBarcode(ctx context.Context, code string) (*models.Product, error)
+	// FindBySKUs retorna los productos cuyo SKU es uno de los indicados, incluidos los de la papelera.
+	FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error)
+	// Create inserta un nuevo producto y retorna su ID.
+	Create(ctx context.Context, product models.Product) (string, error)
+	// UpsertBySKU crea o actualiza, según su SKU, los productos indicados en una sola escritura por lotes
+	// y retorna el resultado de cada uno en el mismo orden.
+	UpsertBySKU(ctx context.Context, products []models.Product) ([]models.ProductWrite, error)
+	// Delete envía un producto sin reservas abiertas a la papelera; las lecturas lo excluyen hasta que se restaure.
 	Delete(ctx context.Context, id string) error
+	// FindDeleted retorna, con paginación, los productos de la papelera.
+	FindDeleted(ctx context.Context, page, size int) ([]models.Product, error)
+	// Restore saca un producto de la papelera y lo retorna.
+	Restore(ctx context.Context, id string) (*models.Product, error)
+	// FindDeletedBefore retorna hasta limit productos sin unidades reservadas que entraron en la papelera
+	// antes de la fecha indicada.
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error)
+	// Purge elimina definitivamente un producto sin unidades reservadas que sigue en la papelera desde
+	// antes de la fecha indicada.
+	Purge(ctx context.Context, id string, before time.Time) error
 	// Exist Determina si un producto existe en la base de datos
 	Update(ctx context.Context, id string, product map[string]interface{}) error
+	// BulkUpdate asigna los campos indicados a todos los productos que cumplen el filtro en una sola escritura.
+	BulkUpdate(ctx context.Context, filter models.ProductFilter, fields map[string]interface{}) (*models.BulkResult, error)
+	// BulkDelete envía a la papelera los productos sin reservas abiertas que cumplen el filtro en una sola escritura.
+	BulkDelete(ctx context.Context, filter models.ProductFilter) (*models.BulkResult, error)
+	// ApplyStockChange ajusta atómicamente el stock disponible y reservado sin permitir valores negativos.
+	// Con warehouseID vacío el cambio se aplica al stock sin asignar a un almacén; en los productos
+	// con variantes se aplica a la variante variantID y a los totales del producto.
+	ApplyStockChange(ctx context.Context, id, warehouseID, variantID string, stockDelta, reservedDelta int64) (*models.Product, error)
+	// TransferStock mueve unidades disponibles entre dos almacenes del producto sin cambiar su total.
+	TransferStock(ctx context.Context, id, fromWarehouseID, toWarehouseID string, quantity int64) (*models.Product, error)
+	// CountByWarehouse cuenta los productos con unidades en el almacén indicado.
+	CountByWarehouse(ctx context.Context, warehouseID string) (int64, error)
+	// RemoveWarehouse elimina las ubicaciones del almacén indicado de todos los productos y retorna sus IDs.
+	RemoveWarehouse(ctx context.Context, warehouseID string) ([]string, error)
+	// CountByCategory cuenta los productos de la categoría indicada.
+	CountByCategory(ctx context.Context, category string) (int64, error)
+	// ReassignCategory mueve todos los productos de una categoría a otra y retorna cuántos cambiaron.
+	ReassignCategory(ctx context.Context, from, to string) (int64, error)
+	// FindLowStock retorna los productos cuyo stock disponible llegó a su punto de reorden.
+	FindLowStock(ctx context.Context, page, size int) ([]models.Product, error)
+	// AddVariant agrega una variante al producto y suma su stock al del producto.
+	AddVariant(ctx context.Context, id string, variant models.Variant) (*models.Product, error)
+	// UpdateVariant modifica los campos indicados de una variante.
+	UpdateVariant(ctx context.Context, id, variantID string, fields map[string]interface{}) error
+	// RemoveVariant quita una variante sin unidades reservadas y retorna la variante eliminada.
+	RemoveVariant(ctx context.Context, id, variantID string) (*models.Variant, error)
+	// AddImage agrega una imagen a las del producto; si ya tiene una con el mismo ID retorna models.ErrDuplicateImage.
+	AddImage(ctx context.Context, id string, image models.ProductImage) error
+	// RemoveImage quita una imagen del producto por su ID.
+	RemoveImage(ctx context.Context, id, imageID string) error
+	// SetImageThumbnail registra la miniatura de una imagen; una clave vacía indica que no se pudo generar.
+	SetImageThumbnail(ctx context.Context, id, imageID, key, url string) error
+	// FindPendingThumbnails retorna hasta limit productos con imágenes cuya miniatura está pendiente.
+	FindPendingThumbnails(ctx context.Context, limit int) ([]models.Product, error)
+	// ApplyRatingChange ajusta atómicamente el agregado de calificaciones del producto.
+	ApplyRatingChange(ctx context.Context, id string, sumDelta, countDelta int64) error
 }
 
 // ProductRedisRepositoryInterface define métodos para interactuar con un repositorio de productos en cache (Redis).
 type ProductRedisRepositoryInterface interface {
-	// GetAll recupera todos los productos del cache usando la clave especificada.
-	GetAll(ctx context.Context, key string) ([]models.Product, error)
+	// ListVersion retorna la versión vigente del cache, que aumenta con cada invalidación. Forma parte de la
+	// clave de cada página y condiciona la escritura de los productos.
+	ListVersion(ctx context.Context) (int64, error)
 
-	// GetOne recupera un producto específico del cache usando la clave proporcionada.
-	GetOne(ctx context.Context, key string) (*models.Product, error)
+	// GetPage recupera del cache una página de un listado de la versión indicada y el momento en que se guardó.
+	GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error)
 
-	// Set almacena un producto en el cache (Redis) bajo la clave especificada.
-	Set(ctx context.Context, key string, payload interface{}) error
+	// SetPage almacena en el cache una página de un listado de la versión indicada durante ttl.
+	SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error
 
-	// Clean elimina el cache existente
-	Clean(ctx context.Context) error
+	// GetOne recupera un producto específico del cache por su ID y el momento en que se guardó.
+	GetOne(ctx context.Context, id string) (*models.Product, time.Time, error)
+
+	// SetOne almacena un producto en el cache bajo su ID durante ttl, solo si la versión vigente sigue siendo
+	// version. Si hubo una invalidación desde que se leyó la versión no guarda nada y no es un error.
+	SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error
+
+	// InvalidateProducts elimina del cache los productos indicados e invalida todos los listados.
+	InvalidateProducts(ctx context.Context, ids ...string) error
+
+	// InvalidateAll elimina del cache todos los productos e invalida todos los listados.
+	InvalidateAll(ctx context.Context) error
+}
+
+// CacheHealthInterface lo implementan los caches que reportan su estado de salud.
+type CacheHealthInterface interface {
+	// State retorna el estado del cache, por ejemplo el de su circuit breaker.
+	State() string
 }
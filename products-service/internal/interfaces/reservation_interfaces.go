@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// ReservationRepositoryInterface define los métodos para persistir reservas de stock.
+type ReservationRepositoryInterface interface {
+	// Create inserta una reserva y la retorna con su ID.
+	Create(ctx context.Context, reservation models.Reservation) (*models.Reservation, error)
+	// Transition cambia el estado de una reserva del producto indicado solo si está en el estado from
+	// y la retorna ya actualizada; una reserva vencida no se puede confirmar.
+	Transition(ctx context.Context, productID, id, from, to string) (*models.Reservation, error)
+	// FindExpired retorna reservas activas cuya expiración es anterior a now.
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]models.Reservation, error)
+}
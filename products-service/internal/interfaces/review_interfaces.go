@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// ReviewRepositoryInterface define los métodos para persistir reseñas de productos. Las operaciones
+// que modifican una reseña retornan su estado anterior, para calcular el cambio en la calificación.
+type ReviewRepositoryInterface interface {
+	// Create inserta una reseña y la retorna con su ID; models.ErrDuplicateReview si el autor ya reseñó el producto.
+	Create(ctx context.Context, review models.Review) (*models.Review, error)
+	// FindOne busca una reseña de un producto por su ID.
+	FindOne(ctx context.Context, productID, id string) (*models.Review, error)
+	// FindByProduct retorna las reseñas de un producto, de la más reciente a la más antigua, y su total.
+	FindByProduct(ctx context.Context, productID string, includeHidden bool, page, size int) ([]models.Review, int64, error)
+	// Update aplica los cambios del autor y retorna la reseña como estaba antes.
+	Update(ctx context.Context, productID, id string, update models.ReviewUpdate) (*models.Review, error)
+	// SetStatus cambia el estado de moderación y retorna la reseña como estaba antes.
+	SetStatus(ctx context.Context, productID, id, status string) (*models.Review, error)
+	// Delete elimina una reseña y la retorna.
+	Delete(ctx context.Context, productID, id string) (*models.Review, error)
+	// DeleteByProduct elimina todas las reseñas de un producto y retorna cuántas eliminó.
+	DeleteByProduct(ctx context.Context, productID string) (int64, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ImageStorageInterface define el almacenamiento de objetos donde se guardan las imágenes de productos.
+type ImageStorageInterface interface {
+	// Put guarda el objeto bajo key y retorna la URL pública con que se sirve.
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) (string, error)
+	// Open abre el objeto para leerlo y retorna su tamaño; models.ErrImageNotFound si no existe.
+	Open(ctx context.Context, key string) (io.ReadCloser, int64, error)
+	// URL retorna la URL pública del objeto.
+	URL(key string) string
+	// Delete elimina el objeto; no falla si ya no existe.
+	Delete(ctx context.Context, key string) error
+}
+
+// ImagePresignerInterface lo implementan los almacenamientos que aceptan subidas directas del cliente.
+type ImagePresignerInterface interface {
+	// PresignPut retorna una URL que permite subir con PUT un objeto del tipo indicado bajo key hasta que pase ttl.
+	PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (string, error)
+}
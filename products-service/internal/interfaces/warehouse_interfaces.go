@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// WarehouseRepositoryInterface define los métodos para persistir almacenes.
+type WarehouseRepositoryInterface interface {
+	// FindAll retorna todos los almacenes ordenados por código.
+	FindAll(ctx context.Context) ([]models.Warehouse, error)
+	// FindOne busca un almacén por su ID.
+	FindOne(ctx context.Context, id string) (*models.Warehouse, error)
+	// Create inserta un almacén y lo retorna con su ID.
+	Create(ctx context.Context, warehouse models.Warehouse) (*models.Warehouse, error)
+	// Update modifica los campos indicados de un almacén.
+	Update(ctx context.Context, id string, fields map[string]interface{}) error
+	// Delete elimina un almacén por su ID.
+	Delete(ctx context.Context, id string) error
+}
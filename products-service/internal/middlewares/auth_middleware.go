@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+// ClaimsKey es la clave bajo la cual se guardan los claims del llamador en el contexto de gin.
+const ClaimsKey = "claims"
+
+// AuthMiddleware verifica el JWT del encabezado Authorization y guarda la identidad del llamador
+// tanto en el contexto de gin como en el contexto de la solicitud.
+func AuthMiddleware(keyFunc jwt.Keyfunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing authorization header"})
+			return
+		}
+
+		tokenString, err := utils.TokenSeparator(authHeader)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		claims, err := auth.ParseToken(tokenString, keyFunc)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Expone la identidad a los handlers y a las capas de servicio.
+		c.Set(ClaimsKey, claims)
+		c.Request = c.Request.WithContext(auth.ContextWithClaims(c.Request.Context(), claims))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+)
+
+// initJWKSServer publica la clave pública como lo hace auth-service.
+func initJWKSServer(t *testing.T, key ed25519.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	set := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "OKP", "kid": "ed", "alg": "EdDSA", "crv": "Ed25519",
+			"x": base64.RawURLEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signToken(t *testing.T, key ed25519.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = "ed"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("could not sign token: %v", err)
+	}
+	return signed
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	keyFunc := auth.NewJWKS(initJWKSServer(t, key).URL).KeyFunc
+	claims := func(role string, exp time.Duration) jwt.MapClaims {
+		return jwt.MapClaims{"sub": "email@example.com", "role": role, "exp": time.Now().Add(exp).Unix()}
+	}
+
+	tc := []struct {
+		Name           string
+		Header         string
+		ExpectedStatus int
+		ExpectedError  string
+	}{
+		{Name: "Manager allowed", Header: "Bearer " + signToken(t, key, claims(constants.RoleInventoryManager, time.Hour)), ExpectedStatus: http.StatusOK},
+		{Name: "Customer forbidden", Header: "Bearer " + signToken(t, key, claims(constants.RoleCustomer, time.Hour)), ExpectedStatus: http.StatusForbidden, ExpectedError: "insufficient permissions"},
+		{Name: "Missing header", ExpectedStatus: http.StatusUnauthorized, ExpectedError: "missing authorization header"},
+		{Name: "Expired token", Header: "Bearer " + signToken(t, key, claims(constants.RoleAdmin, -time.Hour)), ExpectedStatus: http.StatusUnauthorized, ExpectedError: "token expired"},
+		{Name: "Wrong key", Header: "Bearer " + signToken(t, other, claims(constants.RoleAdmin, time.Hour)), ExpectedStatus: http.StatusUnauthorized, ExpectedError: "invalid token"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/products", AuthMiddleware(keyFunc), RequireRoles(constants.RoleAdmin, constants.RoleInventoryManager), func(c *gin.Context) {
+				claims, ok := auth.ClaimsFromContext(c.Request.Context())
+				if !ok || claims.Subject != "email@example.com" {
+					t.Errorf("unexpected claims: got %+v", claims)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tc.ExpectedStatus {
+				t.Fatalf("unexpected status: got %v want %v", rr.Code, tc.ExpectedStatus)
+			}
+			if tc.ExpectedError == "" {
+				return
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to unmarshal response body: %v", err)
+			}
+			if body["error"] != tc.ExpectedError {
+				t.Errorf("unexpected error: got %v want %v", body["error"], tc.ExpectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+// CorrelationHeader es el encabezado que transporta el ID de correlación entre servicios.
+const CorrelationHeader = "X-Correlation-ID"
+
+// CorrelationMiddleware propaga el ID de correlación recibido, o genera uno nuevo, en el
+// contexto de la solicitud y en la respuesta.
+func CorrelationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(CorrelationHeader)
+		if id == "" || len(id) > 128 {
+			id = utils.NewCorrelationID()
+		}
+
+		c.Header(CorrelationHeader, id)
+		c.Request = c.Request.WithContext(utils.ContextWithCorrelationID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
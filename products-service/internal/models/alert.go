@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// LowStockAlert avisa que el stock disponible de un producto llegó a su punto de reorden.
+type LowStockAlert struct {
+	ProductID       string    `json:"product_id"`
+	Title           string    `json:"title"`
+	Category        string    `json:"category"`
+	Stock           uint      `json:"stock"`
+	ReorderPoint    uint      `json:"reorder_point"`
+	ReorderQuantity uint      `json:"reorder_quantity"`
+	DetectedAt      time.Time `json:"detected_at"`
+}
+
+// NewLowStockAlert construye la alerta de un producto con su regla de reposición efectiva.
+func NewLowStockAlert(product *Product, at time.Time) LowStockAlert {
+	rule := product.ReorderRule()
+	return LowStockAlert{
+		ProductID:       product.ID,
+		Title:           product.Title,
+		Category:        product.Category,
+		Stock:           product.Stock,
+		ReorderPoint:    rule.Point,
+		ReorderQuantity: rule.Quantity,
+		DetectedAt:      at,
+	}
+}
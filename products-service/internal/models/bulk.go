@@ -0,0 +1,37 @@
+package models
+
+// BulkFilter selecciona los productos de una operación masiva con los mismos criterios del listado.
+// swagger:model
+type BulkFilter struct {
+	Query     string   `json:"q"`
+	Category  string   `json:"category"`
+	MinPrice  *uint    `json:"min_price"`
+	MaxPrice  *uint    `json:"max_price"`
+	Currency  string   `json:"currency"`
+	InStock   bool     `json:"in_stock"`
+	MinRating *float64 `json:"min_rating"`
+	// Attributes exige una misma variante con todos estos atributos.
+	Attributes map[string]string `json:"attributes"`
+}
+
+// BulkRequest describe una operación masiva: los productos se eligen por IDs o por filtro, y se
+// actualizan con Update o se envían a la papelera con Delete.
+// swagger:model
+type BulkRequest struct {
+	IDs    []string    `json:"ids"`
+	Filter *BulkFilter `json:"filter"`
+	// Update admite los mismos campos que PATCH /products/:id salvo sku y barcodes, que son únicos;
+	// el precio debe indicar su moneda.
+	Update map[string]interface{} `json:"update"`
+	Delete bool                   `json:"delete"`
+}
+
+// BulkResult resume una operación masiva. En una eliminación, Modified es la cantidad de productos eliminados.
+// swagger:model
+type BulkResult struct {
+	Matched  int64 `json:"matched"`
+	Modified int64 `json:"modified"`
+	// Skipped cuenta los productos que se dejaron sin tocar: al actualizar, los que tienen variantes y el precio
+	// cambiaba su moneda; al eliminar, los que tienen reservas abiertas.
+	Skipped int64 `json:"skipped,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Estados del circuit breaker del cache de productos.
+const (
+	CacheStateClosed   = "closed"
+	CacheStateOpen     = "open"
+	CacheStateHalfOpen = "half-open"
+)
+
+// CacheStats son los contadores de una familia de claves del cache de productos.
+type CacheStats struct {
+	// Hits son lecturas servidas con una entrada fresca.
+	Hits uint64 `json:"hits"`
+	// Misses son lecturas sin entrada en el cache que consultaron la base de datos.
+	Misses uint64 `json:"misses"`
+	// Stale son lecturas servidas con una entrada vencida mientras se refresca.
+	Stale uint64 `json:"stale"`
+	// Coalesced son lecturas que esperaron la carga iniciada por otra solicitud en lugar de consultar la base de datos.
+	Coalesced uint64 `json:"coalesced"`
+	// Refreshes son refrescos en segundo plano iniciados y RefreshErrors los que fallaron.
+	Refreshes     uint64 `json:"refreshes"`
+	RefreshErrors uint64 `json:"refresh_errors"`
+	// Errors son fallos del cache que se resolvieron consultando la base de datos.
+	Errors uint64 `json:"errors"`
+}
+
+// CacheStatus es el estado del cache de productos: el de su circuit breaker y los contadores por familia.
+type CacheStatus struct {
+	State    string                `json:"state"`
+	Families map[string]CacheStats `json:"families"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+)
+
+// Category es una categoría de productos; los productos la referencian por su slug.
+// swagger:model
+type Category struct {
+	ID   string `json:"id" bson:"_id,omitempty"`
+	Slug string `json:"slug" bson:"slug"`
+	Name string `json:"name" bson:"name" binding:"required"`
+	// Parent es el slug de la categoría padre; vacío en las categorías raíz.
+	Parent string `json:"parent,omitempty" bson:"parent,omitempty"`
+	// ReorderPoint y ReorderQuantity son la regla de reposición de los productos de la categoría que no
+	// definen la suya; con ReorderPoint en 0 esos productos no generan alertas de stock bajo.
+	ReorderPoint    *uint     `json:"reorder_point,omitempty" bson:"reorder_point,omitempty"`
+	ReorderQuantity *uint     `json:"reorder_quantity,omitempty" bson:"reorder_quantity,omitempty"`
+	CreatedAt       time.Time `json:"created_at" bson:"created_at"`
+}
+
+// ReorderRule retorna la regla de reposición por defecto de la categoría.
+func (c *Category) ReorderRule() constants.ReorderRule {
+	var rule constants.ReorderRule
+	if c.ReorderPoint != nil {
+		rule.Point = *c.ReorderPoint
+	}
+	if c.ReorderQuantity != nil {
+		rule.Quantity = *c.ReorderQuantity
+	}
+	return rule
+}
+
+// CategoryNode es una categoría con sus subcategorías.
+// swagger:model
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "errors"
+
+// Errores de dominio que los controladores traducen a códigos HTTP específicos.
+var (
+	ErrProductNotFound     = errors.New("product not found")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationClosed   = errors.New("reservation is no longer active")
+	ErrWarehouseNotFound   = errors.New("warehouse not found")
+	ErrWarehouseInUse      = errors.New("warehouse still holds stock")
+	ErrDuplicateWarehouse  = errors.New("warehouse code already exists")
+	ErrCacheUnavailable    = errors.New("cache unavailable")
+	ErrImageNotFound       = errors.New("image not found")
+	ErrDuplicateImage      = errors.New("image is already attached to the product")
+	ErrImageTooLarge       = errors.New("image is too large")
+	ErrUnsupportedImage    = errors.New("unsupported image type")
+	ErrPresignUnsupported  = errors.New("image storage does not support direct uploads")
+	ErrInvalidUploadKey    = errors.New("invalid upload key")
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrDuplicateReview     = errors.New("user already reviewed this product")
+	ErrForbidden           = errors.New("not allowed")
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrDuplicateCategory   = errors.New("category slug already exists")
+	ErrCategoryInUse       = errors.New("category still has products")
+	ErrCategoryHasChildren = errors.New("category still has subcategories")
+	ErrDuplicateSKU        = errors.New("sku already exists")
+	ErrDuplicateBarcode    = errors.New("barcode already belongs to another product")
+	ErrVariantNotFound     = errors.New("variant not found")
+	ErrVariantRequired     = errors.New("variant_id is required for products with variants")
+	ErrVariantReserved     = errors.New("variant has reserved units")
+	ErrProductHasStock     = errors.New("product has stock outside its variants")
+	ErrProductReserved     = errors.New("product has open reservations")
+	ErrUnsupportedFormat   = errors.New("unsupported file format")
+)
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ProductImage es una imagen de un producto guardada en el almacenamiento de objetos.
+type ProductImage struct {
+	ID          string    `json:"id" bson:"id"`
+	Key         string    `json:"-" bson:"key"`
+	URL         string    `json:"url" bson:"url"`
+	ContentType string    `json:"content_type" bson:"content_type"`
+	Size        int64     `json:"size" bson:"size"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	// La miniatura se genera en segundo plano; ThumbnailKey ausente indica que está pendiente
+	// y vacía que no se pudo generar.
+	ThumbnailKey *string `json:"-" bson:"thumbnail_key,omitempty"`
+	ThumbnailURL string  `json:"thumbnail_url,omitempty" bson:"thumbnail_url,omitempty"`
+}
+
+// ImageUploadRequest es el cuerpo de la solicitud de una URL de subida directa.
+type ImageUploadRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+// ImageUpload describe una subida directa al almacenamiento: el cliente envía el archivo con
+// Method a UploadURL, incluyendo Headers, antes de ExpiresAt, y luego la confirma con Key.
+type ImageUpload struct {
+	Key       string            `json:"key"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// ImageUploadConfirmation es el cuerpo de la solicitud que asocia una subida directa al producto.
+type ImageUploadConfirmation struct {
+	Key string `json:"key" binding:"required"`
+}
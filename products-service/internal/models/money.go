@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+)
+
+// Money es un importe en unidades menores de su moneda, por ejemplo centavos, con su código ISO 4217.
+// swagger:model
+type Money struct {
+	Amount   int64  `json:"amount" bson:"amount"`
+	Currency string `json:"currency" bson:"currency"`
+}
+
+// Normalize valida el importe y la moneda y retorna el importe con la moneda en mayúsculas.
+func (m Money) Normalize() (Money, error) {
+	m.Currency = strings.ToUpper(strings.TrimSpace(m.Currency))
+	if _, ok := constants.CurrencyMinorUnits[m.Currency]; !ok {
+		return m, errors.New("price currency must be an ISO 4217 code")
+	}
+	if m.Amount < 0 {
+		return m, errors.New("price amount cannot be negative")
+	}
+	return m, nil
+}
+
+// CurrencyScale retorna cuántas unidades menores tiene una unidad mayor de la moneda, por ejemplo 100 para USD.
+func CurrencyScale(currency string) int64 {
+	scale := int64(1)
+	for i := 0; i < constants.CurrencyMinorUnits[currency]; i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+// PriceChange es una entrada inmutable del historial de precios de un producto o de una de sus variantes.
+// swagger:model
+type PriceChange struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	ProductID string `json:"product_id" bson:"product_id"`
+	VariantID string `json:"variant_id,omitempty" bson:"variant_id,omitempty"`
+	// Previous es nulo cuando el precio se fijó por primera vez.
+	Previous      *Money    `json:"previous" bson:"previous,omitempty"`
+	Price         Money     `json:"price" bson:"price"`
+	Actor         string    `json:"actor" bson:"actor"`
+	CorrelationID string    `json:"correlation_id" bson:"correlation_id"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Motivos de los movimientos de inventario.
+const (
+	MovementInitialStock       = "initial_stock"
+	MovementIncrement          = "increment"
+	MovementDecrement          = "decrement"
+	MovementReservation        = "reservation"
+	MovementReservationCommit  = "reservation_committed"
+	MovementReservationRelease = "reservation_released"
+	MovementReservationExpired = "reservation_expired"
+	MovementTransferOut        = "transfer_out"
+	MovementTransferIn         = "transfer_in"
+	MovementVariantRemoved     = "variant_removed"
+)
+
+// MovementSystemActor identifica los movimientos que no origina un usuario, como las reservas expiradas.
+const MovementSystemActor = "system"
+
+// Movement es una entrada inmutable del historial de inventario de un producto.
+// Delta es el cambio del stock disponible en WarehouseID (o en el stock sin asignar si está vacío),
+// o en la variante VariantID, y ReservedDelta el del stock reservado.
+// swagger:model
+type Movement struct {
+	ID            string    `json:"id" bson:"_id,omitempty"`
+	ProductID     string    `json:"product_id" bson:"product_id"`
+	WarehouseID   string    `json:"warehouse_id,omitempty" bson:"warehouse_id,omitempty"`
+	VariantID     string    `json:"variant_id,omitempty" bson:"variant_id,omitempty"`
+	Delta         int64     `json:"delta" bson:"delta"`
+	ReservedDelta int64     `json:"reserved_delta" bson:"reserved_delta"`
+	Reason        string    `json:"reason" bson:"reason"`
+	Actor         string    `json:"actor" bson:"actor"`
+	CorrelationID string    `json:"correlation_id" bson:"correlation_id"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// MovementFilter limita el historial a un rango de fechas; los límites nulos no se aplican.
+type MovementFilter struct {
+	From *time.Time
+	To   *time.Time
+}
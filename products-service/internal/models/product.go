@@ -1,20 +1,99 @@
 package models
 
 import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
 	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
 )
 
 // swagger:model
 type Product struct {
-	ID          string `json:"id" bson:"_id,omitempty"`
-	Title       string `json:"title" bson:"title"`
-	Description string `json:"description" bson:"description"`
-	Category    string `json:"category" bson:"category"`
-	Price       uint   `json:"price" bson:"price"`
-	Stock       uint   `json:"stock" bson:"stock"`
-	Rating      []uint `json:"rating" bson:"rating"`
+	ID string `json:"id" bson:"_id,omitempty"`
+	// SKU identifica al producto de forma única; se guarda en mayúsculas.
+	SKU string `json:"sku" bson:"sku,omitempty"`
+	// Barcodes son sus códigos EAN-13 o UPC-A, guardados en formato EAN-13.
+	Barcodes    []string `json:"barcodes,omitempty" bson:"barcodes,omitempty"`
+	Title       string   `json:"title" bson:"title"`
+	Description string   `json:"description" bson:"description"`
+	Category    string   `json:"category" bson:"category"`
+	// Price es el precio del producto; en los productos con variantes cada una tiene el suyo en la misma moneda.
+	Price    Money `json:"price" bson:"price"`
+	Stock    uint  `json:"stock" bson:"stock"`
+	Reserved uint  `json:"reserved" bson:"reserved"`
+	// Rating se calcula a partir de las reseñas visibles; no se modifica directamente.
+	Rating RatingSummary `json:"rating" bson:"rating_summary"`
+	// Locations reparte el stock disponible entre almacenes; Stock es el total.
+	Locations []StockLocation `json:"locations" bson:"locations,omitempty"`
+	// ReorderPoint y ReorderQuantity sobrescriben la regla de la categoría; un punto en 0 desactiva las alertas.
+	ReorderPoint    *uint `json:"reorder_point,omitempty" bson:"reorder_point,omitempty"`
+	ReorderQuantity *uint `json:"reorder_quantity,omitempty" bson:"reorder_quantity,omitempty"`
+	// Variants solo cambia mediante los endpoints de variantes; si las hay, definen el stock del producto.
+	Variants []Variant `json:"variants,omitempty" bson:"variants,omitempty"`
+	// Images solo cambia mediante los endpoints de imágenes.
+	Images []ProductImage `json:"images" bson:"images,omitempty"`
+	// DeletedAt marca un producto en la papelera; las lecturas lo excluyen hasta que se restaure o se purgue.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// StockLocation es el stock disponible de un producto en un almacén.
+type StockLocation struct {
+	WarehouseID string `json:"warehouse_id" bson:"warehouse_id"`
+	Quantity    uint   `json:"quantity" bson:"quantity"`
 }
 
 func (p *Product) IsValidCategory() bool {
 	return utils.IsValidCategory(p.Category)
 }
+
+// ReorderRule retorna la regla de reposición efectiva: la del producto si la tiene, o la de su categoría.
+func (p *Product) ReorderRule() constants.ReorderRule {
+	rule := utils.CategoryReorderRules()[strings.ToLower(p.Category)]
+	if p.ReorderPoint != nil {
+		rule.Point = *p.ReorderPoint
+	}
+	if p.ReorderQuantity != nil {
+		rule.Quantity = *p.ReorderQuantity
+	}
+	return rule
+}
+
+// StockValue retorna el valor del stock disponible en unidades menores de la moneda del producto:
+// el precio por el stock o, si tiene variantes, la suma de los de cada una. ok es falso si el valor
+// no cabe en un int64.
+func (p *Product) StockValue() (value int64, ok bool) {
+	if len(p.Variants) == 0 {
+		return multiplyStock(p.Price.Amount, p.Stock)
+	}
+	for _, variant := range p.Variants {
+		variantValue, ok := multiplyStock(variant.Price.Amount, variant.Stock)
+		if !ok || (variantValue > 0 && value > math.MaxInt64-variantValue) || (variantValue < 0 && value < math.MinInt64-variantValue) {
+			return 0, false
+		}
+		value += variantValue
+	}
+	return value, true
+}
+
+// multiplyStock retorna amount por stock; ok es falso si el producto no cabe en un int64.
+func multiplyStock(amount int64, stock uint) (int64, bool) {
+	if stock == 0 {
+		return 0, true
+	}
+	if uint64(stock) > math.MaxInt64 {
+		return 0, false
+	}
+	value := amount * int64(stock)
+	if value/int64(stock) != amount {
+		return 0, false
+	}
+	return value, true
+}
+
+// IsLowStock indica si el stock disponible llegó al punto de reorden.
+func (p *Product) IsLowStock() bool {
+	rule := p.ReorderRule()
+	return rule.Point > 0 && p.Stock <= rule.Point
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Órdenes admitidos por el listado de productos; con prefijo "-" el orden es descendente.
+var ProductSortFields = []string{"price", "stock", "rating", "created"}
+
+// ProductFilter reúne los criterios de búsqueda, filtrado y orden del listado de productos.
+type ProductFilter struct {
+	// IDs limita el filtro a esos productos; lo usan las operaciones masivas.
+	IDs      []string
+	Query    string
+	Category string
+	// MinPrice y MaxPrice son importes en unidades menores; Currency limita el listado a una moneda.
+	MinPrice  *uint
+	MaxPrice  *uint
+	Currency  string
+	InStock   bool
+	MinRating *float64
+	// Attributes exige una misma variante con todos estos atributos; los precios y
+	// la disponibilidad se evalúan entonces sobre esa variante.
+	Attributes map[string]string
+	// Sort es uno de ProductSortFields, opcionalmente con prefijo "-".
+	Sort string
+}
+
+// CacheKey serializa todos los criterios en un orden fijo para usarlos como parte de una clave de cache.
+func (f ProductFilter) CacheKey() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "q=%q;category=%s;currency=%s;in_stock=%t;sort=%s", strings.ToLower(f.Query), f.Category, f.Currency, f.InStock, f.Sort)
+	if len(f.IDs) > 0 {
+		fmt.Fprintf(&b, ";ids=%s", strings.Join(f.IDs, ","))
+	}
+	if f.MinPrice != nil {
+		fmt.Fprintf(&b, ";min_price=%d", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		fmt.Fprintf(&b, ";max_price=%d", *f.MaxPrice)
+	}
+	if f.MinRating != nil {
+		fmt.Fprintf(&b, ";min_rating=%g", *f.MinRating)
+	}
+	names := make([]string, 0, len(f.Attributes))
+	for name := range f.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, ";attr.%s=%q", name, f.Attributes[name])
+	}
+	return b.String()
+}
+
+// PageRequest describe la página pedida. En modo cursor se ignora Page y se retornan los productos
+// cuyo _id es posterior a After ("" para empezar desde el principio).
+type PageRequest struct {
+	Page       int
+	Size       int
+	CursorMode bool
+	After      string
+}
+
+// CacheKey identifica la página pedida para usarla como parte de una clave de cache.
+func (p PageRequest) CacheKey() string {
+	if p.CursorMode {
+		return fmt.Sprintf("after_%s_size_%d", p.After, p.Size)
+	}
+	return fmt.Sprintf("page_%d_size_%d", p.Page, p.Size)
+}
+
+// ProductPage es la respuesta paginada del listado de productos.
+// swagger:model
+type ProductPage struct {
+	Items []Product `json:"items"`
+	Total int64     `json:"total"`
+	Page  int       `json:"page,omitempty"`
+	Size  int       `json:"size"`
+	Next  *string   `json:"next"`
+	Prev  *string   `json:"prev"`
+	// NextCursor es el valor de after para la siguiente página en modo cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
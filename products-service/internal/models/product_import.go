@@ -0,0 +1,57 @@
+package models
+
+// Formatos de archivo de la importación y la exportación de productos; XLSX solo se exporta.
+const (
+	ProductFormatCSV    = "csv"
+	ProductFormatNDJSON = "ndjson"
+	ProductFormatXLSX   = "xlsx"
+)
+
+// ProductCSVColumns son las columnas que admite un CSV de productos. Los importes están en unidades
+// menores de price_currency y los códigos de barras se separan con "|".
+var ProductCSVColumns = []string{
+	"sku", "title", "description", "category", "price_amount", "price_currency",
+	"stock", "barcodes", "reorder_point", "reorder_quantity",
+}
+
+// ProductExportColumns son las columnas de la exportación: las de ProductCSVColumns más id, reserved y
+// stock_value, el valor del stock disponible en unidades menores. La importación ignora estas tres.
+var ProductExportColumns = []string{
+	"id", "sku", "title", "description", "category", "price_amount", "price_currency",
+	"stock", "reserved", "stock_value", "barcodes", "reorder_point", "reorder_quantity",
+}
+
+// Resultado de cada fila de una importación.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+// ImportRowResult es el resultado de una fila del archivo importado.
+// swagger:model
+type ImportRowResult struct {
+	// Line es la línea del archivo en la que empieza la fila.
+	Line   int    `json:"line"`
+	SKU    string `json:"sku"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport resume una importación; en modo dry_run indica lo que se haría sin escribir nada.
+// swagger:model
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// ProductWrite es el resultado de escribir un producto en una importación por lotes.
+type ProductWrite struct {
+	ID      string
+	Created bool
+	Err     error
+}
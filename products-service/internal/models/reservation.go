@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Estados posibles de una reserva de stock.
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// Reservation aparta unidades de un producto durante un tiempo limitado.
+// swagger:model
+type Reservation struct {
+	ID          string    `json:"id" bson:"_id,omitempty"`
+	ProductID   string    `json:"product_id" bson:"product_id"`
+	WarehouseID string    `json:"warehouse_id,omitempty" bson:"warehouse_id,omitempty"`
+	VariantID   string    `json:"variant_id,omitempty" bson:"variant_id,omitempty"`
+	Quantity    uint      `json:"quantity" bson:"quantity"`
+	Status      string    `json:"status" bson:"status"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+// StockAdjustment es el cuerpo de las solicitudes que incrementan o decrementan stock.
+// Sin warehouse_id el ajuste se aplica al stock sin asignar a un almacén. En los productos
+// con variantes se indica variant_id en lugar del almacén.
+type StockAdjustment struct {
+	Quantity    uint   `json:"quantity" binding:"required,min=1"`
+	WarehouseID string `json:"warehouse_id"`
+	VariantID   string `json:"variant_id"`
+}
+
+// ReservationRequest es el cuerpo de la solicitud para reservar stock.
+type ReservationRequest struct {
+	Quantity    uint   `json:"quantity" binding:"required,min=1"`
+	TTLSeconds  uint   `json:"ttl_seconds"`
+	WarehouseID string `json:"warehouse_id"`
+	VariantID   string `json:"variant_id"`
+}
+
+// StockTransfer es el cuerpo de la solicitud para mover stock entre almacenes.
+// Un almacén vacío representa el stock sin asignar.
+type StockTransfer struct {
+	FromWarehouseID string `json:"from_warehouse_id"`
+	ToWarehouseID   string `json:"to_warehouse_id"`
+	Quantity        uint   `json:"quantity" binding:"required,min=1"`
+}
+
+// StockLevel es el stock resultante de un producto después de una operación.
+type StockLevel struct {
+	ProductID string          `json:"product_id"`
+	Stock     uint            `json:"stock"`
+	Reserved  uint            `json:"reserved"`
+	Locations []StockLocation `json:"locations"`
+	Variants  []Variant       `json:"variants,omitempty"`
+}
+
+// ReservationResult agrupa la reserva creada con el stock resultante.
+type ReservationResult struct {
+	Reservation Reservation `json:"reservation"`
+	Stock       StockLevel  `json:"stock"`
+}
+
+// NewStockLevel construye el StockLevel de un producto.
+func NewStockLevel(product *Product) StockLevel {
+	return StockLevel{
+		ProductID: product.ID,
+		Stock:     product.Stock,
+		Reserved:  product.Reserved,
+		Locations: product.Locations,
+		Variants:  product.Variants,
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Estados de moderación de una reseña; solo las visibles cuentan en la calificación del producto.
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+)
+
+// Review es la reseña de un usuario sobre un producto; cada usuario puede dejar una por producto.
+// swagger:model
+type Review struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	ProductID string    `json:"product_id" bson:"product_id"`
+	Author    string    `json:"author" bson:"author"`
+	Rating    uint      `json:"rating" bson:"rating"`
+	Text      string    `json:"text" bson:"text"`
+	Status    string    `json:"status" bson:"status"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// ReviewRequest es el cuerpo de la solicitud para publicar una reseña.
+type ReviewRequest struct {
+	Rating uint   `json:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" binding:"max=2000"`
+}
+
+// ReviewUpdate es el cuerpo de la solicitud con que el autor modifica su reseña; los campos ausentes no cambian.
+type ReviewUpdate struct {
+	Rating *uint   `json:"rating" binding:"omitempty,min=1,max=5"`
+	Text   *string `json:"text" binding:"omitempty,max=2000"`
+}
+
+// ReviewModeration es el cuerpo de la solicitud para ocultar o volver a mostrar una reseña.
+type ReviewModeration struct {
+	Status string `json:"status" binding:"required,oneof=visible hidden"`
+}
+
+// ReviewPage es una página de reseñas de un producto.
+type ReviewPage struct {
+	Items []Review `json:"items"`
+	Total int64    `json:"total"`
+	Page  int      `json:"page"`
+	Size  int      `json:"size"`
+	Next  *string  `json:"next"`
+	Prev  *string  `json:"prev"`
+}
+
+// RatingSummary es el agregado de las reseñas visibles de un producto. Se actualiza de forma
+// incremental con cada reseña; Sum permite recalcular el promedio sin recorrerlas.
+type RatingSummary struct {
+	Average float64 `json:"average" bson:"average"`
+	Count   int64   `json:"count" bson:"count"`
+	Sum     int64   `json:"-" bson:"sum"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// Variant es una versión vendible de un producto, por ejemplo talla M en azul, con su propio SKU,
+// precio y stock. En un producto con variantes Stock y Reserved son la suma de las de sus variantes,
+// y el stock de las variantes no se asigna a almacenes.
+// swagger:model
+type Variant struct {
+	ID string `json:"id" bson:"id"`
+	// SKU es único entre todas las variantes; se guarda en mayúsculas.
+	SKU string `json:"sku" bson:"sku" binding:"required"`
+	// Attributes describe la variante, por ejemplo {"size": "M", "color": "blue"}.
+	Attributes map[string]string `json:"attributes" bson:"attributes" binding:"required,min=1"`
+	// Price usa la moneda del producto si no indica otra.
+	Price Money `json:"price" bson:"price"`
+	Stock uint  `json:"stock" bson:"stock"`
+	// Reserved solo cambia mediante las reservas de stock.
+	Reserved uint `json:"reserved" bson:"reserved"`
+}
+
+// FindVariant retorna la variante del producto con el ID indicado.
+func (p *Product) FindVariant(id string) (*Variant, bool) {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i], true
+		}
+	}
+	return nil, false
+}
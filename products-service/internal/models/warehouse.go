@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Warehouse es una ubicación física donde se almacena stock.
+// swagger:model
+type Warehouse struct {
+	ID        string    `json:"id" bson:"_id,omitempty"`
+	Code      string    `json:"code" bson:"code" binding:"required"`
+	Name      string    `json:"name" bson:"name" binding:"required"`
+	Address   string    `json:"address" bson:"address"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
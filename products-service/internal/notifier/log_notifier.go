@@ -0,0 +1,23 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// LogNotifier escribe las alertas en el log del servicio.
+type LogNotifier struct{}
+
+// NewLogNotifier crea una nueva instancia de LogNotifier.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// NotifyLowStock registra la alerta en el log.
+func (n *LogNotifier) NotifyLowStock(ctx context.Context, alert models.LowStockAlert) error {
+	log.Printf("low stock: product %s (%s) has %d units, reorder point %d, reorder quantity %d",
+		alert.ProductID, alert.Title, alert.Stock, alert.ReorderPoint, alert.ReorderQuantity)
+	return nil
+}
@@ -0,0 +1,36 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// MemoryNotifier guarda las alertas en memoria; útil en desarrollo y pruebas.
+type MemoryNotifier struct {
+	mu     sync.Mutex
+	alerts []models.LowStockAlert
+}
+
+// NewMemoryNotifier crea una nueva instancia de MemoryNotifier.
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+// NotifyLowStock agrega la alerta a la lista en memoria.
+func (n *MemoryNotifier) NotifyLowStock(ctx context.Context, alert models.LowStockAlert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// Alerts retorna una copia de las alertas recibidas.
+func (n *MemoryNotifier) Alerts() []models.LowStockAlert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]models.LowStockAlert(nil), n.alerts...)
+}
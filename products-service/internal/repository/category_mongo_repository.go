@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CategoryRepository gestiona las categorías de productos en MongoDB.
+type CategoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCategoryRepository crea una nueva instancia de CategoryRepository con la colección especificada.
+func NewCategoryRepository(collection *mongo.Collection) *CategoryRepository {
+	return &CategoryRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice único sobre el slug y el índice por categoría padre.
+func (r *CategoryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "parent", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating category indexes: %v", err)
+	}
+	return nil
+}
+
+// FindAll retorna todas las categorías ordenadas por slug.
+func (r *CategoryRepository) FindAll(ctx context.Context) ([]models.Category, error) {
+	categories := []models.Category{}
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "slug", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding categories: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, fmt.Errorf("error decoding categories: %v", err)
+	}
+
+	return categories, nil
+}
+
+// FindOne busca una categoría por su slug.
+func (r *CategoryRepository) FindOne(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&category); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+		}
+		return nil, fmt.Errorf("error finding category: %v", err)
+	}
+
+	return &category, nil
+}
+
+// Create inserta una categoría y la retorna con el ID asignado por MongoDB.
+func (r *CategoryRepository) Create(ctx context.Context, category models.Category) (*models.Category, error) {
+	category.ID = ""
+
+	result, err := r.collection.InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, models.ErrDuplicateCategory
+		}
+		return nil, fmt.Errorf("error inserting category: %v", err)
+	}
+
+	if objID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		category.ID = objID.Hex()
+	}
+	return &category, nil
+}
+
+// Update modifica los campos indicados de una categoría.
+func (r *CategoryRepository) Update(ctx context.Context, slug string, fields map[string]interface{}) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"slug": slug}, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("error updating category: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+	}
+
+	return nil
+}
+
+// Delete elimina una categoría por su slug.
+func (r *CategoryRepository) Delete(ctx context.Context, slug string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"slug": slug})
+	if err != nil {
+		return fmt.Errorf("error deleting category: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CategoryRepositoryMocked es un repositorio de categorías en memoria para pruebas.
+type CategoryRepositoryMocked struct {
+	mu         sync.Mutex
+	categories map[string]models.Category
+}
+
+// NewCategoryRepositoryMocked crea un repositorio en memoria con las categorías indicadas.
+func NewCategoryRepositoryMocked(categories ...models.Category) *CategoryRepositoryMocked {
+	r := &CategoryRepositoryMocked{categories: map[string]models.Category{}}
+	for _, category := range categories {
+		r.categories[category.Slug] = category
+	}
+	return r
+}
+
+func (r *CategoryRepositoryMocked) FindAll(ctx context.Context) ([]models.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	categories := []models.Category{}
+	for _, category := range r.categories {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Slug < categories[j].Slug })
+	return categories, nil
+}
+
+func (r *CategoryRepositoryMocked) FindOne(ctx context.Context, slug string) (*models.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	category, ok := r.categories[slug]
+	if !ok {
+		return nil, fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepositoryMocked) Create(ctx context.Context, category models.Category) (*models.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[category.Slug]; ok {
+		return nil, models.ErrDuplicateCategory
+	}
+	category.ID = primitive.NewObjectID().Hex()
+	r.categories[category.Slug] = category
+	return &category, nil
+}
+
+func (r *CategoryRepositoryMocked) Update(ctx context.Context, slug string, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	category, ok := r.categories[slug]
+	if !ok {
+		return fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+	}
+	if name, ok := fields["name"].(string); ok {
+		category.Name = name
+	}
+	if parent, ok := fields["parent"].(string); ok {
+		category.Parent = parent
+	}
+	if point, ok := fields["reorder_point"].(uint); ok {
+		category.ReorderPoint = &point
+	}
+	if quantity, ok := fields["reorder_quantity"].(uint); ok {
+		category.ReorderQuantity = &quantity
+	}
+	r.categories[slug] = category
+	return nil
+}
+
+func (r *CategoryRepositoryMocked) Delete(ctx context.Context, slug string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[slug]; !ok {
+		return fmt.Errorf("%w with slug: %s", models.ErrCategoryNotFound, slug)
+	}
+	delete(r.categories, slug)
+	return nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MovementRepository gestiona el historial de movimientos de inventario en MongoDB.
+// La colección es de solo inserción: no expone operaciones de actualización ni borrado.
+type MovementRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMovementRepository crea una nueva instancia de MovementRepository con la colección especificada.
+func NewMovementRepository(collection *mongo.Collection) *MovementRepository {
+	return &MovementRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice usado para consultar el historial de un producto por fecha.
+func (r *MovementRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating movement indexes: %v", err)
+	}
+	return nil
+}
+
+// Create inserta un movimiento en el historial.
+func (r *MovementRepository) Create(ctx context.Context, movement models.Movement) error {
+	movement.ID = primitive.NewObjectID().Hex()
+
+	if _, err := r.collection.InsertOne(ctx, movement); err != nil {
+		return fmt.Errorf("error inserting movement: %v", err)
+	}
+	return nil
+}
+
+// FindByProduct obtiene los movimientos de un producto dentro del rango de fechas, con paginación.
+func (r *MovementRepository) FindByProduct(ctx context.Context, productID string, filter models.MovementFilter, page, size int) ([]models.Movement, error) {
+	movements := []models.Movement{}
+
+	query := bson.M{"product_id": productID}
+	createdAt := bson.M{}
+	if filter.From != nil {
+		createdAt["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		createdAt["$lt"] = *filter.To
+	}
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	skip := (page - 1) * size
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(size))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding movements: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &movements); err != nil {
+		return nil, fmt.Errorf("error decoding movements: %v", err)
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PriceHistoryRepository gestiona el historial de precios en MongoDB.
+// La colección es de solo inserción: no expone operaciones de actualización ni borrado.
+type PriceHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPriceHistoryRepository crea una nueva instancia de PriceHistoryRepository con la colección especificada.
+func NewPriceHistoryRepository(collection *mongo.Collection) *PriceHistoryRepository {
+	return &PriceHistoryRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice usado para consultar el historial de un producto por fecha.
+func (r *PriceHistoryRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating price history indexes: %v", err)
+	}
+	return nil
+}
+
+// Create inserta un cambio de precio en el historial.
+func (r *PriceHistoryRepository) Create(ctx context.Context, change models.PriceChange) error {
+	change.ID = primitive.NewObjectID().Hex()
+
+	if _, err := r.collection.InsertOne(ctx, change); err != nil {
+		return fmt.Errorf("error inserting price change: %v", err)
+	}
+	return nil
+}
+
+// FindByProduct obtiene los cambios de precio de un producto y sus variantes, con paginación.
+func (r *PriceHistoryRepository) FindByProduct(ctx context.Context, productID string, page, size int) ([]models.PriceChange, error) {
+	changes := []models.PriceChange{}
+
+	skip := (page - 1) * size
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(size))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"product_id": productID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding price history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &changes); err != nil {
+		return nil, fmt.Errorf("error decoding price history: %v", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// PriceHistoryRepositoryMocked guarda el historial de precios en memoria.
+type PriceHistoryRepositoryMocked struct {
+	mu      sync.Mutex
+	Changes []models.PriceChange
+}
+
+func (r *PriceHistoryRepositoryMocked) Create(ctx context.Context, change models.PriceChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Changes = append(r.Changes, change)
+	return nil
+}
+
+// FindByProduct retorna los cambios del producto del más reciente al más antiguo, sin paginar.
+func (r *PriceHistoryRepositoryMocked) FindByProduct(ctx context.Context, productID string, page, size int) ([]models.PriceChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := []models.PriceChange{}
+	for i := len(r.Changes) - 1; i >= 0; i-- {
+		if r.Changes[i].ProductID == productID {
+			changes = append(changes, r.Changes[i])
+		}
+	}
+	return changes, nil
+}
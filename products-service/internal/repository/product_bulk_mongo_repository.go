@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BulkUpdate asigna los campos indicados a todos los productos que cumplen el filtro en un único bulk write.
+func (r *ProductRepository) BulkUpdate(ctx context.Context, filter models.ProductFilter, fields map[string]interface{}) (*models.BulkResult, error) {
+	write := mongo.NewUpdateManyModel().SetFilter(productMatch(filter)).SetUpdate(bson.M{"$set": fields})
+
+	result, err := r.collection.BulkWrite(ctx, []mongo.WriteModel{write})
+	if err != nil {
+		return nil, fmt.Errorf("error updating products: %v", err)
+	}
+	return &models.BulkResult{Matched: result.MatchedCount, Modified: result.ModifiedCount}, nil
+}
+
+// BulkDelete envía a la papelera todos los productos sin reservas abiertas que cumplen el filtro en un
+// único bulk write.
+func (r *ProductRepository) BulkDelete(ctx context.Context, filter models.ProductFilter) (*models.BulkResult, error) {
+	write := mongo.NewUpdateManyModel().SetFilter(unreserved(productMatch(filter))).
+		SetUpdate(bson.M{"$set": bson.M{"deleted_at": time.Now().UTC()}})
+
+	result, err := r.collection.BulkWrite(ctx, []mongo.WriteModel{write})
+	if err != nil {
+		return nil, fmt.Errorf("error deleting products: %v", err)
+	}
+	return &models.BulkResult{Matched: result.MatchedCount, Modified: result.ModifiedCount}, nil
+}
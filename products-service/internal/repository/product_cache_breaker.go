@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// BreakerOptions configura cuándo se abre el circuito y cuánto tarda en volver a probar el cache.
+type BreakerOptions struct {
+	// FailureThreshold es el número de fallos consecutivos que abre el circuito.
+	FailureThreshold int
+	// OpenTimeout es el tiempo que el circuito permanece abierto antes de permitir una prueba.
+	OpenTimeout time.Duration
+}
+
+// DefaultBreakerOptions retorna la configuración por defecto: 5 fallos y 30 segundos abierto.
+func DefaultBreakerOptions() BreakerOptions {
+	return BreakerOptions{FailureThreshold: 5, OpenTimeout: 30 * time.Second}
+}
+
+// ProductCacheBreaker envuelve un cache de productos con un circuit breaker. Mientras el circuito
+// está abierto las llamadas fallan de inmediato con models.ErrCacheUnavailable sin tocar el cache.
+// Pasado OpenTimeout se deja pasar una sola llamada de prueba: si funciona el circuito se cierra,
+// si falla vuelve a abrirse.
+//
+// Las invalidaciones que no se pudieron aplicar quedan pendientes y, antes de volver a usar el cache,
+// se invalida todo para no servir productos que cambiaron durante la caída.
+type ProductCacheBreaker struct {
+	cache   interfaces.ProductRedisRepositoryInterface
+	options BreakerOptions
+	now     func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool
+	// pending y flushed cuentan las invalidaciones fallidas y la última aplicada con InvalidateAll.
+	pending uint64
+	flushed uint64
+}
+
+// NewProductCacheBreaker crea un circuit breaker cerrado alrededor de cache.
+func NewProductCacheBreaker(cache interfaces.ProductRedisRepositoryInterface, options BreakerOptions) *ProductCacheBreaker {
+	return &ProductCacheBreaker{cache: cache, options: options, now: time.Now, state: models.CacheStateClosed}
+}
+
+// State retorna el estado actual del circuito.
+func (b *ProductCacheBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == models.CacheStateOpen && b.now().Sub(b.openedAt) >= b.options.OpenTimeout {
+		return models.CacheStateHalfOpen
+	}
+	return b.state
+}
+
+// ListVersion ver ProductRedisRepository.ListVersion.
+func (b *ProductCacheBreaker) ListVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := b.call(ctx, func() (err error) {
+		version, err = b.cache.ListVersion(ctx)
+		return err
+	})
+	return version, err
+}
+
+// GetPage ver ProductRedisRepository.GetPage.
+func (b *ProductCacheBreaker) GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error) {
+	var page *models.ProductPage
+	var storedAt time.Time
+	err := b.call(ctx, func() (err error) {
+		page, storedAt, err = b.cache.GetPage(ctx, version, key)
+		return err
+	})
+	return page, storedAt, err
+}
+
+// SetPage ver ProductRedisRepository.SetPage.
+func (b *ProductCacheBreaker) SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error {
+	return b.call(ctx, func() error {
+		return b.cache.SetPage(ctx, version, key, page, ttl)
+	})
+}
+
+// GetOne ver ProductRedisRepository.GetOne.
+func (b *ProductCacheBreaker) GetOne(ctx context.Context, id string) (*models.Product, time.Time, error) {
+	var product *models.Product
+	var storedAt time.Time
+	err := b.call(ctx, func() (err error) {
+		product, storedAt, err = b.cache.GetOne(ctx, id)
+		return err
+	})
+	return product, storedAt, err
+}
+
+// SetOne ver ProductRedisRepository.SetOne.
+func (b *ProductCacheBreaker) SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error {
+	return b.call(ctx, func() error {
+		return b.cache.SetOne(ctx, version, id, product, ttl)
+	})
+}
+
+// InvalidateProducts ver ProductRedisRepository.InvalidateProducts. Si falla, la invalidación queda pendiente.
+func (b *ProductCacheBreaker) InvalidateProducts(ctx context.Context, ids ...string) error {
+	err := b.call(ctx, func() error {
+		return b.cache.InvalidateProducts(ctx, ids...)
+	})
+	if err != nil {
+		b.markPending()
+	}
+	return err
+}
+
+// InvalidateAll ver ProductRedisRepository.InvalidateAll. Si falla, la invalidación queda pendiente.
+func (b *ProductCacheBreaker) InvalidateAll(ctx context.Context) error {
+	err := b.call(ctx, func() error {
+		return b.cache.InvalidateAll(ctx)
+	})
+	if err != nil {
+		b.markPending()
+	}
+	return err
+}
+
+// call ejecuta fn si el circuito lo permite y registra su resultado. Si hay una invalidación
+// pendiente, se aplica antes; hasta que no se logre ninguna llamada llega al cache.
+func (b *ProductCacheBreaker) call(ctx context.Context, fn func() error) error {
+	pending, ok := b.allow()
+	if !ok {
+		return models.ErrCacheUnavailable
+	}
+
+	if pending > 0 {
+		if err := b.cache.InvalidateAll(ctx); err != nil {
+			b.record(err)
+			return models.ErrCacheUnavailable
+		}
+		b.clearPending(pending)
+	}
+
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// allow decide si una llamada puede llegar al cache. Si hay invalidaciones pendientes retorna
+// el contador hasta el que deben aplicarse; si no, 0.
+func (b *ProductCacheBreaker) allow() (pending uint64, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case models.CacheStateOpen:
+		if b.now().Sub(b.openedAt) < b.options.OpenTimeout {
+			return 0, false
+		}
+		b.state = models.CacheStateHalfOpen
+		b.trial = true
+	case models.CacheStateHalfOpen:
+		// Solo una llamada de prueba a la vez.
+		if b.trial {
+			return 0, false
+		}
+		b.trial = true
+	}
+	if b.pending == b.flushed {
+		return 0, true
+	}
+	return b.pending, true
+}
+
+// record actualiza el circuito con el resultado de una llamada.
+func (b *ProductCacheBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if err == nil {
+		if b.state != models.CacheStateClosed {
+			log.Printf("cache circuit closed")
+		}
+		b.state = models.CacheStateClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == models.CacheStateHalfOpen || b.failures >= b.options.FailureThreshold {
+		if b.state != models.CacheStateOpen {
+			log.Printf("cache circuit open: %v", err)
+		}
+		b.state = models.CacheStateOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *ProductCacheBreaker) markPending() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending++
+}
+
+// clearPending marca como aplicadas las invalidaciones hasta upTo; las que fallaron
+// mientras tanto siguen pendientes.
+func (b *ProductCacheBreaker) clearPending(upTo uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if upTo > b.flushed {
+		b.flushed = upTo
+	}
+}
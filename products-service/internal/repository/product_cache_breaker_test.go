@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func initBreaker(t *testing.T) (*ProductCacheBreaker, *ProductCacheMocked, *fakeClock) {
+	t.Helper()
+
+	cache := NewProductCacheMocked()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	breaker := NewProductCacheBreaker(cache, BreakerOptions{FailureThreshold: 3, OpenTimeout: 10 * time.Second})
+	breaker.now = clock.Now
+
+	return breaker, cache, clock
+}
+
+func TestProductCacheBreakerStates(t *testing.T) {
+	tc := []struct {
+		Name          string
+		Failures      int
+		Wait          time.Duration
+		CacheHealthy  bool
+		ExpectedState string
+		ExpectedError error
+		ExpectedCalls int
+	}{
+		{
+			Name:          "Stays closed below the threshold",
+			Failures:      2,
+			CacheHealthy:  true,
+			ExpectedState: models.CacheStateClosed,
+			ExpectedCalls: 3,
+		},
+		{
+			Name:          "Opens at the threshold and skips the cache",
+			Failures:      3,
+			CacheHealthy:  true,
+			ExpectedState: models.CacheStateOpen,
+			ExpectedError: models.ErrCacheUnavailable,
+			ExpectedCalls: 3,
+		},
+		{
+			Name:          "Closes after a successful trial",
+			Failures:      3,
+			Wait:          10 * time.Second,
+			CacheHealthy:  true,
+			ExpectedState: models.CacheStateClosed,
+			ExpectedCalls: 4,
+		},
+		{
+			Name:          "Reopens after a failed trial",
+			Failures:      3,
+			Wait:          10 * time.Second,
+			ExpectedState: models.CacheStateOpen,
+			ExpectedError: ErrCacheMocked,
+			ExpectedCalls: 4,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			breaker, cache, clock := initBreaker(t)
+			ctx := context.Background()
+
+			cache.SetFailing(true)
+			for i := 0; i < tc.Failures; i++ {
+				if _, _, err := breaker.GetOne(ctx, "1"); !errors.Is(err, ErrCacheMocked) {
+					t.Fatalf("unexpected error: got %v want %v", err, ErrCacheMocked)
+				}
+			}
+
+			clock.now = clock.now.Add(tc.Wait)
+			cache.SetFailing(!tc.CacheHealthy)
+
+			_, _, err := breaker.GetOne(ctx, "1")
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.ExpectedError)
+			}
+			if state := breaker.State(); state != tc.ExpectedState {
+				t.Errorf("unexpected state: got %v want %v", state, tc.ExpectedState)
+			}
+			if calls := cache.CallCount(); calls != tc.ExpectedCalls {
+				t.Errorf("unexpected cache calls: got %v want %v", calls, tc.ExpectedCalls)
+			}
+		})
+	}
+}
+
+func TestProductCacheBreakerReportsHalfOpen(t *testing.T) {
+	breaker, cache, clock := initBreaker(t)
+
+	cache.SetFailing(true)
+	for i := 0; i < 3; i++ {
+		breaker.GetOne(context.Background(), "1")
+	}
+	clock.now = clock.now.Add(10 * time.Second)
+
+	if state := breaker.State(); state != models.CacheStateHalfOpen {
+		t.Fatalf("unexpected state: got %v want %v", state, models.CacheStateHalfOpen)
+	}
+}
+
+func TestProductCacheBreakerFlushesPendingInvalidations(t *testing.T) {
+	breaker, cache, clock := initBreaker(t)
+	ctx := context.Background()
+
+	if err := breaker.SetOne(ctx, 0, "1", &models.Product{ID: "1", Title: "old"}, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// La invalidación se pierde mientras Redis está caído.
+	cache.SetFailing(true)
+	for i := 0; i < 3; i++ {
+		breaker.InvalidateProducts(ctx, "1")
+	}
+	if err := breaker.InvalidateProducts(ctx, "1"); !errors.Is(err, models.ErrCacheUnavailable) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrCacheUnavailable)
+	}
+
+	// Al recuperarse, el producto viejo no debe servirse.
+	cache.SetFailing(false)
+	clock.now = clock.now.Add(10 * time.Second)
+
+	product, _, err := breaker.GetOne(ctx, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product != nil {
+		t.Errorf("unexpected stale product: %v", product)
+	}
+	if cache.InvalidatedAll != 1 {
+		t.Errorf("unexpected full invalidations: got %v want %v", cache.InvalidatedAll, 1)
+	}
+
+	// Una vez aplicada, la invalidación pendiente no se repite.
+	breaker.GetOne(ctx, "1")
+	if cache.InvalidatedAll != 1 {
+		t.Errorf("unexpected full invalidations: got %v want %v", cache.InvalidatedAll, 1)
+	}
+}
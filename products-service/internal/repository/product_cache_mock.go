@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// ErrCacheMocked es el error que retorna ProductCacheMocked cuando simula una falla.
+var ErrCacheMocked = errors.New("redis: connection refused")
+
+// ProductCacheMocked es un cache de productos en memoria que puede simular que Redis está caído.
+type ProductCacheMocked struct {
+	mu       sync.Mutex
+	failing  bool
+	version  int64
+	products map[string]models.Product
+	pages    map[string]models.ProductPage
+	storedAt map[string]time.Time
+
+	// Calls cuenta las llamadas recibidas, incluidas las que fallaron.
+	Calls int
+	// Invalidated son los IDs invalidados e InvalidatedAll las veces que se invalidó todo.
+	Invalidated    []string
+	InvalidatedAll int
+}
+
+// NewProductCacheMocked crea un cache en memoria vacío y sano.
+func NewProductCacheMocked() *ProductCacheMocked {
+	return &ProductCacheMocked{products: map[string]models.Product{}, pages: map[string]models.ProductPage{}, storedAt: map[string]time.Time{}}
+}
+
+// Age hace que las entradas guardadas parezcan guardadas d antes; las siguientes se guardan con la hora actual.
+func (c *ProductCacheMocked) Age(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, at := range c.storedAt {
+		c.storedAt[key] = at.Add(-d)
+	}
+}
+
+// SetFailing hace que todas las llamadas siguientes fallen con ErrCacheMocked, o que vuelvan a funcionar.
+func (c *ProductCacheMocked) SetFailing(failing bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failing = failing
+}
+
+// CallCount retorna el número de llamadas recibidas.
+func (c *ProductCacheMocked) CallCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Calls
+}
+
+func (c *ProductCacheMocked) call() error {
+	c.Calls++
+	if c.failing {
+		return ErrCacheMocked
+	}
+	return nil
+}
+
+func (c *ProductCacheMocked) ListVersion(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return 0, err
+	}
+	return c.version, nil
+}
+
+func (c *ProductCacheMocked) GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return nil, time.Time{}, err
+	}
+	page, ok := c.pages[c.pageKey(version, key)]
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+	return &page, c.storedAt["page:"+c.pageKey(version, key)], nil
+}
+
+func (c *ProductCacheMocked) SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return err
+	}
+	c.pages[c.pageKey(version, key)] = *page
+	c.storedAt["page:"+c.pageKey(version, key)] = time.Now()
+	return nil
+}
+
+func (c *ProductCacheMocked) GetOne(ctx context.Context, id string) (*models.Product, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return nil, time.Time{}, err
+	}
+	product, ok := c.products[id]
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+	return &product, c.storedAt["product:"+id], nil
+}
+
+func (c *ProductCacheMocked) SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return err
+	}
+	if version != c.version {
+		return nil
+	}
+	c.products[id] = *product
+	c.storedAt["product:"+id] = time.Now()
+	return nil
+}
+
+func (c *ProductCacheMocked) InvalidateProducts(ctx context.Context, ids ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		delete(c.products, id)
+	}
+	c.Invalidated = append(c.Invalidated, ids...)
+	c.version++
+	return nil
+}
+
+func (c *ProductCacheMocked) InvalidateAll(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call(); err != nil {
+		return err
+	}
+	c.products = map[string]models.Product{}
+	c.InvalidatedAll++
+	c.version++
+	return nil
+}
+
+func (c *ProductCacheMocked) pageKey(version int64, key string) string {
+	return strconv.FormatInt(version, 10) + ":" + key
+}
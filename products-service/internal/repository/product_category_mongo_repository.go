@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// categoryMatch filtra los productos de una categoría sin distinguir mayúsculas,
+// igual que el filtro de los listados.
+func categoryMatch(category string) bson.M {
+	return bson.M{"category": bson.M{"$regex": "^" + regexp.QuoteMeta(category) + "$", "$options": "i"}}
+}
+
+// CountByCategory cuenta los productos de la categoría indicada, incluidos los de la papelera,
+// que al restaurarse deben seguir apuntando a una categoría existente.
+func (r *ProductRepository) CountByCategory(ctx context.Context, category string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, categoryMatch(category))
+	if err != nil {
+		return 0, fmt.Errorf("error counting products: %v", err)
+	}
+	return count, nil
+}
+
+// ReassignCategory mueve todos los productos de una categoría a otra, también los de la papelera,
+// y retorna cuántos cambiaron.
+func (r *ProductRepository) ReassignCategory(ctx context.Context, from, to string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx, categoryMatch(from), bson.M{"$set": bson.M{"category": to}})
+	if err != nil {
+		return 0, fmt.Errorf("error reassigning products: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
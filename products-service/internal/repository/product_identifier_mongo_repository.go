@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Nombres de los índices únicos de productos; permiten saber qué clave se repitió.
+const (
+	skuIndex        = "sku_unique"
+	barcodesIndex   = "barcodes_unique"
+	variantSKUIndex = "variants_sku_unique"
+)
+
+// duplicateProductError traduce un error de clave duplicada al error de dominio del índice que lo produjo.
+func duplicateProductError(err error) error {
+	if strings.Contains(err.Error(), barcodesIndex) {
+		return models.ErrDuplicateBarcode
+	}
+	return models.ErrDuplicateSKU
+}
+
+// FindBySKU busca un producto por su SKU ya normalizado o por el de una de sus variantes.
+func (r *ProductRepository) FindBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return r.findOneBy(ctx, bson.M{"$or": bson.A{bson.M{"sku": sku}, bson.M{"variants.sku": sku}}}, "SKU "+sku)
+}
+
+// FindByBarcode busca el producto que tiene el código de barras indicado, en formato EAN-13.
+func (r *ProductRepository) FindByBarcode(ctx context.Context, code string) (*models.Product, error) {
+	return r.findOneBy(ctx, bson.M{"barcodes": code}, "barcode "+code)
+}
+
+func (r *ProductRepository) findOneBy(ctx context.Context, filter bson.M, description string) (*models.Product, error) {
+	var product models.Product
+	if err := r.collection.FindOne(ctx, active(filter)).Decode(&product); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with %s", models.ErrProductNotFound, description)
+		}
+		return nil, fmt.Errorf("error finding product: %v", err)
+	}
+	return &product, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddImage agrega una imagen al final de las del producto. La condición sobre el ID hace que dos
+// confirmaciones simultáneas de la misma subida no la agreguen dos veces.
+func (r *ProductRepository) AddImage(ctx context.Context, id string, image models.ProductImage) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		active(bson.M{"_id": objID, "images.id": bson.M{"$ne": image.ID}}),
+		bson.M{"$push": bson.M{"images": image}},
+	)
+	if err != nil {
+		return fmt.Errorf("error adding image: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.collection.CountDocuments(ctx, active(bson.M{"_id": objID}))
+		if err != nil {
+			return fmt.Errorf("error finding product: %v", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+		}
+		return models.ErrDuplicateImage
+	}
+
+	return nil
+}
+
+// RemoveImage quita una imagen del producto; falla si el producto no la tiene
+func (r *ProductRepository) RemoveImage(ctx context.Context, id, imageID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		active(bson.M{"_id": objID, "images.id": imageID}),
+		bson.M{"$pull": bson.M{"images": bson.M{"id": imageID}}},
+	)
+	if err != nil {
+		return fmt.Errorf("error removing image: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+	}
+
+	return nil
+}
+
+// SetImageThumbnail registra la miniatura de una imagen; falla si el producto ya no la tiene
+func (r *ProductRepository) SetImageThumbnail(ctx context.Context, id, imageID, key, url string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "images.id": imageID},
+		bson.M{"$set": bson.M{"images.$.thumbnail_key": key, "images.$.thumbnail_url": url}},
+	)
+	if err != nil {
+		return fmt.Errorf("error setting thumbnail: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+	}
+
+	return nil
+}
+
+// FindPendingThumbnails retorna productos fuera de la papelera con alguna imagen sin miniatura registrada
+func (r *ProductRepository) FindPendingThumbnails(ctx context.Context, limit int) ([]models.Product, error) {
+	cursor, err := r.collection.Find(ctx,
+		active(bson.M{"images": bson.M{"$elemMatch": bson.M{"thumbnail_key": bson.M{"$exists": false}}}}),
+		options.Find().SetLimit(int64(limit)).SetProjection(bson.M{"images": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error finding pending thumbnails: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	products := []models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %v", err)
+	}
+	return products, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// catalogFields son los campos que una importación reemplaza en los productos existentes; el resto,
+// como el stock, las variantes o las imágenes, solo se escribe al crear el producto.
+var catalogFields = []string{"title", "description", "category", "price", "barcodes", "reorder_point", "reorder_quantity"}
+
+// FindBySKUs retorna los productos cuyo SKU, ya normalizado, es uno de los indicados, incluidos los de la
+// papelera, que conservan su SKU.
+func (r *ProductRepository) FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error) {
+	products := []models.Product{}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"sku": bson.M{"$in": skus}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %v", err)
+	}
+	return products, nil
+}
+
+// UpsertBySKU escribe los productos en un único bulk write no ordenado: crea los SKU nuevos y reemplaza los
+// datos de catálogo de los existentes. Retorna el resultado de cada producto en el mismo orden; el ID solo
+// se conoce para los creados.
+func (r *ProductRepository) UpsertBySKU(ctx context.Context, products []models.Product) ([]models.ProductWrite, error) {
+	writes := make([]mongo.WriteModel, len(products))
+	for i, product := range products {
+		update, err := upsertUpdate(product)
+		if err != nil {
+			return nil, err
+		}
+		// Un SKU en la papelera no coincide y el alta falla por el índice único; $exists evita que el
+		// upsert copie el campo al documento nuevo, como ocurriría con una igualdad a null.
+		filter := bson.M{"sku": product.SKU, "deleted_at": bson.M{"$exists": false}}
+		writes[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true)
+	}
+
+	results := make([]models.ProductWrite, len(products))
+	result, err := r.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) || len(bulkErr.WriteErrors) == 0 {
+			return nil, fmt.Errorf("error importing products: %v", err)
+		}
+		for _, writeErr := range bulkErr.WriteErrors {
+			if mongo.IsDuplicateKeyError(writeErr) {
+				results[writeErr.Index].Err = duplicateProductError(writeErr)
+			} else {
+				results[writeErr.Index].Err = fmt.Errorf("error importing product: %v", writeErr.Message)
+			}
+		}
+	}
+
+	for index, id := range result.UpsertedIDs {
+		results[index].Created = true
+		if objID, ok := id.(primitive.ObjectID); ok {
+			results[index].ID = objID.Hex()
+		}
+	}
+	return results, nil
+}
+
+// upsertUpdate arma la actualización de un producto importado: $set con los datos de catálogo, $unset con
+// los que la fila deja vacíos y $setOnInsert con el resto de campos de un producto nuevo.
+func upsertUpdate(product models.Product) (bson.M, error) {
+	raw, err := bson.Marshal(product)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding product: %v", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("error encoding product: %v", err)
+	}
+	// El SKU lo escribe el filtro al insertar.
+	delete(fields, "sku")
+	delete(fields, "_id")
+
+	set, unset := bson.M{}, bson.M{}
+	for _, field := range catalogFields {
+		if value, ok := fields[field]; ok {
+			set[field] = value
+			delete(fields, field)
+		} else {
+			unset[field] = ""
+		}
+	}
+	fields["_id"] = primitive.NewObjectID()
+
+	update := bson.M{"$set": set, "$setOnInsert": fields}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update, nil
+}
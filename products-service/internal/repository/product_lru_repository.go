@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// ProductLRURepository es un cache de productos en memoria del proceso, con vigencia por entrada
+// y un máximo de entradas: al superarlo se descartan las menos usadas. Usa las mismas claves que
+// ProductRedisRepository, sin prefijo porque no se comparte con nadie.
+type ProductLRURepository struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    *list.List
+	items      map[string]*list.Element
+	version    int64
+	now        func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	data      []byte
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+// NewProductLRURepository crea un cache en memoria que guarda como máximo maxEntries entradas.
+func NewProductLRURepository(maxEntries int) *ProductLRURepository {
+	return &ProductLRURepository{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		items:      map[string]*list.Element{},
+		now:        time.Now,
+	}
+}
+
+// Len retorna el número de entradas guardadas, incluidas las vencidas que aún no se descartaron.
+func (r *ProductLRURepository) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.entries.Len()
+}
+
+// ListVersion obtiene la versión vigente de los listados
+func (r *ProductLRURepository) ListVersion(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.version, nil
+}
+
+// GetPage obtiene una página de un listado de productos del caché y el momento en que se guardó
+func (r *ProductLRURepository) GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error) {
+	var page models.ProductPage
+	storedAt, found, err := r.get(lruPageKey(version, key), &page)
+	if err != nil || !found {
+		return nil, time.Time{}, err
+	}
+	return &page, storedAt, nil
+}
+
+// SetPage almacena en caché una página de un listado de productos durante ttl
+func (r *ProductLRURepository) SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error {
+	now := r.now()
+	return r.set(lruPageKey(version, key), page, now, now.Add(ttl))
+}
+
+// GetOne obtiene un solo producto del caché por su ID y el momento en que se guardó
+func (r *ProductLRURepository) GetOne(ctx context.Context, id string) (*models.Product, time.Time, error) {
+	var product models.Product
+	storedAt, found, err := r.get(lruProductKey(id), &product)
+	if err != nil || !found {
+		return nil, time.Time{}, err
+	}
+	return &product, storedAt, nil
+}
+
+// SetOne almacena en caché un producto bajo su ID durante ttl, salvo que la versión haya cambiado desde que se leyó
+func (r *ProductLRURepository) SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error {
+	now := r.now()
+	entry, err := newLRUEntry(lruProductKey(id), product, now, now.Add(ttl))
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.version == version {
+		r.store(entry)
+	}
+	return nil
+}
+
+// InvalidateProducts elimina del caché los productos indicados e invalida todos los listados
+func (r *ProductLRURepository) InvalidateProducts(ctx context.Context, ids ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		r.remove(lruProductKey(id))
+	}
+	// Las páginas de versiones anteriores ya no se leerán: se liberan de inmediato.
+	for key := range r.items {
+		if strings.HasPrefix(key, "products:") {
+			r.remove(key)
+		}
+	}
+	r.version++
+	return nil
+}
+
+// InvalidateAll elimina todas las entradas e invalida todos los listados
+func (r *ProductLRURepository) InvalidateAll(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries.Init()
+	r.items = map[string]*list.Element{}
+	r.version++
+	return nil
+}
+
+// get lee y deserializa una entrada vigente y la marca como usada recientemente
+func (r *ProductLRURepository) get(key string, dest interface{}) (time.Time, bool, error) {
+	r.mu.Lock()
+	element, ok := r.items[key]
+	if !ok {
+		r.mu.Unlock()
+		return time.Time{}, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !r.now().Before(entry.expiresAt) {
+		r.remove(key)
+		r.mu.Unlock()
+		return time.Time{}, false, nil
+	}
+	r.entries.MoveToFront(element)
+	data, storedAt := entry.data, entry.storedAt
+	r.mu.Unlock()
+
+	// Se guarda serializado para que quien lee no comparta memoria con el cache.
+	if err := json.Unmarshal(data, dest); err != nil {
+		return time.Time{}, false, errors.New("failed to unmarshal payload from cache")
+	}
+	return storedAt, true, nil
+}
+
+// set guarda una entrada hasta expiresAt y descarta las menos usadas si hace falta. storedAt se
+// conserva aparte porque una copia de otro cache mantiene el momento en que se guardó originalmente.
+func (r *ProductLRURepository) set(key string, payload interface{}, storedAt, expiresAt time.Time) error {
+	entry, err := newLRUEntry(key, payload, storedAt, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store(entry)
+	return nil
+}
+
+// newLRUEntry serializa los datos de una entrada
+func newLRUEntry(key string, payload interface{}, storedAt, expiresAt time.Time) (*lruEntry, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.New("failed to marshal payload to JSON")
+	}
+	return &lruEntry{key: key, data: data, storedAt: storedAt, expiresAt: expiresAt}, nil
+}
+
+// store guarda una entrada y descarta las menos usadas si se supera el máximo; debe llamarse con el mutex tomado
+func (r *ProductLRURepository) store(entry *lruEntry) {
+	if element, ok := r.items[entry.key]; ok {
+		element.Value = entry
+		r.entries.MoveToFront(element)
+		return
+	}
+
+	r.items[entry.key] = r.entries.PushFront(entry)
+	for r.maxEntries > 0 && r.entries.Len() > r.maxEntries {
+		r.remove(r.entries.Back().Value.(*lruEntry).key)
+	}
+}
+
+// remove elimina una entrada; debe llamarse con el mutex tomado
+func (r *ProductLRURepository) remove(key string) {
+	if element, ok := r.items[key]; ok {
+		r.entries.Remove(element)
+		delete(r.items, key)
+	}
+}
+
+func lruProductKey(id string) string {
+	return "product:" + id
+}
+
+func lruPageKey(version int64, key string) string {
+	return "products:v" + strconv.FormatInt(version, 10) + ":" + key
+}
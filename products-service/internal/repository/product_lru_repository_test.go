@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+func initLRU(t *testing.T, maxEntries int) (*ProductLRURepository, *fakeClock) {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := NewProductLRURepository(maxEntries)
+	cache.now = clock.Now
+
+	return cache, clock
+}
+
+func TestProductLRURepository(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Run      func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock)
+		ID       string
+		Expected bool
+	}{
+		{
+			Name: "Returns a stored product",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+			},
+			ID:       "1",
+			Expected: true,
+		},
+		{
+			Name: "Expires after the TTL",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+				clock.now = clock.now.Add(time.Minute)
+			},
+			ID:       "1",
+			Expected: false,
+		},
+		{
+			Name: "Evicts the least recently used entry",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+				cache.SetOne(ctx, 0, "2", &models.Product{ID: "2"}, time.Minute)
+				cache.GetOne(ctx, "1")
+				cache.SetOne(ctx, 0, "3", &models.Product{ID: "3"}, time.Minute)
+			},
+			ID:       "2",
+			Expected: false,
+		},
+		{
+			Name: "Keeps a recently used entry",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+				cache.SetOne(ctx, 0, "2", &models.Product{ID: "2"}, time.Minute)
+				cache.GetOne(ctx, "1")
+				cache.SetOne(ctx, 0, "3", &models.Product{ID: "3"}, time.Minute)
+			},
+			ID:       "1",
+			Expected: true,
+		},
+		{
+			Name: "Invalidates a product",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+				cache.InvalidateProducts(ctx, "1")
+			},
+			ID:       "1",
+			Expected: false,
+		},
+		{
+			Name: "Invalidates everything",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				cache.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+				cache.InvalidateAll(ctx)
+			},
+			ID:       "1",
+			Expected: false,
+		},
+		{
+			Name: "Skips a product loaded before an invalidation",
+			Run: func(ctx context.Context, cache *ProductLRURepository, clock *fakeClock) {
+				version, _ := cache.ListVersion(ctx)
+				cache.InvalidateProducts(ctx, "2")
+				cache.SetOne(ctx, version, "1", &models.Product{ID: "1"}, time.Minute)
+			},
+			ID:       "1",
+			Expected: false,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cache, clock := initLRU(t, 2)
+
+			tc.Run(ctx, cache, clock)
+
+			product, _, err := cache.GetOne(ctx, tc.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (product != nil) != tc.Expected {
+				t.Fatalf("unexpected hit: got %v want %v", product != nil, tc.Expected)
+			}
+			if cache.Len() > 2 {
+				t.Errorf("unexpected size: got %v want at most 2", cache.Len())
+			}
+		})
+	}
+}
+
+func TestProductLRURepositoryPages(t *testing.T) {
+	ctx := context.Background()
+	cache, _ := initLRU(t, 10)
+
+	version, _ := cache.ListVersion(ctx)
+	cache.SetPage(ctx, version, "page", &models.ProductPage{Total: 1}, time.Minute)
+	cache.SetOne(ctx, 0, "2", &models.Product{ID: "2"}, time.Minute)
+
+	if page, _, _ := cache.GetPage(ctx, version, "page"); page == nil || page.Total != 1 {
+		t.Fatalf("unexpected page: got %v", page)
+	}
+
+	cache.InvalidateProducts(ctx, "1")
+
+	next, _ := cache.ListVersion(ctx)
+	if next == version {
+		t.Fatalf("unexpected version: got %v want a new version", next)
+	}
+	if page, _, _ := cache.GetPage(ctx, version, "page"); page != nil {
+		t.Errorf("unexpected page after invalidation: got %v", page)
+	}
+	if product, _, _ := cache.GetOne(ctx, "2"); product == nil {
+		t.Errorf("unexpected miss for a product that was not invalidated")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("unexpected size: got %v want 1", cache.Len())
+	}
+}
+
+func initTiered(t *testing.T) (*ProductTieredRepository, *ProductCacheMocked, *[][]byte) {
+	t.Helper()
+
+	var published [][]byte
+	remote := NewProductCacheMocked()
+	tiered := &ProductTieredRepository{
+		local:    NewProductLRURepository(10),
+		remote:   remote,
+		localTTL: time.Minute,
+		origin:   "self",
+		publish: func(ctx context.Context, payload []byte) error {
+			published = append(published, payload)
+			return nil
+		},
+	}
+
+	return tiered, remote, &published
+}
+
+func TestProductTieredRepositoryReadsThrough(t *testing.T) {
+	ctx := context.Background()
+	tiered, remote, _ := initTiered(t)
+
+	remote.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+	calls := remote.CallCount()
+
+	for i := 0; i < 2; i++ {
+		product, _, err := tiered.GetOne(ctx, "1")
+		if err != nil || product == nil {
+			t.Fatalf("unexpected result: got %v, %v", product, err)
+		}
+	}
+	if got := remote.CallCount() - calls; got != 1 {
+		t.Errorf("unexpected remote calls: got %v want 1", got)
+	}
+}
+
+func TestProductTieredRepositoryInvalidation(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Payload  string
+		Expected bool
+	}{
+		{
+			Name:     "Drops the products of another instance",
+			Payload:  `{"origin":"other","ids":["1"]}`,
+			Expected: false,
+		},
+		{
+			Name:     "Drops everything",
+			Payload:  `{"origin":"other","all":true}`,
+			Expected: false,
+		},
+		{
+			Name:     "Keeps unrelated products",
+			Payload:  `{"origin":"other","ids":["2"]}`,
+			Expected: true,
+		},
+		{
+			Name:     "Ignores its own messages",
+			Payload:  `{"origin":"self","ids":["1"]}`,
+			Expected: true,
+		},
+		{
+			Name:     "Drops everything on an unreadable message",
+			Payload:  `not json`,
+			Expected: false,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			tiered, _, _ := initTiered(t)
+			tiered.local.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+
+			tiered.handleInvalidation(ctx, []byte(tc.Payload))
+
+			product, _, _ := tiered.local.GetOne(ctx, "1")
+			if (product != nil) != tc.Expected {
+				t.Fatalf("unexpected hit: got %v want %v", product != nil, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestProductTieredRepositoryPublishesInvalidations(t *testing.T) {
+	ctx := context.Background()
+	tiered, remote, published := initTiered(t)
+
+	tiered.SetOne(ctx, 0, "1", &models.Product{ID: "1"}, time.Minute)
+	if err := tiered.InvalidateProducts(ctx, "1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if product, _, _ := tiered.local.GetOne(ctx, "1"); product != nil {
+		t.Errorf("unexpected local hit after invalidation")
+	}
+	if len(remote.Invalidated) != 1 || remote.Invalidated[0] != "1" {
+		t.Errorf("unexpected remote invalidations: got %v want [1]", remote.Invalidated)
+	}
+	if len(*published) != 1 || string((*published)[0]) != `{"origin":"self","ids":["1"]}` {
+		t.Errorf("unexpected published messages: got %q", *published)
+	}
+}
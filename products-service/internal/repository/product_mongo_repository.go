@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
@@ -21,30 +23,235 @@ func NewProductRepository(collection *mongo.Collection) *ProductRepository {
 	return &ProductRepository{collection: collection}
 }
 
-// FindAll obtiene todos los productos de la colección con paginación.
-func (r *ProductRepository) FindAll(ctx context.Context, page, size int) ([]models.Product, error) {
-	var products []models.Product
+// EnsureIndexes crea el índice de texto que usa la búsqueda del listado y los índices únicos del SKU,
+// los códigos de barras y los SKU de variantes. Estos últimos son parciales para no incluir los productos sin ellos.
+// El índice de la papelera, también parcial, sirve a su listado y a la purga.
+func (r *ProductRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}}},
+		{
+			Keys: bson.D{{Key: "sku", Value: 1}},
+			Options: options.Index().SetName(skuIndex).SetUnique(true).
+				SetPartialFilterExpression(bson.M{"sku": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "barcodes", Value: 1}},
+			Options: options.Index().SetName(barcodesIndex).SetUnique(true).
+				SetPartialFilterExpression(bson.M{"barcodes": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "variants.sku", Value: 1}},
+			Options: options.Index().SetName(variantSKUIndex).SetUnique(true).
+				SetPartialFilterExpression(bson.M{"variants.sku": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$type": "date"}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating product indexes: %v", err)
+	}
+	return nil
+}
 
-	// Calcula el número de documentos a omitir según la página y el tamaño.
-	skip := (page - 1) * size
+// FindAll obtiene los productos que cumplen el filtro, ordenados y paginados, junto con el total de coincidencias.
+// En modo cursor se filtra por _id en lugar de omitir documentos, y se pide uno de más para saber si hay otra página.
+func (r *ProductRepository) FindAll(ctx context.Context, filter models.ProductFilter, page models.PageRequest) ([]models.Product, int64, error) {
+	products := []models.Product{}
 
-	// Realiza la búsqueda de productos en la colección, aplicando la paginación.
-	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSkip(int64(skip)).SetLimit(int64(size)))
+	total, err := r.count(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("error finding products: %v", err)
+		return nil, 0, err
+	}
+
+	match := productMatch(filter)
+	limit := int64(page.Size)
+	var skip int64
+	if page.CursorMode {
+		if page.After != "" {
+			after, err := primitive.ObjectIDFromHex(page.After)
+			if err != nil {
+				return nil, 0, fmt.Errorf("invalid cursor: %v", err)
+			}
+			match["_id"] = bson.M{"$gt": after}
+		}
+		limit++
+	} else {
+		// Calcula el número de documentos a omitir según la página y el tamaño.
+		skip = int64((page.Page - 1) * page.Size)
+	}
+
+	sort := productSort(filter)
+	if page.CursorMode {
+		// El cursor solo es válido si el orden es por _id.
+		sort = bson.D{{Key: "_id", Value: 1}}
+	}
+
+	pipeline := append(productPipeline(match),
+		bson.D{{Key: "$sort", Value: sort}},
+		bson.D{{Key: "$skip", Value: skip}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+
+	// Realiza la búsqueda de productos en la colección, aplicando filtros, orden y paginación.
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error finding products: %v", err)
 	}
 	defer cursor.Close(ctx) // Asegura que el cursor se cierre después de su uso.
 
 	// Decodifica todos los productos encontrados en la variable 'products'.
 	if err = cursor.All(ctx, &products); err != nil {
-		return nil, fmt.Errorf("error decoding products: %v", err)
+		return nil, 0, fmt.Errorf("error decoding products: %v", err)
+	}
+
+	return products, total, nil
+}
+
+// StreamAll recorre con un cursor todos los productos que cumplen el filtro, en el orden pedido, y llama a fn
+// con cada uno a medida que llegan de MongoDB, sin cargarlos todos en memoria. Se detiene en el primer error de fn.
+func (r *ProductRepository) StreamAll(ctx context.Context, filter models.ProductFilter, fn func(product *models.Product) error) error {
+	pipeline := append(productPipeline(productMatch(filter)), bson.D{{Key: "$sort", Value: productSort(filter)}})
+
+	// Un catálogo grande puede superar el límite de memoria del ordenamiento.
+	cursor, err := r.collection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return fmt.Errorf("error finding products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var product models.Product
+		if err := cursor.Decode(&product); err != nil {
+			return fmt.Errorf("error decoding product: %v", err)
+		}
+		if err := fn(&product); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("error reading products: %v", err)
+	}
+	return nil
+}
+
+// count cuenta los productos que cumplen el filtro, sin paginación.
+func (r *ProductRepository) count(ctx context.Context, filter models.ProductFilter) (int64, error) {
+	pipeline := append(productPipeline(productMatch(filter)), bson.D{{Key: "$count", Value: "total"}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("error counting products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, fmt.Errorf("error counting products: %v", err)
 	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return result[0].Total, nil
+}
 
-	if len(products) == 0 {
-		return nil, fmt.Errorf("no products found") // Mensaje informativo si no hay productos.
+// productPipeline arma las etapas de filtrado.
+func productPipeline(match bson.M) mongo.Pipeline {
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
+}
+
+// productMatch traduce el filtro del listado a la etapa $match inicial; $text debe ir en ella.
+// Los productos en la papelera nunca coinciden.
+func productMatch(filter models.ProductFilter) bson.M {
+	match := active(bson.M{})
+	if len(filter.IDs) > 0 {
+		// Los IDs ya se validaron; uno inválido no coincide con ningún producto.
+		ids := make([]primitive.ObjectID, 0, len(filter.IDs))
+		for _, id := range filter.IDs {
+			if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+		match["_id"] = bson.M{"$in": ids}
+	}
+	if filter.Query != "" {
+		match["$text"] = bson.M{"$search": filter.Query}
+	}
+	if filter.Category != "" {
+		match["category"] = categoryMatch(filter.Category)["category"]
+	}
+	if filter.MinRating != nil {
+		match["rating_summary.average"] = bson.M{"$gte": *filter.MinRating}
+	}
+	if filter.Currency != "" {
+		// Las variantes comparten la moneda del producto.
+		match["price.currency"] = filter.Currency
 	}
 
-	return products, nil
+	price := bson.M{}
+	if filter.MinPrice != nil {
+		price["$gte"] = *filter.MinPrice
+	}
+	if filter.MaxPrice != nil {
+		price["$lte"] = *filter.MaxPrice
+	}
+
+	if len(filter.Attributes) > 0 {
+		// Los atributos, el precio y la disponibilidad deben cumplirse en una misma variante.
+		variant := bson.M{}
+		for name, value := range filter.Attributes {
+			variant["attributes."+name] = value
+		}
+		if len(price) > 0 {
+			variant["price.amount"] = price
+		}
+		if filter.InStock {
+			variant["stock"] = bson.M{"$gt": 0}
+		}
+		match["variants"] = bson.M{"$elemMatch": variant}
+	} else {
+		if len(price) > 0 {
+			// Un producto con variantes entra en el rango si alguna de ellas lo hace.
+			match["$or"] = bson.A{
+				bson.M{"variants.0": bson.M{"$exists": false}, "price.amount": price},
+				bson.M{"variants": bson.M{"$elemMatch": bson.M{"price.amount": price}}},
+			}
+		}
+		if filter.InStock {
+			match["stock"] = bson.M{"$gt": 0}
+		}
+	}
+	return match
+}
+
+// productSort traduce el orden pedido a la etapa $sort. Sin orden explícito, una búsqueda de texto
+// se ordena por relevancia y el resto por orden de creación. El _id desempata para que la paginación sea estable.
+func productSort(filter models.ProductFilter) bson.D {
+	field := strings.TrimPrefix(filter.Sort, "-")
+	direction := 1
+	if strings.HasPrefix(filter.Sort, "-") {
+		direction = -1
+	}
+
+	switch field {
+	case "price":
+		return bson.D{{Key: "price.amount", Value: direction}, {Key: "_id", Value: 1}}
+	case "stock":
+		return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: 1}}
+	case "rating":
+		return bson.D{{Key: "rating_summary.average", Value: direction}, {Key: "_id", Value: 1}}
+	case "created":
+		// El ObjectID comienza con la fecha de creación del documento.
+		return bson.D{{Key: "_id", Value: direction}}
+	}
+
+	if filter.Query != "" {
+		return bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}
+	}
+	return bson.D{{Key: "_id", Value: 1}}
 }
 
 // FindOne busca un producto por su ID en la colección.
@@ -58,10 +265,10 @@ func (r *ProductRepository) FindOne(ctx context.Context, id string) (*models.Pro
 	}
 
 	// Realiza la búsqueda del producto en la colección usando el ObjectID.
-	result := r.collection.FindOne(ctx, bson.M{"_id": objID})
+	result := r.collection.FindOne(ctx, active(bson.M{"_id": objID}))
 	if err := result.Err(); err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("product not found with ID: %s", id)
+			return nil, fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
 		}
 		return nil, fmt.Errorf("error finding product: %v", err)
 	}
@@ -74,34 +281,49 @@ func (r *ProductRepository) FindOne(ctx context.Context, id string) (*models.Pro
 	return &product, nil
 }
 
-// Create inserta un nuevo producto en la colección y devuelve el resultado de la operación.
-func (r *ProductRepository) Create(ctx context.Context, product models.Product) error {
-	_, err := r.collection.InsertOne(ctx, product)
+// Create inserta un nuevo producto en la colección y devuelve el ID asignado.
+func (r *ProductRepository) Create(ctx context.Context, product models.Product) (string, error) {
+	result, err := r.collection.InsertOne(ctx, product)
 	if err != nil {
-		return fmt.Errorf("error inserting product: %v", err) // Mensaje de error informativo.
+		if mongo.IsDuplicateKeyError(err) {
+			return "", duplicateProductError(err)
+		}
+		return "", fmt.Errorf("error inserting product: %v", err) // Mensaje de error informativo.
 	}
 
-	return nil
+	if objID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		return objID.Hex(), nil
+	}
+	return fmt.Sprint(result.InsertedID), nil
 }
 
-// Delete elimina un producto por ID en la colección y devuelve un error si lo hay.
+// Delete envía un producto a la papelera marcándolo con la fecha de borrado; conserva sus reseñas,
+// su stock y su historial hasta que se restaure o se purgue. Un producto con reservas abiertas
+// no se borra y se retorna models.ErrProductReserved.
 func (r *ProductRepository) Delete(ctx context.Context, id string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return fmt.Errorf("invalid product ID: %v", err)
 	}
 
-	// Elimina el producto en la colección
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	// Marca el producto solo si no estaba ya en la papelera.
+	result, err := r.collection.UpdateOne(ctx, unreserved(active(bson.M{"_id": objID})), bson.M{"$set": bson.M{"deleted_at": time.Now().UTC()}})
 	if err != nil {
 		return fmt.Errorf("error deleting product: %v", err)
 	}
-	//Si es 0 retorna error ya que no fue encontrado el producto.
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("no product found with ID: %s", id)
+	if result.MatchedCount > 0 {
+		return nil
 	}
 
-	return nil
+	// Distingue un producto inexistente de uno con unidades reservadas.
+	count, err := r.collection.CountDocuments(ctx, active(bson.M{"_id": objID}))
+	if err != nil {
+		return fmt.Errorf("error finding product: %v", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	return models.ErrProductReserved
 }
 
 func (r *ProductRepository) Update(ctx context.Context, id string, product map[string]interface{}) error {
@@ -110,12 +332,15 @@ func (r *ProductRepository) Update(ctx context.Context, id string, product map[s
 		return fmt.Errorf("invalid product ID: %v", err)
 	}
 
-	result, err := r.collection.UpdateByID(ctx, objID, bson.M{"$set": product})
+	result, err := r.collection.UpdateOne(ctx, active(bson.M{"_id": objID}), bson.M{"$set": product})
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return duplicateProductError(err)
+		}
 		return err
 	}
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("no product found with ID: %s", id)
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
 	}
 
 	return nil
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestProductMatch(t *testing.T) {
+	minPrice, maxPrice, rating := uint(1000), uint(5000), 4.0
+	price := bson.M{"$gte": minPrice, "$lte": maxPrice}
+
+	tc := []struct {
+		Name     string
+		Filter   models.ProductFilter
+		Expected bson.M
+	}{
+		{
+			Name:     "Without criteria",
+			Expected: bson.M{"deleted_at": nil},
+		},
+		{
+			Name:   "Text search, category and rating",
+			Filter: models.ProductFilter{Query: "mechanical keyboard", Category: "electronics", MinRating: &rating},
+			Expected: bson.M{
+				"deleted_at":             nil,
+				"$text":                  bson.M{"$search": "mechanical keyboard"},
+				"category":               bson.M{"$regex": "^electronics$", "$options": "i"},
+				"rating_summary.average": bson.M{"$gte": rating},
+			},
+		},
+		{
+			Name:   "Price range and stock",
+			Filter: models.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice, Currency: "USD", InStock: true},
+			Expected: bson.M{
+				"deleted_at":     nil,
+				"price.currency": "USD",
+				"stock":          bson.M{"$gt": 0},
+				"$or": bson.A{
+					bson.M{"variants.0": bson.M{"$exists": false}, "price.amount": price},
+					bson.M{"variants": bson.M{"$elemMatch": bson.M{"price.amount": price}}},
+				},
+			},
+		},
+		{
+			Name:   "Attributes apply price and stock to the same variant",
+			Filter: models.ProductFilter{Attributes: map[string]string{"size": "M"}, MinPrice: &minPrice, MaxPrice: &maxPrice, InStock: true},
+			Expected: bson.M{
+				"deleted_at": nil,
+				"variants": bson.M{"$elemMatch": bson.M{
+					"attributes.size": "M",
+					"price.amount":    price,
+					"stock":           bson.M{"$gt": 0},
+				}},
+			},
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if match := productMatch(tc.Filter); !reflect.DeepEqual(match, tc.Expected) {
+				t.Fatalf("unexpected match: got %v want %v", match, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestProductSort(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Filter   models.ProductFilter
+		Expected bson.D
+	}{
+		{Name: "Default", Expected: bson.D{{Key: "_id", Value: 1}}},
+		{Name: "Relevance", Filter: models.ProductFilter{Query: "keyboard"}, Expected: bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "_id", Value: 1}}},
+		{Name: "Price descending", Filter: models.ProductFilter{Query: "keyboard", Sort: "-price"}, Expected: bson.D{{Key: "price.amount", Value: -1}, {Key: "_id", Value: 1}}},
+		{Name: "Stock", Filter: models.ProductFilter{Sort: "stock"}, Expected: bson.D{{Key: "stock", Value: 1}, {Key: "_id", Value: 1}}},
+		{Name: "Rating", Filter: models.ProductFilter{Sort: "-rating"}, Expected: bson.D{{Key: "rating_summary.average", Value: -1}, {Key: "_id", Value: 1}}},
+		{Name: "Newest first", Filter: models.ProductFilter{Sort: "-created"}, Expected: bson.D{{Key: "_id", Value: -1}}},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if sort := productSort(tc.Filter); !reflect.DeepEqual(sort, tc.Expected) {
+				t.Fatalf("unexpected sort: got %v want %v", sort, tc.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrateLegacyPrices convierte los precios numéricos sin moneda del producto y de sus variantes en importes
+// en unidades menores de currency; scale es la cantidad de unidades menores que representaba cada unidad antigua.
+func (r *ProductRepository) MigrateLegacyPrices(ctx context.Context, currency string, scale int64) error {
+	money := func(price string) bson.M {
+		return bson.M{"$cond": bson.A{
+			bson.M{"$isNumber": price},
+			bson.M{
+				"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{price, scale}}, 0}}},
+				"currency": currency,
+			},
+			price,
+		}}
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"price": bson.M{"$type": "number"}},
+		bson.M{"variants.price": bson.M{"$type": "number"}},
+	}}
+	_, err := r.collection.UpdateMany(ctx, filter, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"price": money("$price"),
+			"variants": bson.M{"$cond": bson.A{
+				bson.M{"$isArray": "$variants"},
+				bson.M{"$map": bson.M{
+					"input": "$variants",
+					"as":    "v",
+					"in":    bson.M{"$mergeObjects": bson.A{"$$v", bson.M{"price": money("$$v.price")}}},
+				}},
+				"$$REMOVE",
+			}},
+		}}},
+	})
+	if err != nil {
+		return fmt.Errorf("error migrating legacy prices: %v", err)
+	}
+	return nil
+}
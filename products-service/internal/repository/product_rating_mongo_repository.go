@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ratingAverage es la etapa que recalcula el promedio a partir de la suma y el conteo.
+var ratingAverage = bson.D{{Key: "$set", Value: bson.M{"rating_summary.average": bson.M{"$cond": bson.A{
+	bson.M{"$gt": bson.A{"$rating_summary.count", 0}},
+	bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$rating_summary.sum", "$rating_summary.count"}}, 2}},
+	0,
+}}}}}
+
+// ApplyRatingChange ajusta atómicamente la suma y el conteo de calificaciones del producto y recalcula el promedio
+func (r *ProductRepository) ApplyRatingChange(ctx context.Context, id string, sumDelta, countDelta int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	result, err := r.collection.UpdateByID(ctx, objID, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"rating_summary.sum":   bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_summary.sum", 0}}, sumDelta}},
+			"rating_summary.count": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$rating_summary.count", 0}}, countDelta}},
+		}}},
+		ratingAverage,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating rating: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+
+	return nil
+}
+
+// MigrateLegacyRatings convierte el antiguo arreglo rating de cada producto en su agregado, de modo
+// que los promedios existentes se conservan; las reseñas nuevas se suman a ellos.
+func (r *ProductRepository) MigrateLegacyRatings(ctx context.Context) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"rating": bson.M{"$type": "array"}}, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"rating_summary.sum":   bson.M{"$sum": "$rating"},
+			"rating_summary.count": bson.M{"$size": "$rating"},
+		}}},
+		ratingAverage,
+		{{Key: "$unset", Value: "rating"}},
+	})
+	if err != nil {
+		return fmt.Errorf("error migrating legacy ratings: %v", err)
+	}
+	return nil
+}
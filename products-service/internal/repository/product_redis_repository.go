@@ -4,78 +4,208 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
 	"github.com/redis/go-redis/v9"
 )
 
+// Todas las claves del servicio viven bajo su prefijo, de modo que una instancia de Redis
+// compartida nunca se modifica fuera de ese espacio:
+//
+//	<prefix>:product:<id>                 un producto
+//	<prefix>:products:version             versión vigente, que aumenta con cada invalidación
+//	<prefix>:products:v<version>:<clave>  una página de un listado
+//	<prefix>:products:invalidations       canal pub/sub de invalidaciones (modo de dos niveles)
+//
+// Incrementar la versión invalida todas las páginas a la vez; las de versiones anteriores expiran solas.
+// Un producto solo se guarda si la versión no cambió desde antes de cargarlo de MongoDB.
+
 // ProductRedisRepository interactúa con Redis para el almacenamiento en caché de productos
 type ProductRedisRepository struct {
 	client *redis.Client
+	prefix string
 }
 
-// NewProductRedisRepository inicializa un nuevo repositorio Redis para productos
-func NewProductRedisRepository(client *redis.Client) *ProductRedisRepository {
-	return &ProductRedisRepository{client: client}
+// NewProductRedisRepository inicializa un nuevo repositorio Redis para productos bajo el prefijo indicado
+func NewProductRedisRepository(client *redis.Client, prefix string) *ProductRedisRepository {
+	return &ProductRedisRepository{client: client, prefix: prefix}
 }
 
-// GetAll obtiene todos los productos del caché usando una clave
-func (r *ProductRedisRepository) GetAll(ctx context.Context, key string) ([]models.Product, error) {
-	data, err := r.client.Get(ctx, key).Result()
+// ListVersion obtiene la versión vigente de los listados; 0 si aún no se ha invalidado ninguno
+func (r *ProductRedisRepository) ListVersion(ctx context.Context) (int64, error) {
+	version, err := r.client.Get(ctx, r.key("products", "version")).Int64()
 	if err == redis.Nil {
-		// No se encontró en el caché
-		return nil, nil
-	} else if err != nil {
-		return nil, err
+		return 0, nil
 	}
+	return version, err
+}
 
-	var products []models.Product
-	// Deserializa los datos almacenados en caché a una lista de productos
-	if err := json.Unmarshal([]byte(data), &products); err != nil {
-		return nil, errors.New("failed to unmarshal products from cache")
+// GetPage obtiene una página de un listado de productos del caché y el momento en que se guardó
+func (r *ProductRedisRepository) GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error) {
+	var page models.ProductPage
+	storedAt, found, err := r.get(ctx, r.pageKey(version, key), &page)
+	if err != nil || !found {
+		return nil, time.Time{}, err
 	}
+	return &page, storedAt, nil
+}
 
-	return products, nil
+// SetPage almacena en caché una página de un listado de productos durante ttl
+func (r *ProductRedisRepository) SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error {
+	return r.set(ctx, r.pageKey(version, key), page, ttl)
 }
 
-// GetOne obtiene un solo producto del caché usando una clave
-func (r *ProductRedisRepository) GetOne(ctx context.Context, key string) (*models.Product, error) {
-	data, err := r.client.Get(ctx, key).Result()
+// GetOne obtiene un solo producto del caché por su ID y el momento en que se guardó
+func (r *ProductRedisRepository) GetOne(ctx context.Context, id string) (*models.Product, time.Time, error) {
+	var product models.Product
+	storedAt, found, err := r.get(ctx, r.key("product", id), &product)
+	if err != nil || !found {
+		return nil, time.Time{}, err
+	}
+	return &product, storedAt, nil
+}
+
+// setIfVersion guarda KEYS[2] solo si la versión KEYS[1] sigue siendo ARGV[1]; comparar y escribir en un
+// script evita que una invalidación se cuele entre ambos pasos
+var setIfVersion = redis.NewScript(`
+if tonumber(redis.call("GET", KEYS[1]) or "0") ~= tonumber(ARGV[1]) then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[2], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+// SetOne almacena en caché un producto bajo su ID durante ttl, salvo que la versión haya cambiado desde que
+// se leyó: el producto pudo cargarse de MongoDB antes de una escritura cuya invalidación ya se aplicó
+func (r *ProductRedisRepository) SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error {
+	data, err := r.encode(product)
+	if err != nil {
+		return err
+	}
+	keys := []string{r.key("products", "version"), r.key("product", id)}
+	return setIfVersion.Run(ctx, r.client, keys, version, data, ttl.Milliseconds()).Err()
+}
+
+// InvalidateProducts elimina del caché los productos indicados e invalida todos los listados
+func (r *ProductRedisRepository) InvalidateProducts(ctx context.Context, ids ...string) error {
+	pipe := r.client.TxPipeline()
+	for _, id := range ids {
+		pipe.Del(ctx, r.key("product", id))
+	}
+	pipe.Incr(ctx, r.key("products", "version"))
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.New("failed to invalidate Redis cache")
+	}
+	return nil
+}
+
+// InvalidateAll elimina todas las claves de productos del prefijo e invalida todos los listados
+func (r *ProductRedisRepository) InvalidateAll(ctx context.Context) error {
+	iter := r.client.Scan(ctx, 0, r.key("product", "*"), 100).Iterator()
+
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 100 {
+			if err := r.client.Del(ctx, keys...).Err(); err != nil {
+				return errors.New("failed to invalidate Redis cache")
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return errors.New("failed to invalidate Redis cache")
+	}
+
+	pipe := r.client.TxPipeline()
+	if len(keys) > 0 {
+		pipe.Del(ctx, keys...)
+	}
+	pipe.Incr(ctx, r.key("products", "version"))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.New("failed to invalidate Redis cache")
+	}
+	return nil
+}
+
+// PublishInvalidation difunde un mensaje de invalidación a las demás instancias del servicio
+func (r *ProductRedisRepository) PublishInvalidation(ctx context.Context, payload []byte) error {
+	if err := r.client.Publish(ctx, r.key("products", "invalidations"), payload).Err(); err != nil {
+		return errors.New("failed to publish cache invalidation")
+	}
+	return nil
+}
+
+// SubscribeInvalidations se suscribe a los mensajes de invalidación del prefijo
+func (r *ProductRedisRepository) SubscribeInvalidations(ctx context.Context) *redis.PubSub {
+	return r.client.Subscribe(ctx, r.key("products", "invalidations"))
+}
+
+func (r *ProductRedisRepository) key(parts ...string) string {
+	return r.prefix + ":" + strings.Join(parts, ":")
+}
+
+func (r *ProductRedisRepository) pageKey(version int64, key string) string {
+	return r.key("products", "v"+strconv.FormatInt(version, 10), key)
+}
+
+// cacheEntry guarda junto al valor el momento en que se escribió, para que el servicio
+// pueda decidir si todavía está fresco.
+type cacheEntry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// get lee y deserializa una clave; found es false si no está en el caché
+func (r *ProductRedisRepository) get(ctx context.Context, key string, dest interface{}) (time.Time, bool, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
 		// No se encontró en el caché
-		return nil, nil
+		return time.Time{}, false, nil
 	}
 	if err != nil {
-		return nil, err
+		return time.Time{}, false, err
 	}
 
-	var product models.Product
-	// Deserializa los datos almacenados en caché a un producto
-	if err := json.Unmarshal([]byte(data), &product); err != nil {
-		return nil, errors.New("failed to unmarshal product from cache")
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return time.Time{}, false, errors.New("failed to unmarshal payload from cache")
 	}
-
-	return &product, nil
+	if err := json.Unmarshal(entry.Value, dest); err != nil {
+		return time.Time{}, false, errors.New("failed to unmarshal payload from cache")
+	}
+	return entry.StoredAt, true, nil
 }
 
-// Set almacena en caché los datos proporcionados (producto) en Redis
-func (r *ProductRedisRepository) Set(ctx context.Context, key string, payload interface{}) error {
-	data, err := json.Marshal(payload)
+// set almacena los datos serializados en JSON con la expiración indicada
+func (r *ProductRedisRepository) set(ctx context.Context, key string, payload interface{}, ttl time.Duration) error {
+	data, err := r.encode(payload)
 	if err != nil {
-		return errors.New("failed to marshal payload to JSON")
+		return err
 	}
-	// Almacena los datos en caché con una expiración de 60 segundos
-	if err := r.client.Set(ctx, key, data, time.Second*60).Err(); err != nil {
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Clean elimina todos los datos almacenados en caché de Redis
-func (r *ProductRedisRepository) Clean(ctx context.Context) error {
-	if err := r.client.FlushAll(ctx).Err(); err != nil {
-		return errors.New("failed to flush all Redis data")
+// encode serializa los datos en JSON dentro de una entrada con el momento actual
+func (r *ProductRedisRepository) encode(payload interface{}) ([]byte, error) {
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.New("failed to marshal payload to JSON")
 	}
-	return nil
+	data, err := json.Marshal(cacheEntry{StoredAt: time.Now().UTC(), Value: value})
+	if err != nil {
+		return nil, errors.New("failed to marshal payload to JSON")
+	}
+	return data, nil
 }
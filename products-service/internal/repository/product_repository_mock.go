@@ -0,0 +1,732 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProductRepositoryMocked es un repositorio de productos en memoria para pruebas.
+type ProductRepositoryMocked struct {
+	mu       sync.Mutex
+	products map[string]models.Product
+	order    []string
+}
+
+// NewProductRepositoryMocked crea un repositorio en memoria con los productos indicados.
+func NewProductRepositoryMocked(products ...models.Product) *ProductRepositoryMocked {
+	r := &ProductRepositoryMocked{products: map[string]models.Product{}}
+	for _, product := range products {
+		r.products[product.ID] = product
+		r.order = append(r.order, product.ID)
+	}
+	return r
+}
+
+func (r *ProductRepositoryMocked) FindAll(ctx context.Context, filter models.ProductFilter, page models.PageRequest) ([]models.Product, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	active := []models.Product{}
+	for _, id := range r.order {
+		if product := r.products[id]; product.DeletedAt == nil {
+			active = append(active, product)
+		}
+	}
+
+	products := []models.Product{}
+	if page.CursorMode {
+		// Como en MongoDB, el cursor sigue el orden de los IDs y se pide un producto de más.
+		slices.SortFunc(active, func(a, b models.Product) int { return strings.Compare(a.ID, b.ID) })
+		for _, product := range active {
+			if product.ID > page.After && len(products) <= page.Size {
+				products = append(products, product)
+			}
+		}
+		return products, int64(len(active)), nil
+	}
+	start := (page.Page - 1) * page.Size
+	for i := start; i < len(active) && len(products) < page.Size; i++ {
+		products = append(products, active[i])
+	}
+	return products, int64(len(active)), nil
+}
+
+// StreamAll recorre los productos en orden de creación; del filtro solo aplica los IDs y la categoría.
+func (r *ProductRepositoryMocked) StreamAll(ctx context.Context, filter models.ProductFilter, fn func(product *models.Product) error) error {
+	r.mu.Lock()
+	products := make([]models.Product, 0, len(r.order))
+	for _, id := range r.order {
+		if matchesMock(r.products[id], filter) {
+			products = append(products, r.products[id])
+		}
+	}
+	r.mu.Unlock()
+
+	for i := range products {
+		if err := fn(&products[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ProductRepositoryMocked) FindOne(ctx context.Context, id string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return nil, fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	return &product, nil
+}
+
+func (r *ProductRepositoryMocked) FindBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.order {
+		product := r.products[id]
+		if product.DeletedAt != nil {
+			continue
+		}
+		if product.SKU == sku {
+			return &product, nil
+		}
+		for _, variant := range product.Variants {
+			if variant.SKU == sku {
+				return &product, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w with SKU %s", models.ErrProductNotFound, sku)
+}
+
+func (r *ProductRepositoryMocked) FindByBarcode(ctx context.Context, code string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.order {
+		product := r.products[id]
+		if product.DeletedAt != nil {
+			continue
+		}
+		for _, barcode := range product.Barcodes {
+			if barcode == code {
+				return &product, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w with barcode %s", models.ErrProductNotFound, code)
+}
+
+func (r *ProductRepositoryMocked) Create(ctx context.Context, product models.Product) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.products {
+		if product.SKU != "" && existing.SKU == product.SKU {
+			return "", models.ErrDuplicateSKU
+		}
+		for _, barcode := range product.Barcodes {
+			for _, other := range existing.Barcodes {
+				if barcode == other {
+					return "", models.ErrDuplicateBarcode
+				}
+			}
+		}
+	}
+
+	product.ID = primitive.NewObjectID().Hex()
+	r.products[product.ID] = product
+	r.order = append(r.order, product.ID)
+	return product.ID, nil
+}
+
+func (r *ProductRepositoryMocked) FindBySKUs(ctx context.Context, skus []string) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, id := range r.order {
+		for _, sku := range skus {
+			if r.products[id].SKU == sku {
+				products = append(products, r.products[id])
+			}
+		}
+	}
+	return products, nil
+}
+
+// UpsertBySKU aplica los productos uno a uno; un código de barras de otro producto o un SKU en la papelera
+// fallan solo esa fila.
+func (r *ProductRepositoryMocked) UpsertBySKU(ctx context.Context, products []models.Product) ([]models.ProductWrite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]models.ProductWrite, len(products))
+	for i, product := range products {
+		var existing *models.Product
+		for _, id := range r.order {
+			if r.products[id].SKU == product.SKU {
+				current := r.products[id]
+				existing = &current
+			}
+		}
+		if r.hasBarcode(product.SKU, product.Barcodes) {
+			results[i].Err = models.ErrDuplicateBarcode
+			continue
+		}
+		if existing != nil && existing.DeletedAt != nil {
+			results[i].Err = models.ErrDuplicateSKU
+			continue
+		}
+
+		if existing == nil {
+			product.ID = primitive.NewObjectID().Hex()
+			r.products[product.ID] = product
+			r.order = append(r.order, product.ID)
+			results[i] = models.ProductWrite{ID: product.ID, Created: true}
+			continue
+		}
+		existing.Title = product.Title
+		existing.Description = product.Description
+		existing.Category = product.Category
+		existing.Price = product.Price
+		existing.Barcodes = product.Barcodes
+		existing.ReorderPoint = product.ReorderPoint
+		existing.ReorderQuantity = product.ReorderQuantity
+		r.products[existing.ID] = *existing
+	}
+	return results, nil
+}
+
+// hasBarcode indica si otro producto distinto del SKU indicado ya tiene alguno de los códigos.
+func (r *ProductRepositoryMocked) hasBarcode(sku string, barcodes []string) bool {
+	for _, existing := range r.products {
+		if existing.SKU == sku {
+			continue
+		}
+		for _, barcode := range barcodes {
+			for _, other := range existing.Barcodes {
+				if barcode == other {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (r *ProductRepositoryMocked) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	if product.Reserved > 0 {
+		return models.ErrProductReserved
+	}
+	now := time.Now().UTC()
+	product.DeletedAt = &now
+	r.products[id] = product
+	return nil
+}
+
+func (r *ProductRepositoryMocked) FindDeleted(ctx context.Context, page, size int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, id := range r.order {
+		if product := r.products[id]; product.DeletedAt != nil {
+			products = append(products, product)
+		}
+	}
+	slices.SortStableFunc(products, func(a, b models.Product) int {
+		return b.DeletedAt.Compare(*a.DeletedAt)
+	})
+	return products, nil
+}
+
+func (r *ProductRepositoryMocked) Restore(ctx context.Context, id string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok || product.DeletedAt == nil {
+		return nil, fmt.Errorf("%w in the trash with ID: %s", models.ErrProductNotFound, id)
+	}
+	product.DeletedAt = nil
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *ProductRepositoryMocked) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, id := range r.order {
+		if product := r.products[id]; product.DeletedAt != nil && product.DeletedAt.Before(before) && product.Reserved == 0 && len(products) < limit {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryMocked) Purge(ctx context.Context, id string, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok || product.DeletedAt == nil || !product.DeletedAt.Before(before) || product.Reserved > 0 {
+		return fmt.Errorf("%w in the trash with ID: %s", models.ErrProductNotFound, id)
+	}
+	delete(r.products, id)
+	r.order = slices.DeleteFunc(r.order, func(productID string) bool { return productID == id })
+	return nil
+}
+
+// active retorna el producto si existe y no está en la papelera.
+func (r *ProductRepositoryMocked) active(id string) (models.Product, bool) {
+	product, ok := r.products[id]
+	return product, ok && product.DeletedAt == nil
+}
+
+func (r *ProductRepositoryMocked) Update(ctx context.Context, id string, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	if title, ok := fields["title"].(string); ok {
+		product.Title = title
+	}
+	if price, ok := fields["price"].(models.Money); ok {
+		product.Price = price
+	}
+	r.products[id] = product
+	return nil
+}
+
+func (r *ProductRepositoryMocked) BulkUpdate(ctx context.Context, filter models.ProductFilter, fields map[string]interface{}) (*models.BulkResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &models.BulkResult{}
+	for id, product := range r.products {
+		if !matchesMock(product, filter) {
+			continue
+		}
+		result.Matched++
+		updated := product
+		if category, ok := fields["category"].(string); ok {
+			updated.Category = category
+		}
+		if price, ok := fields["price"].(models.Money); ok {
+			updated.Price = price
+		}
+		if updated.Category != product.Category || updated.Price != product.Price {
+			result.Modified++
+		}
+		r.products[id] = updated
+	}
+	return result, nil
+}
+
+func (r *ProductRepositoryMocked) BulkDelete(ctx context.Context, filter models.ProductFilter) (*models.BulkResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &models.BulkResult{}
+	now := time.Now().UTC()
+	for id, product := range r.products {
+		if matchesMock(product, filter) && product.Reserved == 0 {
+			product.DeletedAt = &now
+			r.products[id] = product
+			result.Matched++
+			result.Modified++
+		}
+	}
+	return result, nil
+}
+
+// matchesMock aplica del filtro solo los IDs y la categoría; los productos en la papelera no coinciden.
+func matchesMock(product models.Product, filter models.ProductFilter) bool {
+	if product.DeletedAt != nil {
+		return false
+	}
+	if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, product.ID) {
+		return false
+	}
+	return filter.Category == "" || product.Category == filter.Category
+}
+
+func (r *ProductRepositoryMocked) ApplyStockChange(ctx context.Context, id, warehouseID, variantID string, stockDelta, reservedDelta int64) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if variantID != "" && warehouseID != "" {
+		return nil, errors.New("variant stock cannot be assigned to a warehouse")
+	}
+	product, ok := r.products[id]
+	// Un producto en la papelera solo admite los cambios que liberan unidades reservadas.
+	if !ok || (product.DeletedAt != nil && reservedDelta >= 0) {
+		return nil, models.ErrProductNotFound
+	}
+	if len(product.Variants) > 0 && variantID == "" {
+		return nil, models.ErrVariantRequired
+	}
+	if variantID != "" {
+		product.Variants = append([]models.Variant{}, product.Variants...)
+		variant, ok := product.FindVariant(variantID)
+		if !ok {
+			return nil, models.ErrVariantNotFound
+		}
+		if int64(variant.Stock)+stockDelta < 0 || int64(variant.Reserved)+reservedDelta < 0 {
+			return nil, models.ErrInsufficientStock
+		}
+		variant.Stock = uint(int64(variant.Stock) + stockDelta)
+		variant.Reserved = uint(int64(variant.Reserved) + reservedDelta)
+	} else if !moveLocationStock(&product, warehouseID, stockDelta) {
+		return nil, models.ErrInsufficientStock
+	}
+	if int64(product.Stock)+stockDelta < 0 || int64(product.Reserved)+reservedDelta < 0 {
+		return nil, models.ErrInsufficientStock
+	}
+	product.Stock = uint(int64(product.Stock) + stockDelta)
+	product.Reserved = uint(int64(product.Reserved) + reservedDelta)
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *ProductRepositoryMocked) TransferStock(ctx context.Context, id, fromWarehouseID, toWarehouseID string, quantity int64) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return nil, models.ErrProductNotFound
+	}
+	if len(product.Variants) > 0 {
+		return nil, models.ErrVariantRequired
+	}
+	if !moveLocationStock(&product, fromWarehouseID, -quantity) {
+		return nil, models.ErrInsufficientStock
+	}
+	moveLocationStock(&product, toWarehouseID, quantity)
+	r.products[id] = product
+	return &product, nil
+}
+
+// moveLocationStock aplica delta a la ubicación del almacén, o al stock sin asignar si warehouseID
+// está vacío, sin tocar el total. Retorna false si la ubicación no tiene unidades suficientes.
+func moveLocationStock(product *models.Product, warehouseID string, delta int64) bool {
+	if warehouseID == "" {
+		unassigned := int64(product.Stock)
+		for _, location := range product.Locations {
+			unassigned -= int64(location.Quantity)
+		}
+		return unassigned+delta >= 0
+	}
+
+	product.Locations = append([]models.StockLocation{}, product.Locations...)
+	i := slices.IndexFunc(product.Locations, func(l models.StockLocation) bool { return l.WarehouseID == warehouseID })
+	if i < 0 {
+		product.Locations = append(product.Locations, models.StockLocation{WarehouseID: warehouseID})
+		i = len(product.Locations) - 1
+	}
+	if int64(product.Locations[i].Quantity)+delta < 0 {
+		return false
+	}
+	product.Locations[i].Quantity = uint(int64(product.Locations[i].Quantity) + delta)
+	return true
+}
+
+func (r *ProductRepositoryMocked) CountByWarehouse(ctx context.Context, warehouseID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, product := range r.products {
+		for _, location := range product.Locations {
+			if location.WarehouseID == warehouseID && location.Quantity > 0 {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (r *ProductRepositoryMocked) RemoveWarehouse(ctx context.Context, warehouseID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := []string{}
+	for _, id := range r.order {
+		product := r.products[id]
+		locations := slices.DeleteFunc(slices.Clone(product.Locations), func(l models.StockLocation) bool { return l.WarehouseID == warehouseID })
+		if len(locations) != len(product.Locations) {
+			product.Locations = locations
+			r.products[id] = product
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *ProductRepositoryMocked) CountByCategory(ctx context.Context, category string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, product := range r.products {
+		if strings.EqualFold(product.Category, category) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *ProductRepositoryMocked) ReassignCategory(ctx context.Context, from, to string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for id, product := range r.products {
+		if strings.EqualFold(product.Category, from) {
+			product.Category = to
+			r.products[id] = product
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+func (r *ProductRepositoryMocked) AddVariant(ctx context.Context, id string, variant models.Variant) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return nil, fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	for _, existing := range r.products {
+		for _, other := range existing.Variants {
+			if other.SKU == variant.SKU {
+				return nil, models.ErrDuplicateSKU
+			}
+		}
+	}
+	if len(product.Variants) == 0 && (product.Stock > 0 || product.Reserved > 0) {
+		return nil, models.ErrProductHasStock
+	}
+	product.Variants = append(append([]models.Variant{}, product.Variants...), variant)
+	product.Stock += variant.Stock
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *ProductRepositoryMocked) UpdateVariant(ctx context.Context, id, variantID string, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	product.Variants = append([]models.Variant{}, product.Variants...)
+	variant, ok := product.FindVariant(variantID)
+	if !ok {
+		return models.ErrVariantNotFound
+	}
+	if sku, ok := fields["sku"].(string); ok {
+		variant.SKU = sku
+	}
+	if price, ok := fields["price"].(models.Money); ok {
+		variant.Price = price
+	}
+	if attributes, ok := fields["attributes"].(map[string]string); ok {
+		variant.Attributes = attributes
+	}
+	r.products[id] = product
+	return nil
+}
+
+func (r *ProductRepositoryMocked) RemoveVariant(ctx context.Context, id, variantID string) (*models.Variant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return nil, fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	variant, ok := product.FindVariant(variantID)
+	if !ok {
+		return nil, models.ErrVariantNotFound
+	}
+	if variant.Reserved > 0 {
+		return nil, models.ErrVariantReserved
+	}
+	removed := *variant
+
+	variants := []models.Variant{}
+	for _, v := range product.Variants {
+		if v.ID != variantID {
+			variants = append(variants, v)
+		}
+	}
+	product.Variants = variants
+	product.Stock -= removed.Stock
+	r.products[id] = product
+	return &removed, nil
+}
+
+func (r *ProductRepositoryMocked) FindLowStock(ctx context.Context, page, size int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, id := range r.order {
+		if product := r.products[id]; product.DeletedAt == nil && product.IsLowStock() {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryMocked) AddImage(ctx context.Context, id string, image models.ProductImage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	if slices.ContainsFunc(product.Images, func(i models.ProductImage) bool { return i.ID == image.ID }) {
+		return models.ErrDuplicateImage
+	}
+	product.Images = append(product.Images, image)
+	r.products[id] = product
+	return nil
+}
+
+func (r *ProductRepositoryMocked) RemoveImage(ctx context.Context, id, imageID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.active(id)
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	for i, image := range product.Images {
+		if image.ID == imageID {
+			product.Images = append(product.Images[:i:i], product.Images[i+1:]...)
+			r.products[id] = product
+			return nil
+		}
+	}
+	return fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+}
+
+func (r *ProductRepositoryMocked) SetImageThumbnail(ctx context.Context, id, imageID, key, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	images := append([]models.ProductImage(nil), product.Images...)
+	for i := range images {
+		if images[i].ID == imageID {
+			images[i].ThumbnailKey = &key
+			images[i].ThumbnailURL = url
+			product.Images = images
+			r.products[id] = product
+			return nil
+		}
+	}
+	return fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+}
+
+func (r *ProductRepositoryMocked) FindPendingThumbnails(ctx context.Context, limit int) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := []models.Product{}
+	for _, id := range r.order {
+		if r.products[id].DeletedAt != nil {
+			continue
+		}
+		for _, image := range r.products[id].Images {
+			if image.ThumbnailKey == nil {
+				products = append(products, r.products[id])
+				break
+			}
+		}
+		if len(products) == limit {
+			break
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryMocked) ApplyRatingChange(ctx context.Context, id string, sumDelta, countDelta int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[id]
+	if !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrProductNotFound, id)
+	}
+	product.Rating.Sum += sumDelta
+	product.Rating.Count += countDelta
+	product.Rating.Average = 0
+	if product.Rating.Count > 0 {
+		product.Rating.Average = math.Round(float64(product.Rating.Sum)/float64(product.Rating.Count)*100) / 100
+	}
+	r.products[id] = product
+	return nil
+}
+
+// MovementRepositoryMocked guarda los movimientos en memoria.
+type MovementRepositoryMocked struct {
+	mu        sync.Mutex
+	Movements []models.Movement
+}
+
+func (r *MovementRepositoryMocked) Create(ctx context.Context, movement models.Movement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Movements = append(r.Movements, movement)
+	return nil
+}
+
+func (r *MovementRepositoryMocked) FindByProduct(ctx context.Context, productID string, filter models.MovementFilter, page, size int) ([]models.Movement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	movements := []models.Movement{}
+	for _, movement := range r.Movements {
+		if movement.ProductID == productID {
+			movements = append(movements, movement)
+		}
+	}
+	return movements, nil
+}
@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// El stock disponible de un producto se reparte entre sus ubicaciones y un remanente sin asignar:
+// stock = suma(locations.quantity) + sin asignar. Todas las operaciones mantienen esa igualdad.
+// En los productos con variantes, en cambio, stock = suma(variants.stock) y reserved = suma(variants.reserved),
+// y solo se opera sobre una variante a la vez.
+
+// unassignedAtLeast retorna un filtro que exige al menos n unidades sin asignar a un almacén.
+func unassignedAtLeast(n int64) bson.M {
+	return bson.M{"$expr": bson.M{"$gte": bson.A{
+		bson.M{"$subtract": bson.A{"$stock", bson.M{"$sum": "$locations.quantity"}}},
+		n,
+	}}}
+}
+
+// locationAtLeast retorna un filtro que exige al menos n unidades en el almacén indicado.
+func locationAtLeast(warehouseID string, n int64) bson.M {
+	return bson.M{"locations": bson.M{"$elemMatch": bson.M{
+		"warehouse_id": warehouseID,
+		"quantity":     bson.M{"$gte": n},
+	}}}
+}
+
+// ensureLocation agrega una ubicación vacía para el almacén si el producto aún no la tiene.
+func (r *ProductRepository) ensureLocation(ctx context.Context, objID primitive.ObjectID, warehouseID string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "locations.warehouse_id": bson.M{"$ne": warehouseID}, "variants.0": bson.M{"$exists": false}},
+		bson.M{"$push": bson.M{"locations": models.StockLocation{WarehouseID: warehouseID}}},
+	)
+	if err != nil {
+		return fmt.Errorf("error adding stock location: %v", err)
+	}
+	return nil
+}
+
+// ApplyStockChange aplica de forma atómica los deltas al stock disponible y reservado del producto.
+// Si warehouseID no está vacío, el delta disponible también se aplica a esa ubicación; si está vacío,
+// se aplica al stock sin asignar. La actualización es condicional: si algún valor quedaría por debajo
+// de cero no se modifica nada y se retorna models.ErrInsufficientStock.
+// En los productos con variantes el cambio se aplica a la variante variantID, que es obligatoria.
+// Un producto en la papelera solo admite los cambios que liberan unidades reservadas, para que sus
+// reservas puedan confirmarse, liberarse o vencer.
+func (r *ProductRepository) ApplyStockChange(ctx context.Context, id, warehouseID, variantID string, stockDelta, reservedDelta int64) (*models.Product, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	if variantID != "" {
+		if warehouseID != "" {
+			return nil, errors.New("variant stock cannot be assigned to a warehouse")
+		}
+		return r.applyVariantStockChange(ctx, objID, variantID, stockDelta, reservedDelta)
+	}
+
+	filter := bson.M{"_id": objID, "variants.0": bson.M{"$exists": false}}
+	if reservedDelta < 0 {
+		filter["reserved"] = bson.M{"$gte": -reservedDelta}
+	} else {
+		filter["deleted_at"] = nil
+	}
+
+	inc := bson.M{"stock": stockDelta, "reserved": reservedDelta}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	if warehouseID == "" {
+		if stockDelta < 0 {
+			for k, v := range unassignedAtLeast(-stockDelta) {
+				filter[k] = v
+			}
+		}
+	} else if stockDelta != 0 {
+		if stockDelta > 0 {
+			if err := r.ensureLocation(ctx, objID, warehouseID); err != nil {
+				return nil, err
+			}
+		} else {
+			for k, v := range locationAtLeast(warehouseID, -stockDelta) {
+				filter[k] = v
+			}
+		}
+		inc["locations.$[loc].quantity"] = stockDelta
+		opts.SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"loc.warehouse_id": warehouseID}}})
+	}
+
+	product, err := r.updateStock(ctx, objID, filter, bson.M{"$inc": inc}, opts)
+	if errors.Is(err, models.ErrInsufficientStock) {
+		return nil, r.variantStockError(ctx, objID, "")
+	}
+	return product, err
+}
+
+// applyVariantStockChange aplica los deltas a la variante y, en la misma actualización, a los totales del producto.
+func (r *ProductRepository) applyVariantStockChange(ctx context.Context, objID primitive.ObjectID, variantID string, stockDelta, reservedDelta int64) (*models.Product, error) {
+	variant := bson.M{"id": variantID}
+	if stockDelta < 0 {
+		variant["stock"] = bson.M{"$gte": -stockDelta}
+	}
+	filter := bson.M{"_id": objID}
+	if reservedDelta < 0 {
+		variant["reserved"] = bson.M{"$gte": -reservedDelta}
+	} else {
+		filter["deleted_at"] = nil
+	}
+	filter["variants"] = bson.M{"$elemMatch": variant}
+
+	inc := bson.M{
+		"stock":                  stockDelta,
+		"reserved":               reservedDelta,
+		"variants.$[v].stock":    stockDelta,
+		"variants.$[v].reserved": reservedDelta,
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"v.id": variantID}}})
+
+	product, err := r.updateStock(ctx, objID, filter, bson.M{"$inc": inc}, opts)
+	if errors.Is(err, models.ErrInsufficientStock) {
+		return nil, r.variantStockError(ctx, objID, variantID)
+	}
+	return product, err
+}
+
+// variantStockError explica por qué un producto existente no cumplió el filtro de una actualización
+// de stock: le faltaba la variante indicada, tenía variantes y no se indicó ninguna, o no le alcanzaba el stock.
+func (r *ProductRepository) variantStockError(ctx context.Context, objID primitive.ObjectID, variantID string) error {
+	if variantID == "" {
+		count, err := r.collection.CountDocuments(ctx, bson.M{"_id": objID, "variants.0": bson.M{"$exists": true}})
+		if err != nil {
+			return fmt.Errorf("error finding product: %v", err)
+		}
+		if count > 0 {
+			return models.ErrVariantRequired
+		}
+		return models.ErrInsufficientStock
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": objID, "variants.id": variantID})
+	if err != nil {
+		return fmt.Errorf("error finding product: %v", err)
+	}
+	if count == 0 {
+		return models.ErrVariantNotFound
+	}
+	return models.ErrInsufficientStock
+}
+
+// TransferStock mueve unidades disponibles entre dos ubicaciones del producto sin cambiar su total.
+// Un almacén vacío representa el stock sin asignar.
+func (r *ProductRepository) TransferStock(ctx context.Context, id, fromWarehouseID, toWarehouseID string, quantity int64) (*models.Product, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	if toWarehouseID != "" {
+		if err := r.ensureLocation(ctx, objID, toWarehouseID); err != nil {
+			return nil, err
+		}
+	}
+
+	var filter bson.M
+	if fromWarehouseID == "" {
+		filter = unassignedAtLeast(quantity)
+	} else {
+		filter = locationAtLeast(fromWarehouseID, quantity)
+	}
+	filter["_id"] = objID
+	filter["deleted_at"] = nil
+	filter["variants.0"] = bson.M{"$exists": false}
+
+	inc := bson.M{}
+	var arrayFilters []interface{}
+	if fromWarehouseID != "" {
+		inc["locations.$[from].quantity"] = -quantity
+		arrayFilters = append(arrayFilters, bson.M{"from.warehouse_id": fromWarehouseID})
+	}
+	if toWarehouseID != "" {
+		inc["locations.$[to].quantity"] = quantity
+		arrayFilters = append(arrayFilters, bson.M{"to.warehouse_id": toWarehouseID})
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetArrayFilters(options.ArrayFilters{Filters: arrayFilters})
+
+	product, err := r.updateStock(ctx, objID, filter, bson.M{"$inc": inc}, opts)
+	if errors.Is(err, models.ErrInsufficientStock) {
+		return nil, r.variantStockError(ctx, objID, "")
+	}
+	return product, err
+}
+
+// CountByWarehouse cuenta los productos que tienen unidades en el almacén indicado.
+func (r *ProductRepository) CountByWarehouse(ctx context.Context, warehouseID string) (int64, error) {
+	count, err := r.collection.CountDocuments(ctx, locationAtLeast(warehouseID, 1))
+	if err != nil {
+		return 0, fmt.Errorf("error counting products: %v", err)
+	}
+	return count, nil
+}
+
+// RemoveWarehouse elimina de todos los productos las ubicaciones del almacén indicado
+// y retorna los IDs de los productos modificados.
+func (r *ProductRepository) RemoveWarehouse(ctx context.Context, warehouseID string) ([]string, error) {
+	filter := bson.M{"locations.warehouse_id": warehouseID}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding stock locations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("error decoding stock locations: %v", err)
+	}
+
+	_, err = r.collection.UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"locations": bson.M{"warehouse_id": warehouseID}}})
+	if err != nil {
+		return nil, fmt.Errorf("error removing stock locations: %v", err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID.Hex())
+	}
+	return ids, nil
+}
+
+// updateStock ejecuta la actualización condicional y distingue un producto inexistente
+// de uno sin stock suficiente cuando ningún documento cumple el filtro.
+func (r *ProductRepository) updateStock(ctx context.Context, objID primitive.ObjectID, filter, update bson.M, opts *options.FindOneAndUpdateOptions) (*models.Product, error) {
+	var product models.Product
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&product)
+	if err == nil {
+		return &product, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("error updating stock: %v", err)
+	}
+
+	// Un producto en la papelera solo existe para las actualizaciones que lo admiten.
+	exists := bson.M{"_id": objID}
+	if _, ok := filter["deleted_at"]; ok {
+		exists = active(exists)
+	}
+	count, err := r.collection.CountDocuments(ctx, exists)
+	if err != nil {
+		return nil, fmt.Errorf("error finding product: %v", err)
+	}
+	if count == 0 {
+		return nil, models.ErrProductNotFound
+	}
+	return nil, models.ErrInsufficientStock
+}
+
+// lowStockFilter retorna el filtro de los productos cuyo stock llegó al punto de reorden propio o, si no
+// lo definen, al de su categoría en rules.
+func lowStockFilter(rules map[string]constants.ReorderRule) bson.M {
+	categories := make([]string, 0, len(rules))
+	for category, rule := range rules {
+		if rule.Point > 0 {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+
+	// MongoDB rechaza un $switch sin ramas, así que sin reglas de categoría solo cuenta la del producto.
+	var fallback interface{} = 0
+	if len(categories) > 0 {
+		branches := make(bson.A, 0, len(categories))
+		for _, category := range categories {
+			branches = append(branches, bson.M{
+				"case": bson.M{"$eq": bson.A{bson.M{"$toLower": "$category"}, category}},
+				"then": rules[category].Point,
+			})
+		}
+		fallback = bson.M{"$switch": bson.M{"branches": branches, "default": 0}}
+	}
+	point := bson.M{"$ifNull": bson.A{"$reorder_point", fallback}}
+
+	return active(bson.M{"$expr": bson.M{"$and": bson.A{
+		bson.M{"$gt": bson.A{point, 0}},
+		bson.M{"$lte": bson.A{"$stock", point}},
+	}}})
+}
+
+// FindLowStock retorna, con paginación, los productos cuyo stock disponible llegó a su punto de reorden.
+// El punto es el del producto si lo define o, si no, el de su categoría.
+func (r *ProductRepository) FindLowStock(ctx context.Context, page, size int) ([]models.Product, error) {
+	products := []models.Product{}
+	filter := lowStockFilter(utils.CategoryReorderRules())
+
+	skip := (page - 1) * size
+	opts := options.Find().
+		SetSort(bson.D{{Key: "stock", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(size))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding low stock products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %v", err)
+	}
+
+	return products, nil
+}
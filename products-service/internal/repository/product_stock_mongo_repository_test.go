@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLowStockFilter(t *testing.T) {
+	lowStock := func(point interface{}) bson.M {
+		point = bson.M{"$ifNull": bson.A{"$reorder_point", point}}
+		return bson.M{"deleted_at": nil, "$expr": bson.M{"$and": bson.A{
+			bson.M{"$gt": bson.A{point, 0}},
+			bson.M{"$lte": bson.A{"$stock", point}},
+		}}}
+	}
+	branch := func(category string, point uint) bson.M {
+		return bson.M{"case": bson.M{"$eq": bson.A{bson.M{"$toLower": "$category"}, category}}, "then": point}
+	}
+
+	tc := []struct {
+		Name     string
+		Rules    map[string]constants.ReorderRule
+		Expected bson.M
+	}{
+		{
+			Name:     "Without categories",
+			Expected: lowStock(0),
+		},
+		{
+			Name:     "Every category rule is zero",
+			Rules:    map[string]constants.ReorderRule{"books": {}, "toys": {Quantity: 10}},
+			Expected: lowStock(0),
+		},
+		{
+			Name:  "Only categories with a reorder point",
+			Rules: map[string]constants.ReorderRule{"toys": {Point: 5, Quantity: 30}, "books": {Point: 3}, "misc": {}},
+			Expected: lowStock(bson.M{"$switch": bson.M{
+				"branches": bson.A{branch("books", 3), branch("toys", 5)},
+				"default":  0,
+			}}),
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if filter := lowStockFilter(tc.Rules); !reflect.DeepEqual(filter, tc.Expected) {
+				t.Fatalf("unexpected filter: got %v want %v", filter, tc.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// ProductTieredRepository antepone un cache LRU local a Redis. Las lecturas consultan primero el
+// nivel local y copian en él lo que encuentran en Redis; las invalidaciones se aplican en ambos
+// niveles y se difunden por pub/sub para que las demás instancias descarten sus copias locales.
+//
+// La versión de los listados siempre se lee de Redis, de modo que una invalidación hecha por otra
+// instancia cambia la clave de las páginas aunque el mensaje aún no haya llegado.
+type ProductTieredRepository struct {
+	local     *ProductLRURepository
+	remote    interfaces.ProductRedisRepositoryInterface
+	localTTL  time.Duration
+	origin    string
+	publish   func(ctx context.Context, payload []byte) error
+	subscribe func(ctx context.Context) *redis.PubSub
+}
+
+// invalidationMessage es el mensaje que se difunde tras una invalidación
+type invalidationMessage struct {
+	Origin string   `json:"origin"`
+	IDs    []string `json:"ids,omitempty"`
+	All    bool     `json:"all,omitempty"`
+}
+
+// NewProductTieredRepository crea un cache de dos niveles. Las copias locales duran como máximo localTTL,
+// lo que acota cuánto puede servir una instancia un valor viejo si pierde un mensaje de invalidación.
+func NewProductTieredRepository(local *ProductLRURepository, remote *ProductRedisRepository, localTTL time.Duration) *ProductTieredRepository {
+	return &ProductTieredRepository{
+		local:     local,
+		remote:    remote,
+		localTTL:  localTTL,
+		origin:    newOrigin(),
+		publish:   remote.PublishInvalidation,
+		subscribe: remote.SubscribeInvalidations,
+	}
+}
+
+// ListVersion obtiene la versión vigente de los listados, compartida a través de Redis
+func (r *ProductTieredRepository) ListVersion(ctx context.Context) (int64, error) {
+	return r.remote.ListVersion(ctx)
+}
+
+// GetPage obtiene una página de un listado del nivel local o, si no está, de Redis
+func (r *ProductTieredRepository) GetPage(ctx context.Context, version int64, key string) (*models.ProductPage, time.Time, error) {
+	if page, storedAt, _ := r.local.GetPage(ctx, version, key); page != nil {
+		return page, storedAt, nil
+	}
+
+	page, storedAt, err := r.remote.GetPage(ctx, version, key)
+	if err != nil || page == nil {
+		return nil, time.Time{}, err
+	}
+	r.local.set(lruPageKey(version, key), page, storedAt, r.local.now().Add(r.localTTL))
+	return page, storedAt, nil
+}
+
+// SetPage almacena una página de un listado en Redis y en el nivel local
+func (r *ProductTieredRepository) SetPage(ctx context.Context, version int64, key string, page *models.ProductPage, ttl time.Duration) error {
+	if err := r.remote.SetPage(ctx, version, key, page, ttl); err != nil {
+		return err
+	}
+	return r.local.SetPage(ctx, version, key, page, r.localExpiry(ttl))
+}
+
+// GetOne obtiene un producto del nivel local o, si no está, de Redis
+func (r *ProductTieredRepository) GetOne(ctx context.Context, id string) (*models.Product, time.Time, error) {
+	if product, storedAt, _ := r.local.GetOne(ctx, id); product != nil {
+		return product, storedAt, nil
+	}
+
+	product, storedAt, err := r.remote.GetOne(ctx, id)
+	if err != nil || product == nil {
+		return nil, time.Time{}, err
+	}
+	r.local.set(lruProductKey(id), product, storedAt, r.local.now().Add(r.localTTL))
+	return product, storedAt, nil
+}
+
+// SetOne almacena un producto en Redis. La versión solo se puede comprobar allí, así que el nivel local
+// no se escribe: la próxima lectura copia el producto si Redis lo guardó
+func (r *ProductTieredRepository) SetOne(ctx context.Context, version int64, id string, product *models.Product, ttl time.Duration) error {
+	return r.remote.SetOne(ctx, version, id, product, ttl)
+}
+
+// InvalidateProducts invalida los productos indicados en ambos niveles y lo avisa a las demás instancias
+func (r *ProductTieredRepository) InvalidateProducts(ctx context.Context, ids ...string) error {
+	if err := r.remote.InvalidateProducts(ctx, ids...); err != nil {
+		return err
+	}
+	r.local.InvalidateProducts(ctx, ids...)
+	return r.broadcast(ctx, invalidationMessage{IDs: ids})
+}
+
+// InvalidateAll vacía ambos niveles y lo avisa a las demás instancias
+func (r *ProductTieredRepository) InvalidateAll(ctx context.Context) error {
+	if err := r.remote.InvalidateAll(ctx); err != nil {
+		return err
+	}
+	r.local.InvalidateAll(ctx)
+	return r.broadcast(ctx, invalidationMessage{All: true})
+}
+
+// Listen aplica al nivel local las invalidaciones de las demás instancias hasta que ctx se cancele.
+// Cada vez que la suscripción se establece o se recupera se vacía el nivel local, porque los
+// mensajes publicados mientras estuvo caída se perdieron.
+func (r *ProductTieredRepository) Listen(ctx context.Context) {
+	pubsub := r.subscribe(ctx)
+	defer pubsub.Close()
+
+	for {
+		received, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("cache invalidation subscription failed: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch msg := received.(type) {
+		case *redis.Subscription:
+			if msg.Kind == "subscribe" {
+				r.local.InvalidateAll(ctx)
+			}
+		case *redis.Message:
+			r.handleInvalidation(ctx, []byte(msg.Payload))
+		}
+	}
+}
+
+// handleInvalidation aplica al nivel local un mensaje de otra instancia
+func (r *ProductTieredRepository) handleInvalidation(ctx context.Context, payload []byte) {
+	var msg invalidationMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		// Ante un mensaje ilegible lo seguro es descartar todo el nivel local.
+		log.Printf("invalid cache invalidation message: %v", err)
+		r.local.InvalidateAll(ctx)
+		return
+	}
+	if msg.Origin == r.origin {
+		return
+	}
+	if msg.All {
+		r.local.InvalidateAll(ctx)
+		return
+	}
+	r.local.InvalidateProducts(ctx, msg.IDs...)
+}
+
+func (r *ProductTieredRepository) broadcast(ctx context.Context, msg invalidationMessage) error {
+	msg.Origin = r.origin
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return r.publish(ctx, payload)
+}
+
+// localExpiry limita la vigencia de una copia local a localTTL
+func (r *ProductTieredRepository) localExpiry(ttl time.Duration) time.Duration {
+	if ttl > r.localTTL {
+		return r.localTTL
+	}
+	return ttl
+}
+
+// newOrigin identifica a esta instancia para ignorar sus propios mensajes
+func newOrigin() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(b)
+}
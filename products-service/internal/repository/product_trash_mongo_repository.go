@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// active agrega al filtro la condición de que el producto no esté en la papelera.
+func active(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// unreserved agrega al filtro la condición de que el producto no tenga unidades reservadas, es decir,
+// reservas abiertas que aún deben confirmarse, liberarse o vencer.
+func unreserved(filter bson.M) bson.M {
+	filter["reserved"] = bson.M{"$not": bson.M{"$gt": 0}}
+	return filter
+}
+
+// inTrash filtra los productos de la papelera.
+func inTrash(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$type": "date"}
+	return filter
+}
+
+// FindDeleted retorna, con paginación, los productos de la papelera, primero los borrados más recientemente.
+func (r *ProductRepository) FindDeleted(ctx context.Context, page, size int) ([]models.Product, error) {
+	products := []models.Product{}
+
+	skip := (page - 1) * size
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(size))
+
+	cursor, err := r.collection.Find(ctx, inTrash(bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding deleted products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %v", err)
+	}
+	return products, nil
+}
+
+// Restore saca un producto de la papelera y lo retorna ya restaurado.
+func (r *ProductRepository) Restore(ctx context.Context, id string) (*models.Product, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	var product models.Product
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.collection.FindOneAndUpdate(ctx, inTrash(bson.M{"_id": objID}), bson.M{"$unset": bson.M{"deleted_at": ""}}, opts).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w in the trash with ID: %s", models.ErrProductNotFound, id)
+		}
+		return nil, fmt.Errorf("error restoring product: %v", err)
+	}
+	return &product, nil
+}
+
+// FindDeletedBefore retorna hasta limit productos sin unidades reservadas que entraron en la papelera
+// antes de la fecha indicada.
+func (r *ProductRepository) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]models.Product, error) {
+	products := []models.Product{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"images": 1, "deleted_at": 1})
+
+	cursor, err := r.collection.Find(ctx, unreserved(bson.M{"deleted_at": bson.M{"$lt": before}}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding deleted products: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %v", err)
+	}
+	return products, nil
+}
+
+// Purge elimina definitivamente un producto sin unidades reservadas que sigue en la papelera desde antes
+// de la fecha indicada; si se restauró mientras tanto no lo toca y retorna models.ErrProductNotFound.
+func (r *ProductRepository) Purge(ctx context.Context, id string, before time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, unreserved(bson.M{"_id": objID, "deleted_at": bson.M{"$lt": before}}))
+	if err != nil {
+		return fmt.Errorf("error purging product: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w in the trash with ID: %s", models.ErrProductNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddVariant agrega una variante al producto y suma su stock al del producto. La primera variante
+// solo se agrega si el producto no tiene stock propio, que quedaría fuera de las variantes.
+func (r *ProductRepository) AddVariant(ctx context.Context, id string, variant models.Variant) (*models.Product, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	filter := active(bson.M{
+		"_id":          objID,
+		"variants.sku": bson.M{"$ne": variant.SKU},
+		"$or": bson.A{
+			bson.M{"variants.0": bson.M{"$exists": true}},
+			bson.M{"stock": 0, "reserved": 0},
+		},
+	})
+	update := bson.M{
+		"$push": bson.M{"variants": variant},
+		"$inc":  bson.M{"stock": variant.Stock},
+	}
+
+	var product models.Product
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&product)
+	if err == nil {
+		return &product, nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, duplicateProductError(err)
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("error adding variant: %v", err)
+	}
+
+	current, err := r.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range current.Variants {
+		if existing.SKU == variant.SKU {
+			return nil, models.ErrDuplicateSKU
+		}
+	}
+	return nil, models.ErrProductHasStock
+}
+
+// UpdateVariant modifica los campos indicados de una variante; el SKU no puede repetirse en el producto.
+func (r *ProductRepository) UpdateVariant(ctx context.Context, id, variantID string, fields map[string]interface{}) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	filter := active(bson.M{"_id": objID, "variants.id": variantID})
+	if sku, ok := fields["sku"]; ok {
+		filter["variants"] = bson.M{"$not": bson.M{"$elemMatch": bson.M{"sku": sku, "id": bson.M{"$ne": variantID}}}}
+	}
+	set := bson.M{}
+	for field, value := range fields {
+		set["variants.$[v]."+field] = value
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"v.id": variantID}}})
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": set}, opts)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return duplicateProductError(err)
+		}
+		return fmt.Errorf("error updating variant: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return r.variantError(ctx, id, variantID, models.ErrDuplicateSKU)
+	}
+	return nil
+}
+
+// RemoveVariant quita una variante sin unidades reservadas, recalcula el stock del producto y
+// retorna la variante eliminada.
+func (r *ProductRepository) RemoveVariant(ctx context.Context, id, variantID string) (*models.Variant, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid product ID: %v", err)
+	}
+
+	filter := active(bson.M{"_id": objID, "variants": bson.M{"$elemMatch": bson.M{"id": variantID, "reserved": 0}}})
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"variants": bson.M{"$filter": bson.M{
+			"input": "$variants",
+			"cond":  bson.M{"$ne": bson.A{"$$this.id", variantID}},
+		}}}}},
+		{{Key: "$set", Value: bson.M{
+			"stock":    bson.M{"$sum": "$variants.stock"},
+			"reserved": bson.M{"$sum": "$variants.reserved"},
+		}}},
+	}
+
+	var before models.Product
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, r.variantError(ctx, id, variantID, models.ErrVariantReserved)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error removing variant: %v", err)
+	}
+
+	variant, _ := before.FindVariant(variantID)
+	return variant, nil
+}
+
+// variantError explica por qué una actualización condicional de una variante no modificó nada:
+// el producto o la variante no existen o, si existen, se retorna otherwise.
+func (r *ProductRepository) variantError(ctx context.Context, id, variantID string, otherwise error) error {
+	product, err := r.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, ok := product.FindVariant(variantID); !ok {
+		return fmt.Errorf("%w with ID: %s", models.ErrVariantNotFound, variantID)
+	}
+	return otherwise
+}
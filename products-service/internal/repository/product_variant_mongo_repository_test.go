@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+func TestVariantInvalidProductID(t *testing.T) {
+	// Un ID inválido se rechaza antes de consultar MongoDB, así que no hace falta una colección.
+	repository := NewProductRepository(nil)
+	ctx := context.Background()
+
+	tc := []struct {
+		Name string
+		Run  func() error
+	}{
+		{Name: "Add variant", Run: func() error {
+			_, err := repository.AddVariant(ctx, "not-an-id", models.Variant{SKU: "KB-RED"})
+			return err
+		}},
+		{Name: "Update variant", Run: func() error {
+			return repository.UpdateVariant(ctx, "not-an-id", "variant", map[string]interface{}{"stock": 1})
+		}},
+		{Name: "Remove variant", Run: func() error {
+			_, err := repository.RemoveVariant(ctx, "not-an-id", "variant")
+			return err
+		}},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Run()
+			if err == nil || errors.Is(err, models.ErrProductNotFound) || !strings.HasPrefix(err.Error(), "invalid product ID") {
+				t.Fatalf("unexpected error: got %v want invalid product ID", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReservationRepository gestiona las reservas de stock en MongoDB.
+type ReservationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReservationRepository crea una nueva instancia de ReservationRepository con la colección especificada.
+func NewReservationRepository(collection *mongo.Collection) *ReservationRepository {
+	return &ReservationRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice que usa el barrido de reservas expiradas.
+func (r *ReservationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating reservation indexes: %v", err)
+	}
+	return nil
+}
+
+// Create inserta una reserva y la retorna con el ID asignado.
+func (r *ReservationRepository) Create(ctx context.Context, reservation models.Reservation) (*models.Reservation, error) {
+	reservation.ID = primitive.NewObjectID().Hex()
+
+	if _, err := r.collection.InsertOne(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("error inserting reservation: %v", err)
+	}
+	return &reservation, nil
+}
+
+// Transition cambia el estado de la reserva de from a to de forma atómica. La reserva debe pertenecer
+// al producto indicado; la de otro producto se trata como inexistente. Una reserva vencida ya no se
+// puede confirmar aunque el barrido aún no la haya liberado.
+func (r *ReservationRepository) Transition(ctx context.Context, productID, id, from, to string) (*models.Reservation, error) {
+	filter := bson.M{"_id": id, "product_id": productID, "status": from}
+	if to == models.ReservationCommitted {
+		filter["expires_at"] = bson.M{"$gt": time.Now().UTC()}
+	}
+
+	var reservation models.Reservation
+	err := r.collection.FindOneAndUpdate(ctx,
+		filter,
+		bson.M{"$set": bson.M{"status": to}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&reservation)
+	if err == nil {
+		return &reservation, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("error updating reservation: %v", err)
+	}
+
+	// Distingue una reserva inexistente de una que ya no está en el estado esperado o que venció.
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": id, "product_id": productID})
+	if err != nil {
+		return nil, fmt.Errorf("error finding reservation: %v", err)
+	}
+	if count == 0 {
+		return nil, models.ErrReservationNotFound
+	}
+	return nil, models.ErrReservationClosed
+}
+
+// FindExpired retorna hasta limit reservas activas que expiraron antes de now.
+func (r *ReservationRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"status": models.ReservationActive, "expires_at": bson.M{"$lt": now}},
+		options.Find().SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error finding expired reservations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &reservations); err != nil {
+		return nil, fmt.Errorf("error decoding reservations: %v", err)
+	}
+
+	return reservations, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReservationRepositoryMocked guarda las reservas en memoria.
+type ReservationRepositoryMocked struct {
+	mu           sync.Mutex
+	Reservations []models.Reservation
+}
+
+func (r *ReservationRepositoryMocked) Create(ctx context.Context, reservation models.Reservation) (*models.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation.ID = primitive.NewObjectID().Hex()
+	r.Reservations = append(r.Reservations, reservation)
+	return &reservation, nil
+}
+
+func (r *ReservationRepositoryMocked) Transition(ctx context.Context, productID, id, from, to string) (*models.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.Reservations {
+		reservation := &r.Reservations[i]
+		if reservation.ID != id || reservation.ProductID != productID {
+			continue
+		}
+		if reservation.Status != from || to == models.ReservationCommitted && !reservation.ExpiresAt.After(time.Now()) {
+			return nil, models.ErrReservationClosed
+		}
+		reservation.Status = to
+		updated := *reservation
+		return &updated, nil
+	}
+	return nil, fmt.Errorf("%w with ID: %s", models.ErrReservationNotFound, id)
+}
+
+func (r *ReservationRepositoryMocked) FindExpired(ctx context.Context, now time.Time, limit int) ([]models.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservations := []models.Reservation{}
+	for _, reservation := range r.Reservations {
+		if reservation.Status == models.ReservationActive && reservation.ExpiresAt.Before(now) && len(reservations) < limit {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
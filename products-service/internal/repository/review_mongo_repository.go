@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReviewRepository gestiona las reseñas de productos en MongoDB.
+type ReviewRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReviewRepository crea una nueva instancia de ReviewRepository con la colección especificada.
+func NewReviewRepository(collection *mongo.Collection) *ReviewRepository {
+	return &ReviewRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice único que limita a una reseña por usuario y producto; también sirve
+// para listar las reseñas de un producto.
+func (r *ReviewRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "author", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating review indexes: %v", err)
+	}
+	return nil
+}
+
+// Create inserta una reseña y la retorna con el ID asignado por MongoDB.
+func (r *ReviewRepository) Create(ctx context.Context, review models.Review) (*models.Review, error) {
+	review.ID = ""
+
+	result, err := r.collection.InsertOne(ctx, review)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, models.ErrDuplicateReview
+		}
+		return nil, fmt.Errorf("error inserting review: %v", err)
+	}
+
+	if objID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		review.ID = objID.Hex()
+	}
+	return &review, nil
+}
+
+// FindOne busca una reseña de un producto por su ID.
+func (r *ReviewRepository) FindOne(ctx context.Context, productID, id string) (*models.Review, error) {
+	filter, err := reviewFilter(productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.Review
+	if err := r.collection.FindOne(ctx, filter).Decode(&review); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with ID: %s", models.ErrReviewNotFound, id)
+		}
+		return nil, fmt.Errorf("error finding review: %v", err)
+	}
+
+	return &review, nil
+}
+
+// FindByProduct retorna las reseñas de un producto, de la más reciente a la más antigua, y su total.
+func (r *ReviewRepository) FindByProduct(ctx context.Context, productID string, includeHidden bool, page, size int) ([]models.Review, int64, error) {
+	filter := bson.M{"product_id": productID}
+	if !includeHidden {
+		filter["status"] = models.ReviewVisible
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting reviews: %v", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * size)).
+		SetLimit(int64(size))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error finding reviews: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.Review{}
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, 0, fmt.Errorf("error decoding reviews: %v", err)
+	}
+
+	return reviews, total, nil
+}
+
+// Update aplica los cambios del autor y retorna la reseña como estaba antes.
+func (r *ReviewRepository) Update(ctx context.Context, productID, id string, update models.ReviewUpdate) (*models.Review, error) {
+	fields := bson.M{"updated_at": time.Now().UTC()}
+	if update.Rating != nil {
+		fields["rating"] = *update.Rating
+	}
+	if update.Text != nil {
+		fields["text"] = *update.Text
+	}
+
+	return r.findOneAndUpdate(ctx, productID, id, bson.M{"$set": fields})
+}
+
+// SetStatus cambia el estado de moderación y retorna la reseña como estaba antes.
+func (r *ReviewRepository) SetStatus(ctx context.Context, productID, id, status string) (*models.Review, error) {
+	return r.findOneAndUpdate(ctx, productID, id, bson.M{"$set": bson.M{"status": status}})
+}
+
+// Delete elimina una reseña y la retorna.
+func (r *ReviewRepository) Delete(ctx context.Context, productID, id string) (*models.Review, error) {
+	filter, err := reviewFilter(productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.Review
+	if err := r.collection.FindOneAndDelete(ctx, filter).Decode(&review); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with ID: %s", models.ErrReviewNotFound, id)
+		}
+		return nil, fmt.Errorf("error deleting review: %v", err)
+	}
+
+	return &review, nil
+}
+
+// DeleteByProduct elimina todas las reseñas de un producto y retorna cuántas eliminó.
+func (r *ReviewRepository) DeleteByProduct(ctx context.Context, productID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"product_id": productID})
+	if err != nil {
+		return 0, fmt.Errorf("error deleting reviews: %v", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// findOneAndUpdate aplica update a la reseña y la retorna como estaba antes del cambio.
+func (r *ReviewRepository) findOneAndUpdate(ctx context.Context, productID, id string, update bson.M) (*models.Review, error) {
+	filter, err := reviewFilter(productID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var before models.Review
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(&before)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with ID: %s", models.ErrReviewNotFound, id)
+		}
+		return nil, fmt.Errorf("error updating review: %v", err)
+	}
+
+	return &before, nil
+}
+
+// reviewFilter identifica una reseña dentro de las de un producto.
+func reviewFilter(productID, id string) (bson.M, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w with ID: %s", models.ErrReviewNotFound, id)
+	}
+	return bson.M{"_id": objID, "product_id": productID}, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewRepositoryMocked es un repositorio de reseñas en memoria para pruebas.
+type ReviewRepositoryMocked struct {
+	mu      sync.Mutex
+	reviews []models.Review
+}
+
+func (r *ReviewRepositoryMocked) Create(ctx context.Context, review models.Review) (*models.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.reviews {
+		if existing.ProductID == review.ProductID && existing.Author == review.Author {
+			return nil, models.ErrDuplicateReview
+		}
+	}
+	review.ID = primitive.NewObjectID().Hex()
+	r.reviews = append(r.reviews, review)
+	return &review, nil
+}
+
+func (r *ReviewRepositoryMocked) FindOne(ctx context.Context, productID, id string) (*models.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(productID, id)
+	if err != nil {
+		return nil, err
+	}
+	review := r.reviews[i]
+	return &review, nil
+}
+
+func (r *ReviewRepositoryMocked) FindByProduct(ctx context.Context, productID string, includeHidden bool, page, size int) ([]models.Review, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	matched := []models.Review{}
+	for i := len(r.reviews) - 1; i >= 0; i-- {
+		review := r.reviews[i]
+		if review.ProductID == productID && (includeHidden || review.Status == models.ReviewVisible) {
+			matched = append(matched, review)
+		}
+	}
+
+	start := min((page-1)*size, len(matched))
+	end := min(start+size, len(matched))
+	return matched[start:end], int64(len(matched)), nil
+}
+
+func (r *ReviewRepositoryMocked) Update(ctx context.Context, productID, id string, update models.ReviewUpdate) (*models.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(productID, id)
+	if err != nil {
+		return nil, err
+	}
+	before := r.reviews[i]
+	if update.Rating != nil {
+		r.reviews[i].Rating = *update.Rating
+	}
+	if update.Text != nil {
+		r.reviews[i].Text = *update.Text
+	}
+	return &before, nil
+}
+
+func (r *ReviewRepositoryMocked) SetStatus(ctx context.Context, productID, id, status string) (*models.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(productID, id)
+	if err != nil {
+		return nil, err
+	}
+	before := r.reviews[i]
+	r.reviews[i].Status = status
+	return &before, nil
+}
+
+func (r *ReviewRepositoryMocked) Delete(ctx context.Context, productID, id string) (*models.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.find(productID, id)
+	if err != nil {
+		return nil, err
+	}
+	deleted := r.reviews[i]
+	r.reviews = append(r.reviews[:i], r.reviews[i+1:]...)
+	return &deleted, nil
+}
+
+func (r *ReviewRepositoryMocked) DeleteByProduct(ctx context.Context, productID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.reviews[:0]
+	for _, review := range r.reviews {
+		if review.ProductID != productID {
+			kept = append(kept, review)
+		}
+	}
+	deleted := int64(len(r.reviews) - len(kept))
+	r.reviews = kept
+	return deleted, nil
+}
+
+func (r *ReviewRepositoryMocked) find(productID, id string) (int, error) {
+	for i, review := range r.reviews {
+		if review.ID == id && review.ProductID == productID {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%w with ID: %s", models.ErrReviewNotFound, id)
+}
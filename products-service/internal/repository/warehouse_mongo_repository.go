@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WarehouseRepository gestiona los almacenes en MongoDB.
+type WarehouseRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWarehouseRepository crea una nueva instancia de WarehouseRepository con la colección especificada.
+func NewWarehouseRepository(collection *mongo.Collection) *WarehouseRepository {
+	return &WarehouseRepository{collection: collection}
+}
+
+// EnsureIndexes crea el índice único sobre el código del almacén.
+func (r *WarehouseRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating warehouse indexes: %v", err)
+	}
+	return nil
+}
+
+// FindAll retorna todos los almacenes ordenados por código.
+func (r *WarehouseRepository) FindAll(ctx context.Context) ([]models.Warehouse, error) {
+	warehouses := []models.Warehouse{}
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "code", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding warehouses: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &warehouses); err != nil {
+		return nil, fmt.Errorf("error decoding warehouses: %v", err)
+	}
+
+	return warehouses, nil
+}
+
+// FindOne busca un almacén por su ID.
+func (r *WarehouseRepository) FindOne(ctx context.Context, id string) (*models.Warehouse, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+	}
+
+	var warehouse models.Warehouse
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&warehouse); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+		}
+		return nil, fmt.Errorf("error finding warehouse: %v", err)
+	}
+
+	return &warehouse, nil
+}
+
+// Create inserta un almacén y lo retorna con el ID asignado por MongoDB.
+func (r *WarehouseRepository) Create(ctx context.Context, warehouse models.Warehouse) (*models.Warehouse, error) {
+	warehouse.ID = ""
+
+	result, err := r.collection.InsertOne(ctx, warehouse)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, models.ErrDuplicateWarehouse
+		}
+		return nil, fmt.Errorf("error inserting warehouse: %v", err)
+	}
+
+	if objID, ok := result.InsertedID.(primitive.ObjectID); ok {
+		warehouse.ID = objID.Hex()
+	}
+	return &warehouse, nil
+}
+
+// Update modifica los campos indicados de un almacén.
+func (r *WarehouseRepository) Update(ctx context.Context, id string, fields map[string]interface{}) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+	}
+
+	result, err := r.collection.UpdateByID(ctx, objID, bson.M{"$set": fields})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return models.ErrDuplicateWarehouse
+		}
+		return fmt.Errorf("error updating warehouse: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+	}
+
+	return nil
+}
+
+// Delete elimina un almacén por su ID.
+func (r *WarehouseRepository) Delete(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		return fmt.Errorf("error deleting warehouse: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WarehouseRepositoryMocked guarda los almacenes en memoria.
+type WarehouseRepositoryMocked struct {
+	mu         sync.Mutex
+	Warehouses []models.Warehouse
+}
+
+func (r *WarehouseRepositoryMocked) FindAll(ctx context.Context) ([]models.Warehouse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]models.Warehouse{}, r.Warehouses...), nil
+}
+
+func (r *WarehouseRepositoryMocked) FindOne(ctx context.Context, id string) (*models.Warehouse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, warehouse := range r.Warehouses {
+		if warehouse.ID == id {
+			return &warehouse, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+}
+
+func (r *WarehouseRepositoryMocked) Create(ctx context.Context, warehouse models.Warehouse) (*models.Warehouse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.Warehouses {
+		if existing.Code == warehouse.Code {
+			return nil, models.ErrDuplicateWarehouse
+		}
+	}
+	warehouse.ID = primitive.NewObjectID().Hex()
+	r.Warehouses = append(r.Warehouses, warehouse)
+	return &warehouse, nil
+}
+
+func (r *WarehouseRepositoryMocked) Update(ctx context.Context, id string, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.Warehouses {
+		warehouse := &r.Warehouses[i]
+		if warehouse.ID != id {
+			continue
+		}
+		if code, ok := fields["code"].(string); ok {
+			warehouse.Code = code
+		}
+		if name, ok := fields["name"].(string); ok {
+			warehouse.Name = name
+		}
+		if address, ok := fields["address"].(string); ok {
+			warehouse.Address = address
+		}
+		return nil
+	}
+	return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+}
+
+func (r *WarehouseRepositoryMocked) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, warehouse := range r.Warehouses {
+		if warehouse.ID == id {
+			r.Warehouses = append(r.Warehouses[:i], r.Warehouses[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w with ID: %s", models.ErrWarehouseNotFound, id)
+}
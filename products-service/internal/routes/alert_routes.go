@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
+)
+
+func AlertRoutes(router *gin.Engine, productsController *controller.ProductController, authMiddleware gin.HandlerFunc) {
+	// Las alertas de inventario son para administradores y encargados de inventario.
+	canManage := middlewares.RequireRoles(constants.RoleAdmin, constants.RoleInventoryManager)
+
+	alertGroup := router.Group("/alerts", authMiddleware, canManage)
+	{
+		alertGroup.GET("/low-stock", productsController.GetLowStockAlerts)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
+)
+
+func CacheRoutes(router *gin.Engine, productsController *controller.ProductController, authMiddleware gin.HandlerFunc) {
+	// El estado del servicio es público para los balanceadores y orquestadores.
+	router.GET("/health", productsController.Health)
+
+	// Las métricas del cache son solo para administradores.
+	cacheGroup := router.Group("/cache", authMiddleware, middlewares.RequireRoles(constants.RoleAdmin))
+	{
+		cacheGroup.GET("/stats", productsController.GetCacheStats)
+	}
+}
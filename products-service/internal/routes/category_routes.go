@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
+)
+
+func CategoryRoutes(router *gin.Engine, categoriesController *controller.CategoryController, authMiddleware gin.HandlerFunc) {
+	// Solo administradores y encargados de inventario pueden modificar categorías.
+	canManage := middlewares.RequireRoles(constants.RoleAdmin, constants.RoleInventoryManager)
+
+	categoryGroup := router.Group("/categories", authMiddleware)
+	{
+		categoryGroup.GET("/", categoriesController.GetCategories)
+		categoryGroup.GET("/:slug", categoriesController.GetCategory)
+		categoryGroup.POST("/", canManage, categoriesController.PostCategory)
+		categoryGroup.PATCH("/:slug", canManage, categoriesController.UpdateCategory)
+		categoryGroup.DELETE("/:slug", canManage, categoriesController.DeleteCategory)
+	}
+}
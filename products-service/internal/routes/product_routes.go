@@ -2,17 +2,52 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
 )
 
-func ProductRoutes(router *gin.Engine, productsController *controller.ProductController) {
-	productGroup := router.Group("/products")
+func ProductRoutes(router *gin.Engine, productsController *controller.ProductController, authMiddleware gin.HandlerFunc) {
+	// Solo administradores y encargados de inventario pueden modificar productos.
+	canManage := middlewares.RequireRoles(constants.RoleAdmin, constants.RoleInventoryManager)
+
+	productGroup := router.Group("/products", authMiddleware)
 	{
 		productGroup.GET("/", productsController.GetProducts)
 		productGroup.GET("/:user_id", productsController.GetProduct)
-		productGroup.POST("/", productsController.PostProduct)
-		productGroup.PATCH("/:user_id", productsController.UpdateProduct)
-		productGroup.DELETE("/:user_id", productsController.DeleteProduct)
+		productGroup.GET("/by-sku/:sku", productsController.GetProductBySKU)
+		productGroup.GET("/by-barcode/:code", productsController.GetProductByBarcode)
+		productGroup.GET("/export", canManage, productsController.ExportProducts)
+		productGroup.POST("/", canManage, productsController.PostProduct)
+		productGroup.POST("/import", canManage, productsController.ImportProducts)
+		productGroup.POST("/bulk", canManage, productsController.BulkProducts)
+		productGroup.PATCH("/:user_id", canManage, productsController.UpdateProduct)
+		productGroup.DELETE("/:user_id", canManage, productsController.DeleteProduct)
+
+		// Papelera: los productos borrados se pueden restaurar hasta que se purgan.
+		productGroup.GET("/trash", canManage, productsController.GetTrash)
+		productGroup.POST("/:user_id/restore", canManage, productsController.RestoreProduct)
+
+		// Operaciones atómicas de inventario.
+		productGroup.POST("/:user_id/stock/increment", canManage, productsController.IncrementStock)
+		productGroup.POST("/:user_id/stock/decrement", canManage, productsController.DecrementStock)
+		productGroup.POST("/:user_id/stock/transfer", canManage, productsController.TransferStock)
+		productGroup.POST("/:user_id/reservations", canManage, productsController.ReserveStock)
+		productGroup.POST("/:user_id/reservations/:reservation_id/commit", canManage, productsController.CommitReservation)
+		productGroup.DELETE("/:user_id/reservations/:reservation_id", canManage, productsController.ReleaseReservation)
+		productGroup.GET("/:user_id/movements", canManage, productsController.GetMovements)
+		productGroup.GET("/:user_id/price-history", canManage, productsController.GetPriceHistory)
+
+		// Variantes del producto.
+		productGroup.POST("/:user_id/variants", canManage, productsController.PostVariant)
+		productGroup.PATCH("/:user_id/variants/:variant_id", canManage, productsController.UpdateVariant)
+		productGroup.DELETE("/:user_id/variants/:variant_id", canManage, productsController.DeleteVariant)
+
+		// Imágenes del producto.
+		productGroup.POST("/:user_id/images", canManage, productsController.UploadProductImage)
+		productGroup.POST("/:user_id/images/uploads", canManage, productsController.CreateImageUpload)
+		productGroup.POST("/:user_id/images/confirm", canManage, productsController.ConfirmImageUpload)
+		productGroup.DELETE("/:user_id/images/:image_id", canManage, productsController.DeleteProductImage)
 	}
 
 }
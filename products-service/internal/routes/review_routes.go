@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
+)
+
+func ReviewRoutes(router *gin.Engine, reviewsController *controller.ReviewController, authMiddleware gin.HandlerFunc) {
+	// Cualquier usuario autenticado puede reseñar; solo los administradores moderan.
+	canModerate := middlewares.RequireRoles(constants.RoleAdmin)
+
+	reviewGroup := router.Group("/products", authMiddleware)
+	{
+		reviewGroup.GET("/:user_id/reviews", reviewsController.GetReviews)
+		reviewGroup.POST("/:user_id/reviews", reviewsController.PostReview)
+		reviewGroup.PATCH("/:user_id/reviews/:review_id", reviewsController.UpdateReview)
+		reviewGroup.DELETE("/:user_id/reviews/:review_id", reviewsController.DeleteReview)
+		reviewGroup.PUT("/:user_id/reviews/:review_id/status", canModerate, reviewsController.ModerateReview)
+	}
+}
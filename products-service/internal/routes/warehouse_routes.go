@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/controller"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/middlewares"
+)
+
+func WarehouseRoutes(router *gin.Engine, warehousesController *controller.WarehouseController, authMiddleware gin.HandlerFunc) {
+	// Solo administradores y encargados de inventario pueden modificar almacenes.
+	canManage := middlewares.RequireRoles(constants.RoleAdmin, constants.RoleInventoryManager)
+
+	warehouseGroup := router.Group("/warehouses", authMiddleware)
+	{
+		warehouseGroup.GET("/", warehousesController.GetWarehouses)
+		warehouseGroup.GET("/:id", warehousesController.GetWarehouse)
+		warehouseGroup.POST("/", canManage, warehousesController.PostWarehouse)
+		warehouseGroup.PATCH("/:id", canManage, warehousesController.UpdateWarehouse)
+		warehouseGroup.DELETE("/:id", canManage, warehousesController.DeleteWarehouse)
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// CategoryService contiene la lógica de negocio de las categorías y mantiene en memoria
+// los slugs vigentes para validar categorías sin consultar MongoDB en cada solicitud.
+type CategoryService struct {
+	repository interfaces.CategoryRepositoryInterface
+	products   interfaces.ProductMongoRepositoryInterface
+	cache      interfaces.ProductRedisRepositoryInterface
+
+	mu    sync.RWMutex
+	slugs map[string]struct{}
+	rules map[string]constants.ReorderRule
+}
+
+// NewCategoryService crea una nueva instancia de CategoryService.
+func NewCategoryService(repository interfaces.CategoryRepositoryInterface, products interfaces.ProductMongoRepositoryInterface, cache interfaces.ProductRedisRepositoryInterface) *CategoryService {
+	return &CategoryService{repository: repository, products: products, cache: cache, slugs: map[string]struct{}{}, rules: map[string]constants.ReorderRule{}}
+}
+
+// Exists indica si existe una categoría con el slug dado según la última carga de categorías.
+func (s *CategoryService) Exists(slug string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.slugs[slug]
+	return ok
+}
+
+// ReorderRules retorna la regla de reposición de cada categoría según la última carga de categorías.
+// El mapa se reemplaza en cada carga, así que no debe modificarse.
+func (s *CategoryService) ReorderRules() map[string]constants.ReorderRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules
+}
+
+// Refresh recarga desde MongoDB los slugs que usa Exists y las reglas que retorna ReorderRules.
+func (s *CategoryService) Refresh(ctx context.Context) error {
+	categories, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	slugs := make(map[string]struct{}, len(categories))
+	rules := make(map[string]constants.ReorderRule, len(categories))
+	for _, category := range categories {
+		slugs[category.Slug] = struct{}{}
+		rules[category.Slug] = category.ReorderRule()
+	}
+
+	s.mu.Lock()
+	s.slugs = slugs
+	s.rules = rules
+	s.mu.Unlock()
+	return nil
+}
+
+// RunCategoryRefresher recarga las categorías cada interval hasta que se cancele ctx, para que
+// cada instancia vea las que se modificaron en otras.
+func (s *CategoryService) RunCategoryRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Printf("failed to refresh categories: %v", err)
+			}
+		}
+	}
+}
+
+// SeedDefaults crea las categorías de constants.AllowCategories si la colección está vacía,
+// para que los productos existentes sigan siendo válidos, y carga los slugs vigentes. A las
+// categorías de AllowCategories creadas sin regla de reposición les asigna la de
+// constants.CategoryReorderRules, para que sigan generando alertas de stock bajo.
+func (s *CategoryService) SeedDefaults(ctx context.Context) error {
+	categories, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(categories) == 0 {
+		now := time.Now().UTC()
+		for _, slug := range constants.AllowCategories {
+			category := models.Category{Slug: slug, Name: strings.ToUpper(slug[:1]) + slug[1:], CreatedAt: now}
+			if rule, ok := constants.CategoryReorderRules[slug]; ok {
+				category.ReorderPoint, category.ReorderQuantity = &rule.Point, &rule.Quantity
+			}
+			if _, err := s.repository.Create(ctx, category); err != nil && !errors.Is(err, models.ErrDuplicateCategory) {
+				return err
+			}
+		}
+	}
+
+	for _, category := range categories {
+		rule, ok := constants.CategoryReorderRules[category.Slug]
+		if !ok || category.ReorderPoint != nil || category.ReorderQuantity != nil {
+			continue
+		}
+		fields := map[string]interface{}{"reorder_point": rule.Point, "reorder_quantity": rule.Quantity}
+		if err := s.repository.Update(ctx, category.Slug, fields); err != nil && !errors.Is(err, models.ErrCategoryNotFound) {
+			return err
+		}
+	}
+
+	return s.Refresh(ctx)
+}
+
+// GetAllCategories retorna todas las categorías.
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]models.Category, error) {
+	return s.repository.FindAll(ctx)
+}
+
+// GetCategoryTree retorna las categorías raíz con sus subcategorías anidadas.
+func (s *CategoryService) GetCategoryTree(ctx context.Context) ([]models.CategoryNode, error) {
+	categories, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string][]models.Category{}
+	for _, category := range categories {
+		children[category.Parent] = append(children[category.Parent], category)
+	}
+
+	var build func(parent string) []models.CategoryNode
+	build = func(parent string) []models.CategoryNode {
+		nodes := []models.CategoryNode{}
+		for _, category := range children[parent] {
+			nodes = append(nodes, models.CategoryNode{Category: category, Children: build(category.Slug)})
+		}
+		return nodes
+	}
+	return build(""), nil
+}
+
+// GetOneCategory retorna una categoría por su slug.
+func (s *CategoryService) GetOneCategory(ctx context.Context, slug string) (*models.Category, error) {
+	return s.repository.FindOne(ctx, slug)
+}
+
+// CreateCategory registra una categoría nueva; si no trae slug se deriva de su nombre.
+func (s *CategoryService) CreateCategory(ctx context.Context, category models.Category) (*models.Category, error) {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if category.Slug == "" {
+		category.Slug = category.Name
+	}
+	category.Slug = slugify(category.Slug)
+	if category.Slug == "" {
+		return nil, errors.New("slug must contain letters or digits")
+	}
+
+	category.Parent = strings.ToLower(strings.TrimSpace(category.Parent))
+	if category.Parent != "" {
+		if _, err := s.repository.FindOne(ctx, category.Parent); err != nil {
+			if errors.Is(err, models.ErrCategoryNotFound) {
+				return nil, errors.New("parent must be an existing category")
+			}
+			return nil, err
+		}
+	}
+	// Una categoría nueva siempre guarda su regla, aunque sea vacía, para que SeedDefaults no le asigne otra.
+	if category.ReorderPoint == nil {
+		category.ReorderPoint = new(uint)
+	}
+	if category.ReorderQuantity == nil {
+		category.ReorderQuantity = new(uint)
+	}
+	category.CreatedAt = time.Now().UTC()
+
+	created, err := s.repository.Create(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	s.refresh(ctx)
+	return created, nil
+}
+
+// UpdateCategory modifica el nombre, la categoría padre o la regla de reposición; el slug no cambia porque
+// lo referencian los productos.
+func (s *CategoryService) UpdateCategory(ctx context.Context, slug string, fields map[string]interface{}) error {
+	for field, value := range fields {
+		switch field {
+		case "name":
+			name, ok := value.(string)
+			if !ok || strings.TrimSpace(name) == "" {
+				return errors.New("name must be a non-empty string")
+			}
+			fields[field] = strings.TrimSpace(name)
+		case "parent":
+			// null o "" convierten la categoría en raíz.
+			parent := ""
+			if value != nil {
+				str, ok := value.(string)
+				if !ok {
+					return errors.New("parent must be a string")
+				}
+				parent = strings.ToLower(strings.TrimSpace(str))
+			}
+			if err := s.checkParent(ctx, slug, parent); err != nil {
+				return err
+			}
+			fields[field] = parent
+		case "reorder_point", "reorder_quantity":
+			// Un reorder_point en 0 desactiva las alertas de los productos sin regla propia.
+			n, ok := value.(float64)
+			if !ok || n < 0 || n != math.Trunc(n) || n > math.MaxUint32 {
+				return errors.New(field + " must be a non-negative integer")
+			}
+			fields[field] = uint(n)
+		default:
+			return errors.New(field + " cannot be updated")
+		}
+	}
+
+	if err := s.repository.Update(ctx, slug, fields); err != nil {
+		return err
+	}
+	s.refresh(ctx)
+	return nil
+}
+
+// DeleteCategory elimina una categoría sin subcategorías. Si todavía tiene productos solo se elimina
+// cuando se indica reassignTo, la categoría a la que se mueven esos productos.
+func (s *CategoryService) DeleteCategory(ctx context.Context, slug, reassignTo string) error {
+	category, err := s.repository.FindOne(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, category := range categories {
+		if category.Parent == slug {
+			return models.ErrCategoryHasChildren
+		}
+	}
+
+	reassignTo = strings.ToLower(strings.TrimSpace(reassignTo))
+	if reassignTo == "" {
+		count, err := s.products.CountByCategory(ctx, slug)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return models.ErrCategoryInUse
+		}
+		if err := s.deleteCategory(ctx, slug); err != nil {
+			return err
+		}
+
+		// Un producto creado entre el conteo y la eliminación quedaría en una categoría inexistente,
+		// así que se vuelve a contar y la categoría se restaura si apareció alguno.
+		count, err = s.products.CountByCategory(ctx, slug)
+		if err == nil && count == 0 {
+			return nil
+		}
+		if _, restoreErr := s.repository.Create(ctx, *category); restoreErr != nil {
+			log.Printf("failed to restore category %s after deleting it: %v", slug, restoreErr)
+		}
+		s.refresh(ctx)
+		if err != nil {
+			return err
+		}
+		return models.ErrCategoryInUse
+	}
+
+	if reassignTo == slug {
+		return errors.New("reassign_to must be a different category")
+	}
+	if _, err := s.repository.FindOne(ctx, reassignTo); err != nil {
+		if errors.Is(err, models.ErrCategoryNotFound) {
+			return errors.New("reassign_to must be an existing category")
+		}
+		return err
+	}
+
+	// Los productos se mueven antes de eliminar la categoría para que nunca queden en una inexistente.
+	moved, err := s.products.ReassignCategory(ctx, slug, reassignTo)
+	if err != nil {
+		return err
+	}
+	if err := s.deleteCategory(ctx, slug); err != nil {
+		return err
+	}
+	// Los productos creados en la categoría mientras se eliminaba también se mueven.
+	late, err := s.products.ReassignCategory(ctx, slug, reassignTo)
+	if err != nil {
+		log.Printf("failed to reassign late products of category %s: %v", slug, err)
+	}
+	moved += late
+
+	// La reasignación puede tocar muchos productos, así que se invalida todo el cache. La categoría
+	// ya se eliminó, así que un fallo del cache se registra en lugar de reportarse al cliente.
+	if moved > 0 {
+		if err := s.cache.InvalidateAll(ctx); err != nil {
+			log.Printf("failed to invalidate cache after deleting category %s: %v", slug, err)
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) deleteCategory(ctx context.Context, slug string) error {
+	if err := s.repository.Delete(ctx, slug); err != nil {
+		return err
+	}
+	s.refresh(ctx)
+	return nil
+}
+
+// checkParent valida que parent exista y que asignarlo a la categoría no forme un ciclo.
+func (s *CategoryService) checkParent(ctx context.Context, slug, parent string) error {
+	if parent == "" {
+		return nil
+	}
+
+	categories, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(categories))
+	for _, category := range categories {
+		parents[category.Slug] = category.Parent
+	}
+
+	if _, ok := parents[parent]; !ok {
+		return errors.New("parent must be an existing category")
+	}
+	// El límite de pasos evita recorrer indefinidamente un ciclo que ya existiera en los datos.
+	for current, steps := parent, 0; current != "" && steps <= len(parents); current, steps = parents[current], steps+1 {
+		if current == slug {
+			return errors.New("a category cannot be its own ancestor")
+		}
+	}
+	return nil
+}
+
+// refresh recarga los slugs tras una modificación; si falla, los corrige la siguiente recarga periódica.
+func (s *CategoryService) refresh(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("failed to refresh categories: %v", err)
+	}
+}
+
+// slugify normaliza un texto a minúsculas con guiones entre las secuencias de letras y dígitos.
+func slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+func initCategoryService(t *testing.T) (*CategoryService, *repository.ProductRepositoryMocked, *repository.ProductCacheMocked) {
+	t.Helper()
+
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "Electronics"})
+	cache := repository.NewProductCacheMocked()
+	service := NewCategoryService(repository.NewCategoryRepositoryMocked(), products, cache)
+	if err := service.SeedDefaults(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return service, products, cache
+}
+
+func TestCreateCategory(t *testing.T) {
+	service, _, _ := initCategoryService(t)
+	ctx := context.Background()
+
+	if !service.Exists("electronics") {
+		t.Fatalf("expected default categories to be seeded")
+	}
+
+	tc := []struct {
+		name     string
+		category models.Category
+		slug     string
+		err      string
+	}{
+		{name: "slug from name", category: models.Category{Name: "Video Games & Consoles"}, slug: "video-games-consoles"},
+		{name: "explicit slug", category: models.Category{Name: "Laptops", Slug: " Laptops ", Parent: "Electronics"}, slug: "laptops"},
+		{name: "missing parent", category: models.Category{Name: "Phones", Parent: "gadgets"}, err: "parent must be an existing category"},
+		{name: "empty slug", category: models.Category{Name: "¿?"}, err: "slug must contain letters or digits"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+		t.Run(tc.name, func(t *testing.T) {
+			created, err := service.CreateCategory(ctx, tc.category)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("unexpected error: got %v want %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created.Slug != tc.slug {
+				t.Fatalf("unexpected slug: got %v want %v", created.Slug, tc.slug)
+			}
+			if !service.Exists(tc.slug) {
+				t.Fatalf("expected %v to be a valid category", tc.slug)
+			}
+		})
+	}
+
+	if _, err := service.CreateCategory(ctx, models.Category{Name: "Books"}); !errors.Is(err, models.ErrDuplicateCategory) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrDuplicateCategory)
+	}
+}
+
+func TestUpdateCategoryParent(t *testing.T) {
+	service, _, _ := initCategoryService(t)
+	ctx := context.Background()
+
+	if _, err := service.CreateCategory(ctx, models.Category{Name: "Laptops", Parent: "electronics"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := service.UpdateCategory(ctx, "electronics", map[string]interface{}{"parent": "laptops"}); err == nil {
+		t.Fatalf("expected a cycle to be refused")
+	}
+	if err := service.UpdateCategory(ctx, "laptops", map[string]interface{}{"slug": "notebooks"}); err == nil {
+		t.Fatalf("expected the slug to be immutable")
+	}
+	if err := service.UpdateCategory(ctx, "laptops", map[string]interface{}{"parent": nil, "name": "Notebooks"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tree, err := service.GetCategoryTree(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, node := range tree {
+		if node.Slug == "laptops" && node.Name == "Notebooks" {
+			return
+		}
+	}
+	t.Fatalf("expected laptops to be a root category")
+}
+
+func TestDeleteCategory(t *testing.T) {
+	service, products, cache := initCategoryService(t)
+	ctx := context.Background()
+
+	if _, err := service.CreateCategory(ctx, models.Category{Name: "Laptops", Parent: "electronics"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := []struct {
+		name       string
+		slug       string
+		reassignTo string
+		err        error
+	}{
+		{name: "with subcategories", slug: "electronics", err: models.ErrCategoryHasChildren},
+		{name: "unknown", slug: "gadgets", err: models.ErrCategoryNotFound},
+		{name: "empty leaf", slug: "toys"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.DeleteCategory(ctx, tc.slug, tc.reassignTo)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.err)
+			}
+		})
+	}
+	if service.Exists("toys") {
+		t.Fatalf("expected toys to no longer be a valid category")
+	}
+
+	// Sin reasignación no se elimina una categoría con productos.
+	if err := service.UpdateCategory(ctx, "laptops", map[string]interface{}{"parent": ""}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteCategory(ctx, "electronics", ""); !errors.Is(err, models.ErrCategoryInUse) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrCategoryInUse)
+	}
+	if err := service.DeleteCategory(ctx, "electronics", "gadgets"); err == nil {
+		t.Fatalf("expected an unknown reassign_to to be refused")
+	}
+
+	if err := service.DeleteCategory(ctx, "electronics", "laptops"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product, _ := products.FindOne(ctx, testProductID)
+	if product.Category != "laptops" {
+		t.Fatalf("unexpected category: got %v want %v", product.Category, "laptops")
+	}
+	if cache.InvalidatedAll != 1 {
+		t.Fatalf("unexpected cache invalidations: got %v want %v", cache.InvalidatedAll, 1)
+	}
+}
+
+func TestCategoryReorderRules(t *testing.T) {
+	ctx := context.Background()
+	service, products, _ := initCategoryService(t)
+	utils.SetReorderRulesLookup(service.ReorderRules)
+	t.Cleanup(func() { utils.SetReorderRulesLookup(nil) })
+
+	if rule := service.ReorderRules()["electronics"]; rule != constants.CategoryReorderRules["electronics"] {
+		t.Fatalf("unexpected seeded rule: got %+v want %+v", rule, constants.CategoryReorderRules["electronics"])
+	}
+
+	point, quantity := uint(4), uint(12)
+	if _, err := service.CreateCategory(ctx, models.Category{Name: "Gadgets", ReorderPoint: &point, ReorderQuantity: &quantity}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule := service.ReorderRules()["gadgets"]; rule != (constants.ReorderRule{Point: 4, Quantity: 12}) {
+		t.Fatalf("unexpected rule: got %+v want %+v", rule, constants.ReorderRule{Point: 4, Quantity: 12})
+	}
+
+	// Una categoría creada sin regla no genera alertas, pero la guarda para que no se rellene al sembrar.
+	created, err := service.CreateCategory(ctx, models.Category{Name: "Misc"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ReorderPoint == nil || *created.ReorderPoint != 0 {
+		t.Fatalf("unexpected reorder point: got %v want 0", created.ReorderPoint)
+	}
+
+	// Los productos de una categoría creada en ejecución usan su regla.
+	id, err := products.Create(ctx, models.Product{Title: "Drone", Category: "gadgets", Stock: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	low, err := products.FindLowStock(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var drone *models.Product
+	for i := range low {
+		if low[i].ID == id {
+			drone = &low[i]
+		}
+	}
+	if drone == nil {
+		t.Fatalf("expected %v to be a low stock product", id)
+	}
+	if alert := models.NewLowStockAlert(drone, time.Now()); alert.ReorderPoint != 4 || alert.ReorderQuantity != 12 {
+		t.Fatalf("unexpected alert rule: got %v/%v want %v/%v", alert.ReorderPoint, alert.ReorderQuantity, 4, 12)
+	}
+
+	tc := []struct {
+		name     string
+		fields   map[string]interface{}
+		err      bool
+		expected constants.ReorderRule
+	}{
+		{name: "point", fields: map[string]interface{}{"reorder_point": float64(7)}, expected: constants.ReorderRule{Point: 7, Quantity: 12}},
+		{name: "disabled", fields: map[string]interface{}{"reorder_point": float64(0)}, expected: constants.ReorderRule{Quantity: 12}},
+		{name: "negative", fields: map[string]interface{}{"reorder_quantity": float64(-1)}, err: true},
+		{name: "fractional", fields: map[string]interface{}{"reorder_point": 1.5}, err: true},
+		{name: "not a number", fields: map[string]interface{}{"reorder_point": "5"}, err: true},
+		{name: "null", fields: map[string]interface{}{"reorder_point": nil}, err: true},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+		t.Run(tc.name, func(t *testing.T) {
+			before := service.ReorderRules()["gadgets"]
+			err := service.UpdateCategory(ctx, "gadgets", tc.fields)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: got %v want error %v", err, tc.err)
+			}
+			expected := tc.expected
+			if tc.err {
+				expected = before
+			}
+			if rule := service.ReorderRules()["gadgets"]; rule != expected {
+				t.Fatalf("unexpected rule: got %+v want %+v", rule, expected)
+			}
+		})
+	}
+}
+
+func TestSeedDefaultsBackfillsReorderRules(t *testing.T) {
+	ctx := context.Background()
+	disabled := uint(0)
+	categories := repository.NewCategoryRepositoryMocked(
+		models.Category{Slug: "toys", Name: "Toys"},
+		models.Category{Slug: "books", Name: "Books", ReorderPoint: &disabled},
+		models.Category{Slug: "gadgets", Name: "Gadgets"},
+	)
+	service := NewCategoryService(categories, repository.NewProductRepositoryMocked(), repository.NewProductCacheMocked())
+
+	if err := service.SeedDefaults(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]constants.ReorderRule{
+		"toys":    constants.CategoryReorderRules["toys"],
+		"books":   {},
+		"gadgets": {},
+	}
+	if rules := service.ReorderRules(); !reflect.DeepEqual(rules, expected) {
+		t.Fatalf("unexpected rules: got %+v want %+v", rules, expected)
+	}
+}
+
+// lateProductRepository crea un producto en la categoría justo después del primer conteo o
+// reasignación, como si se hubiera creado mientras la categoría se eliminaba.
+type lateProductRepository struct {
+	*repository.ProductRepositoryMocked
+	arrived bool
+}
+
+func (r *lateProductRepository) arrive(ctx context.Context, category string) {
+	if !r.arrived {
+		r.arrived = true
+		_, _ = r.ProductRepositoryMocked.Create(ctx, models.Product{Title: "Doll", Category: category})
+	}
+}
+
+func (r *lateProductRepository) CountByCategory(ctx context.Context, category string) (int64, error) {
+	count, err := r.ProductRepositoryMocked.CountByCategory(ctx, category)
+	r.arrive(ctx, category)
+	return count, err
+}
+
+func (r *lateProductRepository) ReassignCategory(ctx context.Context, from, to string) (int64, error) {
+	moved, err := r.ProductRepositoryMocked.ReassignCategory(ctx, from, to)
+	r.arrive(ctx, from)
+	return moved, err
+}
+
+func TestDeleteCategoryWithLateProduct(t *testing.T) {
+	tc := []struct {
+		name       string
+		reassignTo string
+		err        error
+	}{
+		{name: "without reassign restores the category", err: models.ErrCategoryInUse},
+		{name: "with reassign moves the late product", reassignTo: "electronics"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, products, _ := initCategoryService(t)
+			service.products = &lateProductRepository{ProductRepositoryMocked: products}
+
+			err := service.DeleteCategory(ctx, "toys", tc.reassignTo)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.err)
+			}
+			if service.Exists("toys") != (tc.err != nil) {
+				t.Fatalf("unexpected toys existence: got %v want %v", service.Exists("toys"), tc.err != nil)
+			}
+			if tc.err != nil {
+				if _, err := service.GetOneCategory(ctx, "toys"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			left, _ := products.CountByCategory(ctx, "toys")
+			if tc.reassignTo != "" && left != 0 {
+				t.Fatalf("unexpected products left in toys: got %v want 0", left)
+			}
+		})
+	}
+}
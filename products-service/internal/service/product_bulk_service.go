@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// errBulkLimit indica que la operación afectaría más productos de los admitidos.
+var errBulkLimit = fmt.Errorf("bulk operations are limited to %d products, narrow the filter", constants.MaxBulkProducts)
+
+// BulkProducts actualiza o envía a la papelera en una sola escritura los productos elegidos por IDs o por
+// filtro. Los productos se leen antes para conocer sus IDs, que acotan la escritura, y sus precios anteriores;
+// el cache se invalida una sola vez para todos ellos. Los productos con reservas abiertas no se borran.
+func (s *ProductService) BulkProducts(ctx context.Context, request models.BulkRequest) (*models.BulkResult, error) {
+	filter, err := bulkFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	if request.Delete == (request.Update != nil) {
+		return nil, errors.New("send either an update document or delete: true")
+	}
+
+	var price *models.Money
+	if request.Update != nil {
+		if price, err = validateBulkUpdate(request.Update); err != nil {
+			return nil, err
+		}
+	}
+
+	products := []models.Product{}
+	skipped := int64(0)
+	err = s.repository.StreamAll(ctx, filter, func(product *models.Product) error {
+		// El precio de un producto con variantes no puede cambiar de moneda.
+		if price != nil && len(product.Variants) > 0 && price.Currency != product.Price.Currency {
+			skipped++
+			return nil
+		}
+		// Sus reservas deben cerrarse antes de enviarlo a la papelera.
+		if request.Delete && product.Reserved > 0 {
+			skipped++
+			return nil
+		}
+		if len(products) == constants.MaxBulkProducts {
+			return errBulkLimit
+		}
+		products = append(products, *product)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return &models.BulkResult{Skipped: skipped}, nil
+	}
+
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	filter.IDs = ids
+
+	var result *models.BulkResult
+	if request.Delete {
+		result, err = s.repository.BulkDelete(ctx, filter)
+	} else {
+		result, err = s.repository.BulkUpdate(ctx, filter, request.Update)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Skipped = skipped
+
+	// Invalida los productos y los listados en el cache una sola vez.
+	s.invalidateCache(ctx, ids...)
+
+	if price != nil {
+		for _, product := range products {
+			s.recordPriceChange(ctx, product.ID, "", &product.Price, *price)
+		}
+	}
+
+	return result, nil
+}
+
+// bulkFilter traduce los IDs o el filtro de la solicitud al filtro del listado; debe indicarse uno de los dos.
+func bulkFilter(request models.BulkRequest) (models.ProductFilter, error) {
+	if (len(request.IDs) > 0) == (request.Filter != nil) {
+		return models.ProductFilter{}, errors.New("send either ids or a filter")
+	}
+
+	if len(request.IDs) > 0 {
+		if len(request.IDs) > constants.MaxBulkProducts {
+			return models.ProductFilter{}, errBulkLimit
+		}
+		for _, id := range request.IDs {
+			if !primitive.IsValidObjectID(id) {
+				return models.ProductFilter{}, errors.New("invalid product id " + id)
+			}
+		}
+		return models.ProductFilter{IDs: request.IDs}, nil
+	}
+
+	criteria := request.Filter
+	filter := models.ProductFilter{
+		Query:     strings.TrimSpace(criteria.Query),
+		Category:  strings.ToLower(strings.TrimSpace(criteria.Category)),
+		MinPrice:  criteria.MinPrice,
+		MaxPrice:  criteria.MaxPrice,
+		InStock:   criteria.InStock,
+		MinRating: criteria.MinRating,
+	}
+	if filter.Category != "" && !utils.IsValidCategory(filter.Category) {
+		return filter, errors.New("invalid filter category")
+	}
+	if criteria.Currency != "" {
+		price, err := models.Money{Currency: criteria.Currency}.Normalize()
+		if err != nil {
+			return filter, err
+		}
+		filter.Currency = price.Currency
+	}
+	if len(criteria.Attributes) > 0 {
+		filter.Attributes = make(map[string]string, len(criteria.Attributes))
+		for name, value := range criteria.Attributes {
+			name, value, err := utils.NormalizeAttribute(name, value)
+			if err != nil {
+				return filter, err
+			}
+			filter.Attributes[name] = value
+		}
+	}
+
+	// Un filtro vacío elegiría todo el catálogo.
+	if filter.Query == "" && filter.Category == "" && filter.MinPrice == nil && filter.MaxPrice == nil &&
+		filter.Currency == "" && !filter.InStock && filter.MinRating == nil && len(filter.Attributes) == 0 {
+		return filter, errors.New("the filter needs at least one criterion")
+	}
+	return filter, nil
+}
+
+// validateBulkUpdate aplica las reglas de PATCH /products/:id a una actualización masiva. El SKU y los
+// códigos de barras son únicos por producto, y el precio debe indicar su moneda porque los productos
+// pueden tener monedas distintas. Retorna el precio nuevo, si cambia.
+func validateBulkUpdate(fields map[string]interface{}) (*models.Money, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+	for _, field := range []string{"sku", "barcodes"} {
+		if _, ok := fields[field]; ok {
+			return nil, errors.New(field + " is unique per product and cannot be updated in bulk")
+		}
+	}
+	if err := validateProductUpdate(fields); err != nil {
+		return nil, err
+	}
+
+	value, ok := fields["price"]
+	if !ok {
+		return nil, nil
+	}
+	price, err := parseMoney(value, "")
+	if err != nil {
+		return nil, err
+	}
+	fields["price"] = price
+	return &price, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestBulkProducts(t *testing.T) {
+	cache := repository.NewProductCacheMocked()
+	service, products := initProductService(t, cache)
+	ctx := context.Background()
+
+	for _, product := range []models.Product{
+		{SKU: "TY-001", Title: "Robot", Category: "toys", Price: models.Money{Amount: 1000, Currency: "USD"}},
+		{SKU: "TY-002", Title: "Puzzle", Category: "toys", Price: models.Money{Amount: 2000, Currency: "USD"}},
+		{SKU: "BK-001", Title: "Novel", Category: "books", Price: models.Money{Amount: 1500, Currency: "USD"}},
+	} {
+		if err := service.CreateProduct(ctx, product); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	novel, _ := products.FindBySKU(ctx, "BK-001")
+
+	tc := []struct {
+		Name    string
+		Request models.BulkRequest
+		Valid   bool
+	}{
+		{Name: "Ids and filter", Request: models.BulkRequest{IDs: []string{novel.ID}, Filter: &models.BulkFilter{Category: "toys"}, Delete: true}},
+		{Name: "Neither ids nor filter", Request: models.BulkRequest{Delete: true}},
+		{Name: "Update and delete", Request: models.BulkRequest{IDs: []string{novel.ID}, Update: map[string]interface{}{"title": "x"}, Delete: true}},
+		{Name: "Empty filter", Request: models.BulkRequest{Filter: &models.BulkFilter{}, Delete: true}},
+		{Name: "Invalid id", Request: models.BulkRequest{IDs: []string{"toys"}, Delete: true}},
+		{Name: "Unique field", Request: models.BulkRequest{IDs: []string{novel.ID}, Update: map[string]interface{}{"sku": "BK-002"}}},
+		{Name: "Stock field", Request: models.BulkRequest{IDs: []string{novel.ID}, Update: map[string]interface{}{"stock": 3.0}}},
+		{Name: "Price without currency", Request: models.BulkRequest{IDs: []string{novel.ID}, Update: map[string]interface{}{"price": map[string]interface{}{"amount": 100.0}}}},
+		{Name: "Valid update", Request: models.BulkRequest{IDs: []string{novel.ID}, Update: map[string]interface{}{"reorder_point": 2.0}}, Valid: true},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := service.BulkProducts(ctx, tc.Request)
+			if (err == nil) != tc.Valid {
+				t.Fatalf("unexpected error: got %v want valid %v", err, tc.Valid)
+			}
+		})
+	}
+
+	// Una actualización por filtro invalida el cache una sola vez y registra los precios anteriores.
+	version, _ := cache.ListVersion(ctx)
+	result, err := service.BulkProducts(ctx, models.BulkRequest{
+		Filter: &models.BulkFilter{Category: "Toys"},
+		Update: map[string]interface{}{"price": map[string]interface{}{"amount": 900.0, "currency": "usd"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Matched != 2 || result.Modified != 2 {
+		t.Fatalf("unexpected result: got %+v", result)
+	}
+	if current, _ := cache.ListVersion(ctx); current != version+1 {
+		t.Fatalf("unexpected invalidations: got %v want %v", current-version, 1)
+	}
+	robot, _ := products.FindBySKU(ctx, "TY-001")
+	history, _ := service.GetPriceHistory(ctx, robot.ID, 1, 10)
+	if robot.Price.Amount != 900 || len(history) != 2 || history[0].Previous.Amount != 1000 {
+		t.Fatalf("unexpected price change: got %+v and %+v", robot.Price, history)
+	}
+
+	result, err = service.BulkProducts(ctx, models.BulkRequest{IDs: []string{robot.ID, novel.ID}, Delete: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Matched != 2 || result.Modified != 2 {
+		t.Fatalf("unexpected result: got %+v", result)
+	}
+	if _, err := products.FindOne(ctx, novel.ID); !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductNotFound)
+	}
+}
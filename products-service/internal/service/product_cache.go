@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// Familias de claves del cache; cada una tiene su propia vigencia y sus propios contadores.
+const (
+	CacheFamilyProduct = "product"
+	CacheFamilyList    = "list"
+)
+
+// CacheOptions configura la vigencia de las entradas del cache de productos.
+type CacheOptions struct {
+	// ProductTTL y ListTTL son el tiempo que una entrada se considera fresca.
+	ProductTTL time.Duration
+	ListTTL    time.Duration
+	// StaleTTL es el tiempo adicional durante el que una entrada vencida todavía se sirve
+	// mientras una sola goroutine la refresca. En 0 desactiva stale-while-revalidate.
+	StaleTTL time.Duration
+}
+
+// DefaultCacheOptions retorna la configuración por defecto: 60 segundos sin stale-while-revalidate.
+func DefaultCacheOptions() CacheOptions {
+	return CacheOptions{ProductTTL: 60 * time.Second, ListTTL: 60 * time.Second}
+}
+
+type cacheMetrics struct {
+	hits, misses, stale, coalesced, refreshes, refreshErrors, errors atomic.Uint64
+}
+
+func (m *cacheMetrics) snapshot() models.CacheStats {
+	return models.CacheStats{
+		Hits:          m.hits.Load(),
+		Misses:        m.misses.Load(),
+		Stale:         m.stale.Load(),
+		Coalesced:     m.coalesced.Load(),
+		Refreshes:     m.refreshes.Load(),
+		RefreshErrors: m.refreshErrors.Load(),
+		Errors:        m.errors.Load(),
+	}
+}
+
+// CacheStatus retorna el estado del cache y sus contadores por familia de claves.
+func (s *ProductService) CacheStatus() models.CacheStatus {
+	status := models.CacheStatus{
+		State:    "unknown",
+		Families: make(map[string]models.CacheStats, len(s.metrics)),
+	}
+	if health, ok := s.cache.(interfaces.CacheHealthInterface); ok {
+		status.State = health.State()
+	}
+	for family, m := range s.metrics {
+		status.Families[family] = m.snapshot()
+	}
+	return status
+}
+
+// invalidateCache invalida el cache tras una escritura. La escritura ya se aplicó en MongoDB,
+// así que un fallo del cache se registra en lugar de reportarse al cliente.
+func (s *ProductService) invalidateCache(ctx context.Context, ids ...string) {
+	if err := s.cache.InvalidateProducts(ctx, ids...); err != nil {
+		logCacheError("invalidate", err)
+	}
+}
+
+// logCacheError registra un fallo del cache. Con el circuito abierto todas las llamadas fallan,
+// así que ese caso solo se cuenta en las métricas.
+func logCacheError(op string, err error) {
+	if !errors.Is(err, models.ErrCacheUnavailable) {
+		log.Printf("cache %s failed, falling back to MongoDB: %v", op, err)
+	}
+}
+
+// cacheLoader describe cómo leer, cargar y guardar una clave del cache.
+type cacheLoader[T any] struct {
+	family string
+	// key identifica la entrada entre todas las familias; agrupa las cargas concurrentes.
+	key string
+	ttl time.Duration
+	get func(ctx context.Context) (*T, time.Time, error)
+	// version retorna la versión del cache con la que se guardará el valor; se lee antes de cargarlo.
+	version func(ctx context.Context) (int64, error)
+	load    func(ctx context.Context) (*T, error)
+	set     func(ctx context.Context, version int64, value *T, ttl time.Duration) error
+}
+
+// cached resuelve una lectura a través del cache:
+//   - una entrada fresca se sirve directamente;
+//   - una entrada vencida dentro de StaleTTL se sirve y se refresca en segundo plano;
+//   - sin entrada, o si el cache falla, se carga de la base de datos.
+//
+// Las cargas concurrentes de una misma clave se agrupan para que solo una llegue a la base de datos.
+func cached[T any](ctx context.Context, s *ProductService, l cacheLoader[T]) (*T, error) {
+	metrics := s.metrics[l.family]
+
+	value, storedAt, err := l.get(ctx)
+	if err != nil {
+		metrics.errors.Add(1)
+		logCacheError("read", err)
+		value = nil
+	}
+
+	if value != nil {
+		age := time.Since(storedAt)
+		if age < l.ttl {
+			metrics.hits.Add(1)
+			return value, nil
+		}
+		if age < l.ttl+s.cacheOptions.StaleTTL {
+			metrics.stale.Add(1)
+			refreshAsync(ctx, s, l)
+			return value, nil
+		}
+	}
+
+	metrics.misses.Add(1)
+	// La carga no depende de la solicitud que la inició: otras pueden estar esperándola.
+	// singleflight marca como compartido también el resultado de quien ejecutó la carga,
+	// así que solo se cuentan las lecturas que no la ejecutaron.
+	loaded := false
+	result, err, _ := s.flight.Do(l.key, func() (interface{}, error) {
+		loaded = true
+		return load(context.WithoutCancel(ctx), s, l)
+	})
+	if !loaded {
+		metrics.coalesced.Add(1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*T), nil
+}
+
+// refreshAsync refresca la entrada en una goroutine, salvo que ya haya un refresco en curso para la clave.
+func refreshAsync[T any](ctx context.Context, s *ProductService, l cacheLoader[T]) {
+	if _, running := s.refreshing.LoadOrStore(l.key, struct{}{}); running {
+		return
+	}
+
+	metrics := s.metrics[l.family]
+	metrics.refreshes.Add(1)
+
+	go func(ctx context.Context) {
+		defer s.refreshing.Delete(l.key)
+
+		_, err, _ := s.flight.Do(l.key, func() (interface{}, error) {
+			return load(ctx, s, l)
+		})
+		if err != nil {
+			metrics.refreshErrors.Add(1)
+			log.Printf("failed to refresh cache key %s: %v", l.key, err)
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+// load consulta la base de datos y guarda el resultado. La entrada vive en Redis durante su vigencia
+// más la ventana en la que todavía puede servirse vencida. Si no se puede guardar, el resultado
+// igual se retorna.
+//
+// La versión se lee antes de consultar la base de datos: si una escritura la incrementa mientras tanto,
+// el resultado puede ser anterior a ella y el cache lo descarta en lugar de guardarlo.
+func load[T any](ctx context.Context, s *ProductService, l cacheLoader[T]) (*T, error) {
+	version, versionErr := l.version(ctx)
+
+	value, err := l.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if versionErr == nil {
+		versionErr = l.set(ctx, version, value, l.ttl+s.cacheOptions.StaleTTL)
+	}
+	if versionErr != nil {
+		s.metrics[l.family].errors.Add(1)
+		logCacheError("write", versionErr)
+	}
+	return value, nil
+}
+
+// newCacheMetrics crea los contadores de cada familia de claves.
+func newCacheMetrics() map[string]*cacheMetrics {
+	return map[string]*cacheMetrics{
+		CacheFamilyProduct: {},
+		CacheFamilyList:    {},
+	}
+}
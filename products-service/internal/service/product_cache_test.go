@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func initCachedService(t *testing.T, options CacheOptions) (*ProductService, *gatedRepository, *repository.ProductCacheMocked) {
+	t.Helper()
+
+	products := newGatedRepository(repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 50}))
+	cache := repository.NewProductCacheMocked()
+	service := NewProductService(products, cache, nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, options)
+
+	return service, products, cache
+}
+
+// waitFor espera hasta un segundo a que se cumpla la condición.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCacheCoalescesConcurrentLoads(t *testing.T) {
+	const readers = 10
+
+	service, products, _ := initCachedService(t, DefaultCacheOptions())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			product, err := service.GetOneProduct(ctx, testProductID)
+			if err != nil || product.Title != "Keyboard" {
+				t.Errorf("unexpected result: %v %v", product, err)
+			}
+		}()
+	}
+
+	// Todas las lecturas fallan el cache y esperan la carga que ya está en curso.
+	<-products.started
+	waitFor(t, func() bool { return service.CacheStatus().Families[CacheFamilyProduct].Misses == readers })
+	time.Sleep(10 * time.Millisecond)
+	close(products.gate)
+	wg.Wait()
+
+	if loads := products.loads.Load(); loads != 1 {
+		t.Fatalf("unexpected loads: got %v want %v", loads, 1)
+	}
+	if stats := service.CacheStatus().Families[CacheFamilyProduct]; stats.Coalesced != readers-1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	tc := []struct {
+		Name    string
+		Options CacheOptions
+		Age     time.Duration
+		Title   string
+		Stats   models.CacheStats
+	}{
+		{
+			Name:    "Fresh",
+			Options: CacheOptions{ProductTTL: time.Minute, StaleTTL: time.Minute},
+			Age:     30 * time.Second,
+			Title:   "Keyboard",
+			Stats:   models.CacheStats{Hits: 1},
+		},
+		{
+			Name:    "Stale",
+			Options: CacheOptions{ProductTTL: time.Minute, StaleTTL: time.Minute},
+			Age:     90 * time.Second,
+			Title:   "Keyboard",
+			Stats:   models.CacheStats{Stale: 1, Refreshes: 1},
+		},
+		{
+			Name:    "Past the stale window",
+			Options: CacheOptions{ProductTTL: time.Minute, StaleTTL: time.Minute},
+			Age:     3 * time.Minute,
+			Title:   "Mechanical keyboard",
+			Stats:   models.CacheStats{Misses: 1},
+		},
+		{
+			Name:    "Without stale-while-revalidate",
+			Options: CacheOptions{ProductTTL: time.Minute},
+			Age:     90 * time.Second,
+			Title:   "Mechanical keyboard",
+			Stats:   models.CacheStats{Misses: 1},
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			service, products, cache := initCachedService(t, tc.Options)
+			ctx := context.Background()
+
+			// El cache guarda la versión anterior del producto.
+			_ = cache.SetOne(ctx, 0, testProductID, &models.Product{ID: testProductID, Title: "Keyboard"}, time.Hour)
+			cache.Age(tc.Age)
+			_ = products.Update(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"})
+			if tc.Stats.Misses > 0 {
+				close(products.gate)
+			}
+
+			product, err := service.GetOneProduct(ctx, testProductID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if product.Title != tc.Title {
+				t.Fatalf("unexpected title: got %v want %v", product.Title, tc.Title)
+			}
+			if stats := service.CacheStatus().Families[CacheFamilyProduct]; stats != tc.Stats {
+				t.Fatalf("unexpected stats: got %+v want %+v", stats, tc.Stats)
+			}
+			if tc.Stats.Refreshes == 0 {
+				return
+			}
+
+			// Mientras el refresco está en curso se sigue sirviendo la entrada vencida sin lanzar otro.
+			<-products.started
+			if product, _ := service.GetOneProduct(ctx, testProductID); product.Title != "Keyboard" {
+				t.Fatalf("unexpected title: got %v want %v", product.Title, "Keyboard")
+			}
+			if stats := service.CacheStatus().Families[CacheFamilyProduct]; stats.Refreshes != 1 {
+				t.Fatalf("unexpected stats: %+v", stats)
+			}
+
+			close(products.gate)
+			waitFor(t, func() bool {
+				cached, _, _ := cache.GetOne(ctx, testProductID)
+				return cached.Title == "Mechanical keyboard"
+			})
+			if product, _ := service.GetOneProduct(ctx, testProductID); product.Title != "Mechanical keyboard" {
+				t.Fatalf("unexpected title: got %v want %v", product.Title, "Mechanical keyboard")
+			}
+			if loads := products.loads.Load(); loads != 1 {
+				t.Fatalf("unexpected loads: got %v want %v", loads, 1)
+			}
+		})
+	}
+}
+
+func TestProductLoadedBeforeWriteIsNotCached(t *testing.T) {
+	service, products, _ := initCachedService(t, DefaultCacheOptions())
+	ctx := context.Background()
+
+	// Una lectura carga el producto antes de la escritura e intenta guardarlo después de su invalidación.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if product, err := service.GetOneProduct(ctx, testProductID); err != nil || product.Title != "Keyboard" {
+			t.Errorf("unexpected result: %v %v", product, err)
+		}
+	}()
+	<-products.started
+	if err := service.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(products.gate)
+	<-done
+
+	product, err := service.GetOneProduct(ctx, testProductID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Title != "Mechanical keyboard" {
+		t.Fatalf("unexpected title: got %v want %v", product.Title, "Mechanical keyboard")
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/xlsx"
+)
+
+// productEncoder escribe los productos exportados en un formato de archivo.
+type productEncoder interface {
+	Encode(product *models.Product) error
+	// Close escribe lo que quede pendiente; el archivo no está completo hasta entonces.
+	Close() error
+}
+
+// ExportProducts escribe en w, en el formato indicado, todos los productos que cumplen el filtro del listado,
+// a medida que se leen de la base de datos. Cada producto incluye el valor de su stock disponible.
+func (s *ProductService) ExportProducts(ctx context.Context, w io.Writer, format string, filter models.ProductFilter) error {
+	encoder, err := newProductEncoder(w, format)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.StreamAll(ctx, filter, encoder.Encode); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// newProductEncoder crea el codificador del formato y escribe el encabezado, si el formato lo tiene.
+func newProductEncoder(w io.Writer, format string) (productEncoder, error) {
+	switch format {
+	case models.ProductFormatCSV:
+		encoder := &csvProductEncoder{writer: csv.NewWriter(w)}
+		return encoder, encoder.writer.Write(models.ProductExportColumns)
+	case models.ProductFormatNDJSON:
+		buffered := bufio.NewWriter(w)
+		return &ndjsonProductEncoder{buffered: buffered, encoder: json.NewEncoder(buffered)}, nil
+	case models.ProductFormatXLSX:
+		writer, err := xlsx.NewWriter(w, "Products")
+		if err != nil {
+			return nil, err
+		}
+		header := make([]interface{}, len(models.ProductExportColumns))
+		for i, column := range models.ProductExportColumns {
+			header[i] = column
+		}
+		return &xlsxProductEncoder{writer: writer}, writer.WriteRow(header...)
+	default:
+		return nil, fmt.Errorf("%w: %q, use csv, ndjson or xlsx", models.ErrUnsupportedFormat, format)
+	}
+}
+
+// exportRecord retorna las celdas de un producto en el orden de models.ProductExportColumns;
+// las reglas de reposición sin valor y un valor del stock que no cabe en un int64 quedan vacíos.
+func exportRecord(product *models.Product) []interface{} {
+	record := []interface{}{
+		product.ID, product.SKU, product.Title, product.Description, product.Category,
+		product.Price.Amount, product.Price.Currency, product.Stock, product.Reserved, nil,
+		strings.Join(product.Barcodes, "|"), nil, nil,
+	}
+	if value, ok := product.StockValue(); ok {
+		record[9] = value
+	}
+	if product.ReorderPoint != nil {
+		record[11] = *product.ReorderPoint
+	}
+	if product.ReorderQuantity != nil {
+		record[12] = *product.ReorderQuantity
+	}
+	return record
+}
+
+type csvProductEncoder struct {
+	writer *csv.Writer
+}
+
+func (e *csvProductEncoder) Encode(product *models.Product) error {
+	record := exportRecord(product)
+	fields := make([]string, len(record))
+	for i, value := range record {
+		switch value := value.(type) {
+		case nil:
+		case string:
+			fields[i] = escapeCSVFormula(value)
+		default:
+			fields[i] = fmt.Sprint(value)
+		}
+	}
+	return e.writer.Write(fields)
+}
+
+// csvFormulaPrefixes son los caracteres con los que una hoja de cálculo interpreta una celda como fórmula.
+const csvFormulaPrefixes = "=+-@"
+
+// escapeCSVFormula antepone un apóstrofo a los textos que una hoja de cálculo ejecutaría como fórmula;
+// la importación de CSV lo quita.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVFormula revierte escapeCSVFormula.
+func unescapeCSVFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func (e *csvProductEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// ndjsonProductEncoder escribe cada producto con los campos de la API más stock_value.
+type ndjsonProductEncoder struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+// Encode escribe stock_value en null si el valor no cabe en un int64.
+func (e *ndjsonProductEncoder) Encode(product *models.Product) error {
+	var stockValue *int64
+	if value, ok := product.StockValue(); ok {
+		stockValue = &value
+	}
+	return e.encoder.Encode(struct {
+		*models.Product
+		StockValue *int64 `json:"stock_value"`
+	}{product, stockValue})
+}
+
+func (e *ndjsonProductEncoder) Close() error {
+	return e.buffered.Flush()
+}
+
+type xlsxProductEncoder struct {
+	writer *xlsx.Writer
+}
+
+func (e *xlsxProductEncoder) Encode(product *models.Product) error {
+	return e.writer.WriteRow(exportRecord(product)...)
+}
+
+func (e *xlsxProductEncoder) Close() error {
+	return e.writer.Close()
+}
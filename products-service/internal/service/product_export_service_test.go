@@ -0,0 +1,176 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestExportProducts(t *testing.T) {
+	service, _ := initProductService(t, repository.NewProductCacheMocked())
+	ctx := context.Background()
+
+	err := service.CreateProduct(ctx, models.Product{SKU: "TS-020", Title: "T-shirt", Category: "clothing", Price: models.Money{Amount: 2000, Currency: "USD"}, Variants: []models.Variant{
+		{SKU: "TS-020-M", Attributes: map[string]string{"size": "M"}, Price: models.Money{Amount: 2000}, Stock: 3},
+		{SKU: "TS-020-L", Attributes: map[string]string{"size": "L"}, Price: models.Money{Amount: 2500}, Stock: 2},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := service.ExportProducts(ctx, &buf, models.ProductFormatCSV, models.ProductFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exported := buf.String()
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(records) != 3 || len(records[0]) != len(models.ProductExportColumns) {
+		t.Fatalf("unexpected csv: got %v", records)
+	}
+
+	tc := []struct {
+		Name       string
+		Record     []string
+		StockValue string
+	}{
+		{Name: "Product without variants", Record: records[1], StockValue: "500000"},
+		{Name: "Product with variants", Record: records[2], StockValue: "11000"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if value := tc.Record[9]; value != tc.StockValue {
+				t.Fatalf("unexpected stock_value: got %v want %v", value, tc.StockValue)
+			}
+		})
+	}
+
+	// Un CSV exportado se puede volver a importar; el producto de prueba inicial no tiene SKU.
+	report, err := service.ImportProducts(ctx, bytes.NewBufferString(exported), models.ProductFormatCSV, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Updated != 1 || report.Failed != 1 || report.Rows[1].SKU != "TS-020" {
+		t.Fatalf("unexpected import report: got %+v", report)
+	}
+
+	buf.Reset()
+	if err := service.ExportProducts(ctx, &buf, models.ProductFormatNDJSON, models.ProductFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var line struct {
+		SKU        string `json:"sku"`
+		StockValue int64  `json:"stock_value"`
+	}
+	if err := json.NewDecoder(&buf).Decode(&line); err != nil || line.StockValue != 500000 {
+		t.Fatalf("unexpected ndjson line: got %+v (%v)", line, err)
+	}
+
+	buf.Reset()
+	if err := service.ExportProducts(ctx, &buf, models.ProductFormatXLSX, models.ProductFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Fatalf("invalid xlsx: %v", err)
+	}
+
+	if err := service.ExportProducts(ctx, &buf, "pdf", models.ProductFilter{}); !errors.Is(err, models.ErrUnsupportedFormat) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrUnsupportedFormat)
+	}
+}
+
+func TestExportEscapesFormulas(t *testing.T) {
+	service, products := initProductService(t, repository.NewProductCacheMocked())
+	ctx := context.Background()
+
+	title, description := "=HYPERLINK(\"http://example.com\")", "@SUM(1+1)"
+	id, err := products.Create(ctx, models.Product{SKU: "KB-001", Title: title, Description: description, Category: "electronics", Price: models.Money{Amount: 10000, Currency: "USD"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := service.ExportProducts(ctx, &buf, models.ProductFormatCSV, models.ProductFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exported := buf.String()
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("unexpected csv: got %v (%v)", records, err)
+	}
+
+	tc := []struct {
+		Name     string
+		Value    string
+		Expected string
+	}{
+		{Name: "Formula", Value: records[2][2], Expected: "'" + title},
+		{Name: "At sign", Value: records[2][3], Expected: "'" + description},
+		{Name: "Plain text", Value: records[2][4], Expected: "electronics"},
+		{Name: "Number", Value: records[2][5], Expected: "10000"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if tc.Value != tc.Expected {
+				t.Fatalf("unexpected cell: got %v want %v", tc.Value, tc.Expected)
+			}
+		})
+	}
+
+	// La importación quita el apóstrofo, así que el producto no cambia.
+	if _, err := service.ImportProducts(ctx, bytes.NewBufferString(exported), models.ProductFormatCSV, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product, _ := products.FindOne(ctx, id)
+	if product.Title != title || product.Description != description {
+		t.Fatalf("unexpected product after import: got %q %q want %q %q", product.Title, product.Description, title, description)
+	}
+}
+
+func TestStockValueOverflow(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Product  models.Product
+		Expected int64
+		Ok       bool
+	}{
+		{Name: "Fits", Product: models.Product{Price: models.Money{Amount: 1 << 31}, Stock: 1 << 31}, Expected: 1 << 62, Ok: true},
+		{Name: "Product overflows", Product: models.Product{Price: models.Money{Amount: math.MaxInt64 / 2}, Stock: 3}},
+		{Name: "Variant sum overflows", Product: models.Product{Variants: []models.Variant{
+			{Price: models.Money{Amount: math.MaxInt64 / 2}, Stock: 1},
+			{Price: models.Money{Amount: math.MaxInt64 / 2}, Stock: 1},
+			{Price: models.Money{Amount: 2}, Stock: 1},
+		}}},
+		{Name: "No stock", Product: models.Product{Price: models.Money{Amount: math.MaxInt64}}, Ok: true},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			value, ok := tc.Product.StockValue()
+			if value != tc.Expected || ok != tc.Ok {
+				t.Fatalf("unexpected stock value: got %v %v want %v %v", value, ok, tc.Expected, tc.Ok)
+			}
+			if record := exportRecord(&tc.Product); (record[9] == nil) == tc.Ok {
+				t.Fatalf("unexpected stock_value cell: got %v", record[9])
+			}
+		})
+	}
+}
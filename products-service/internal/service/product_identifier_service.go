@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+// GetProductBySKU retorna el producto con el SKU indicado, sin distinguir mayúsculas.
+func (s *ProductService) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	sku, err := utils.NormalizeSKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.FindBySKU(ctx, sku)
+}
+
+// GetProductByBarcode retorna el producto con el código EAN-13 o UPC-A indicado.
+func (s *ProductService) GetProductByBarcode(ctx context.Context, code string) (*models.Product, error) {
+	code, err := utils.NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.FindByBarcode(ctx, code)
+}
+
+// normalizeBarcodes valida los códigos de barras, los lleva a EAN-13 y descarta los repetidos.
+func normalizeBarcodes(codes []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		code, err := utils.NormalizeBarcode(code)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeIdentifierFields valida y normaliza el SKU y los códigos de barras de una actualización parcial.
+func normalizeIdentifierFields(product map[string]interface{}) error {
+	if value, ok := product["sku"]; ok {
+		sku, isString := value.(string)
+		if !isString {
+			return errors.New("sku must be a string")
+		}
+		sku, err := utils.NormalizeSKU(sku)
+		if err != nil {
+			return err
+		}
+		product["sku"] = sku
+	}
+
+	if value, ok := product["barcodes"]; ok {
+		values, isList := value.([]interface{})
+		if !isList && value != nil {
+			return errors.New("barcodes must be a list of strings")
+		}
+		codes := make([]string, 0, len(values))
+		for _, value := range values {
+			code, isString := value.(string)
+			if !isString {
+				return errors.New("barcodes must be a list of strings")
+			}
+			codes = append(codes, code)
+		}
+		barcodes, err := normalizeBarcodes(codes)
+		if err != nil {
+			return err
+		}
+		product["barcodes"] = barcodes
+	}
+	return nil
+}
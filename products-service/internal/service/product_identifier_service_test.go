@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestProductIdentifiers(t *testing.T) {
+	service, _ := initProductService(t, repository.NewProductCacheMocked())
+	ctx := context.Background()
+
+	if err := service.CreateProduct(ctx, models.Product{SKU: " kb-002 ", Title: "Keyboard", Category: "electronics", Price: models.Money{Amount: 9900, Currency: "USD"}, Barcodes: []string{"036000291452", "0036000291452"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := []struct {
+		Name    string
+		Product models.Product
+		Err     error
+	}{
+		{Name: "Duplicate SKU", Product: models.Product{SKU: "KB-002", Title: "Other", Category: "electronics", Price: models.Money{Amount: 9900, Currency: "USD"}}, Err: models.ErrDuplicateSKU},
+		{Name: "Duplicate barcode", Product: models.Product{SKU: "KB-003", Title: "Other", Category: "electronics", Price: models.Money{Amount: 9900, Currency: "USD"}, Barcodes: []string{"0036000291452"}}, Err: models.ErrDuplicateBarcode},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if err := service.CreateProduct(ctx, tc.Product); !errors.Is(err, tc.Err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.Err)
+			}
+		})
+	}
+
+	if err := service.CreateProduct(ctx, models.Product{Title: "No SKU", Category: "electronics", Price: models.Money{Amount: 9900, Currency: "USD"}}); err == nil {
+		t.Fatalf("expected a product without SKU to be refused")
+	}
+	if err := service.CreateProduct(ctx, models.Product{SKU: "KB-004", Title: "Bad barcode", Category: "electronics", Price: models.Money{Amount: 9900, Currency: "USD"}, Barcodes: []string{"036000291453"}}); err == nil {
+		t.Fatalf("expected an invalid check digit to be refused")
+	}
+
+	bySKU, err := service.GetProductBySKU(ctx, "kb-002")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bySKU.Barcodes) != 1 || bySKU.Barcodes[0] != "0036000291452" {
+		t.Fatalf("unexpected barcodes: got %v want %v", bySKU.Barcodes, []string{"0036000291452"})
+	}
+
+	byBarcode, err := service.GetProductByBarcode(ctx, "036000291452")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byBarcode.ID != bySKU.ID {
+		t.Fatalf("unexpected product: got %v want %v", byBarcode.ID, bySKU.ID)
+	}
+
+	if _, err := service.GetProductByBarcode(ctx, "4006381333931"); !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductNotFound)
+	}
+}
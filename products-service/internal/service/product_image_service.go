@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/thumbnail"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// UploadURLTTL es la vigencia de una URL de subida directa.
+	UploadURLTTL = 15 * time.Minute
+	// thumbnailQueueSize es la capacidad de la cola de miniaturas; lo que no cabe lo recoge el barrido.
+	thumbnailQueueSize = 100
+	// thumbnailBatchSize es el número de productos que revisa cada barrido de miniaturas pendientes.
+	thumbnailBatchSize = 50
+)
+
+// thumbnailJob es una imagen a la que hay que generarle la miniatura.
+type thumbnailJob struct {
+	productID string
+	imageID   string
+}
+
+// AddProductImage valida una imagen, la sube al almacenamiento y la registra en el producto.
+// El tipo se detecta a partir del contenido, no del que declara el cliente.
+func (s *ProductService) AddProductImage(ctx context.Context, id string, body io.ReadSeeker, size int64) (*models.ProductImage, error) {
+	if size > constants.MaxImageSize {
+		return nil, fmt.Errorf("%w: maximum size is %d bytes", models.ErrImageTooLarge, constants.MaxImageSize)
+	}
+
+	head, err := readHead(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+	contentType := http.DetectContentType(head)
+	extension, ok := constants.ImageExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedImage, contentType)
+	}
+
+	// Evita subir objetos de productos que no existen.
+	if _, err := s.repository.FindOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	imageID := primitive.NewObjectID().Hex()
+	key := imageKey(id, imageID, extension)
+	url, err := s.storage.Put(ctx, key, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attachImage(ctx, id, models.ProductImage{
+		ID:          imageID,
+		Key:         key,
+		URL:         url,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// CreateImageUpload firma una URL para que el cliente suba una imagen directamente al almacenamiento,
+// sin pasar por el servicio. La imagen se asocia al producto al confirmar la subida.
+func (s *ProductService) CreateImageUpload(ctx context.Context, id string, request models.ImageUploadRequest) (*models.ImageUpload, error) {
+	presigner, ok := s.storage.(interfaces.ImagePresignerInterface)
+	if !ok {
+		return nil, models.ErrPresignUnsupported
+	}
+	if request.Size > constants.MaxDirectImageSize {
+		return nil, fmt.Errorf("%w: maximum size is %d bytes", models.ErrImageTooLarge, constants.MaxDirectImageSize)
+	}
+	extension, ok := constants.ImageExtensions[request.ContentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedImage, request.ContentType)
+	}
+
+	if _, err := s.repository.FindOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	key := imageKey(id, primitive.NewObjectID().Hex(), extension)
+	url, err := presigner.PresignPut(ctx, key, request.ContentType, UploadURLTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ImageUpload{
+		Key:       key,
+		UploadURL: url,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": request.ContentType},
+		ExpiresAt: time.Now().UTC().Add(UploadURLTTL),
+	}, nil
+}
+
+// ConfirmImageUpload verifica el objeto de una subida directa y lo asocia al producto. Un objeto que
+// no cumple el tamaño o el tipo se elimina. Confirmar dos veces la misma subida retorna la misma imagen.
+func (s *ProductService) ConfirmImageUpload(ctx context.Context, id, key string) (*models.ProductImage, error) {
+	imageID, extension, err := parseImageKey(id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if image, err := s.findImage(ctx, id, imageID); !errors.Is(err, models.ErrImageNotFound) {
+		return image, err
+	}
+
+	object, size, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	head, err := readHead(object)
+	object.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if size > constants.MaxDirectImageSize {
+		s.deleteObject(ctx, key)
+		return nil, fmt.Errorf("%w: maximum size is %d bytes", models.ErrImageTooLarge, constants.MaxDirectImageSize)
+	}
+	contentType := http.DetectContentType(head)
+	if constants.ImageExtensions[contentType] != extension {
+		s.deleteObject(ctx, key)
+		return nil, fmt.Errorf("%w: %s", models.ErrUnsupportedImage, contentType)
+	}
+
+	return s.attachImage(ctx, id, models.ProductImage{
+		ID:          imageID,
+		Key:         key,
+		URL:         s.storage.URL(key),
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// findImage busca una imagen entre las del producto.
+func (s *ProductService) findImage(ctx context.Context, id, imageID string) (*models.ProductImage, error) {
+	product, err := s.repository.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range product.Images {
+		if image.ID == imageID {
+			return &image, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+}
+
+// attachImage registra en el producto una imagen ya guardada y encola su miniatura
+func (s *ProductService) attachImage(ctx context.Context, id string, image models.ProductImage) (*models.ProductImage, error) {
+	if err := s.repository.AddImage(ctx, id, image); err != nil {
+		if errors.Is(err, models.ErrDuplicateImage) {
+			// Otra confirmación de la misma subida la registró primero: el objeto es el de esa imagen.
+			return s.findImage(ctx, id, image.ID)
+		}
+		// Sin registro en el producto nadie eliminaría el objeto.
+		s.deleteObject(ctx, image.Key)
+		return nil, err
+	}
+
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	select {
+	case s.thumbnails <- thumbnailJob{productID: id, imageID: image.ID}:
+	default:
+		log.Printf("thumbnail queue is full; image %s of product %s is left to the sweep", image.ID, id)
+	}
+
+	return &image, nil
+}
+
+// DeleteProductImage quita una imagen del producto y elimina su objeto del almacenamiento.
+func (s *ProductService) DeleteProductImage(ctx context.Context, id, imageID string) error {
+	product, err := s.repository.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	image := findImage(product, imageID)
+	if image == nil {
+		return fmt.Errorf("%w with ID: %s", models.ErrImageNotFound, imageID)
+	}
+
+	// Primero se quita del producto: un objeto huérfano es preferible a una URL rota.
+	if err := s.repository.RemoveImage(ctx, id, imageID); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, id)
+	s.deleteImageObjects(ctx, *image)
+
+	return nil
+}
+
+// RunThumbnailWorker genera las miniaturas de las imágenes nuevas hasta que ctx se cancele. Cada
+// interval revisa además las pendientes, de modo que se recuperan las que no cupieron en la cola
+// o quedaron sin procesar al reiniciar el servicio.
+func (s *ProductService) RunThumbnailWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-s.thumbnails:
+			s.generateThumbnail(ctx, job)
+		case <-ticker.C:
+			products, err := s.repository.FindPendingThumbnails(ctx, thumbnailBatchSize)
+			if err != nil {
+				log.Printf("thumbnail worker: %v", err)
+				continue
+			}
+			for _, product := range products {
+				for _, image := range product.Images {
+					if image.ThumbnailKey == nil {
+						s.generateThumbnail(ctx, thumbnailJob{productID: product.ID, imageID: image.ID})
+					}
+				}
+			}
+		}
+	}
+}
+
+// generateThumbnail genera y registra la miniatura de una imagen que aún la tiene pendiente.
+// Un error del almacenamiento deja la miniatura pendiente para el siguiente barrido; una imagen
+// que no se puede decodificar se marca como fallida para no reintentarla.
+func (s *ProductService) generateThumbnail(ctx context.Context, job thumbnailJob) {
+	product, err := s.repository.FindOne(ctx, job.productID)
+	if err != nil {
+		return
+	}
+	image := findImage(product, job.imageID)
+	if image == nil || image.ThumbnailKey != nil {
+		return
+	}
+
+	data, err := s.readObject(ctx, image.Key)
+	if errors.Is(err, models.ErrImageNotFound) {
+		s.setThumbnail(ctx, job, "", "")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to read image %s of product %s: %v", job.imageID, job.productID, err)
+		return
+	}
+
+	thumb, err := thumbnail.Generate(data, constants.ThumbnailSize)
+	if err != nil {
+		log.Printf("failed to generate thumbnail of image %s of product %s: %v", job.imageID, job.productID, err)
+		s.setThumbnail(ctx, job, "", "")
+		return
+	}
+
+	key := strings.TrimSuffix(image.Key, path.Ext(image.Key)) + "_thumb.jpg"
+	url, err := s.storage.Put(ctx, key, bytes.NewReader(thumb), thumbnail.ContentType)
+	if err != nil {
+		log.Printf("failed to store thumbnail of image %s of product %s: %v", job.imageID, job.productID, err)
+		return
+	}
+	if err := s.setThumbnail(ctx, job, key, url); err != nil {
+		// La imagen se eliminó mientras se generaba la miniatura.
+		s.deleteObject(ctx, key)
+	}
+}
+
+// setThumbnail registra la miniatura e invalida el producto en el cache
+func (s *ProductService) setThumbnail(ctx context.Context, job thumbnailJob, key, url string) error {
+	if err := s.repository.SetImageThumbnail(ctx, job.productID, job.imageID, key, url); err != nil {
+		return err
+	}
+	s.invalidateCache(ctx, job.productID)
+	return nil
+}
+
+// readObject lee un objeto completo del almacenamiento, sin superar MaxDirectImageSize
+func (s *ProductService) readObject(ctx context.Context, key string) ([]byte, error) {
+	object, _, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(io.LimitReader(object, constants.MaxDirectImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+	if len(data) > constants.MaxDirectImageSize {
+		return nil, models.ErrImageTooLarge
+	}
+	return data, nil
+}
+
+// deleteImageObjects elimina los objetos de las imágenes y sus miniaturas
+func (s *ProductService) deleteImageObjects(ctx context.Context, images ...models.ProductImage) {
+	for _, image := range images {
+		s.deleteObject(ctx, image.Key)
+		if image.ThumbnailKey != nil && *image.ThumbnailKey != "" {
+			s.deleteObject(ctx, *image.ThumbnailKey)
+		}
+	}
+}
+
+// deleteObject elimina un objeto que ya nadie referencia, así que un fallo se registra en lugar de
+// reportarse al cliente.
+func (s *ProductService) deleteObject(ctx context.Context, key string) {
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("failed to delete image object %s: %v", key, err)
+	}
+}
+
+func findImage(product *models.Product, imageID string) *models.ProductImage {
+	for i := range product.Images {
+		if product.Images[i].ID == imageID {
+			return &product.Images[i]
+		}
+	}
+	return nil
+}
+
+// imageKey es la clave de una imagen en el almacenamiento: products/<producto>/<imagen><extensión>
+func imageKey(productID, imageID, extension string) string {
+	return "products/" + productID + "/" + imageID + extension
+}
+
+// parseImageKey valida que la clave de una subida directa pertenezca al producto y retorna el ID
+// de la imagen y su extensión
+func parseImageKey(productID, key string) (string, string, error) {
+	name, ok := strings.CutPrefix(key, "products/"+productID+"/")
+	extension := path.Ext(name)
+	imageID := strings.TrimSuffix(name, extension)
+	if !ok || !primitive.IsValidObjectID(imageID) {
+		return "", "", models.ErrInvalidUploadKey
+	}
+	for _, valid := range constants.ImageExtensions {
+		if extension == valid {
+			return imageID, extension, nil
+		}
+	}
+	return "", "", models.ErrInvalidUploadKey
+}
+
+// readHead lee los primeros bytes de una imagen, suficientes para detectar su tipo
+func readHead(body io.Reader) ([]byte, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+	return head[:n], nil
+}
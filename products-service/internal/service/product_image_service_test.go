@@ -0,0 +1,312 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/storage"
+)
+
+func initImageService(t *testing.T) (*ProductService, *repository.ProductRepositoryMocked, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics"})
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), storage.NewLocalStorage(dir, "/images"), DefaultCacheOptions())
+
+	return service, products, dir
+}
+
+func pngImage(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("could not encode image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAddProductImage(t *testing.T) {
+	tc := []struct {
+		Name          string
+		ProductID     string
+		Body          []byte
+		Size          int64
+		ExpectedError error
+	}{
+		{
+			Name:      "Valid PNG",
+			ProductID: testProductID,
+			Body:      pngImage(t),
+		},
+		{
+			Name:          "Unsupported type",
+			ProductID:     testProductID,
+			Body:          []byte("%PDF-1.4 not an image"),
+			ExpectedError: models.ErrUnsupportedImage,
+		},
+		{
+			Name:          "Too large",
+			ProductID:     testProductID,
+			Body:          pngImage(t),
+			Size:          constants.MaxImageSize + 1,
+			ExpectedError: models.ErrImageTooLarge,
+		},
+		{
+			Name:          "Product not found",
+			ProductID:     "65f000000000000000000002",
+			Body:          pngImage(t),
+			ExpectedError: models.ErrProductNotFound,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			service, products, dir := initImageService(t)
+
+			size := tc.Size
+			if size == 0 {
+				size = int64(len(tc.Body))
+			}
+			image, err := service.AddProductImage(ctx, tc.ProductID, bytes.NewReader(tc.Body), size)
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.ExpectedError)
+			}
+			if tc.ExpectedError != nil {
+				return
+			}
+
+			if image.ContentType != "image/png" {
+				t.Errorf("unexpected content type: got %v want image/png", image.ContentType)
+			}
+			stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(image.Key)))
+			if err != nil || !bytes.Equal(stored, tc.Body) {
+				t.Errorf("image was not stored: %v", err)
+			}
+			product, _ := products.FindOne(ctx, tc.ProductID)
+			if len(product.Images) != 1 || product.Images[0].URL != image.URL {
+				t.Errorf("unexpected product images: got %v", product.Images)
+			}
+		})
+	}
+}
+
+func TestPurgeTrashRemovesImages(t *testing.T) {
+	ctx := context.Background()
+	service, _, dir := initImageService(t)
+
+	var keys []string
+	for i := 0; i < 2; i++ {
+		image, err := service.AddProductImage(ctx, testProductID, bytes.NewReader(pngImage(t)), int64(len(pngImage(t))))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		keys = append(keys, image.Key)
+	}
+
+	if err := service.DeleteProductImage(ctx, testProductID, "missing"); !errors.Is(err, models.ErrImageNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrImageNotFound)
+	}
+	if err := service.DeleteProduct(ctx, testProductID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Las imágenes se conservan mientras el producto está en la papelera, pero no se les generan miniaturas.
+	if pending, _ := service.repository.FindPendingThumbnails(ctx, 10); len(pending) != 0 {
+		t.Fatalf("unexpected pending thumbnails in the trash: got %v want 0", len(pending))
+	}
+	for _, key := range keys {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key))); err != nil {
+			t.Fatalf("image %s was deleted with the product in the trash: %v", key, err)
+		}
+	}
+	if purged, err := service.PurgeTrash(ctx, 0); err != nil || purged != 1 {
+		t.Fatalf("unexpected purge: got %v (%v) want %v", purged, err, 1)
+	}
+
+	for _, key := range keys {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key))); !os.IsNotExist(err) {
+			t.Errorf("image %s was not deleted: %v", key, err)
+		}
+	}
+}
+
+func TestConfirmImageUpload(t *testing.T) {
+	imageID := "65f0000000000000000000aa"
+
+	tc := []struct {
+		Name          string
+		Key           string
+		Upload        []byte
+		ExpectedError error
+		ExpectDeleted bool
+	}{
+		{
+			Name:   "Valid upload",
+			Key:    "products/" + testProductID + "/" + imageID + ".png",
+			Upload: pngImage(t),
+		},
+		{
+			Name:          "Not uploaded",
+			Key:           "products/" + testProductID + "/" + imageID + ".png",
+			ExpectedError: models.ErrImageNotFound,
+		},
+		{
+			Name:          "Content does not match the extension",
+			Key:           "products/" + testProductID + "/" + imageID + ".jpg",
+			Upload:        pngImage(t),
+			ExpectedError: models.ErrUnsupportedImage,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "Key of another product",
+			Key:           "products/65f000000000000000000002/" + imageID + ".png",
+			Upload:        pngImage(t),
+			ExpectedError: models.ErrInvalidUploadKey,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			service, products, dir := initImageService(t)
+
+			if tc.Upload != nil {
+				if _, err := service.storage.Put(ctx, tc.Key, bytes.NewReader(tc.Upload), "image/png"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			image, err := service.ConfirmImageUpload(ctx, testProductID, tc.Key)
+			if !errors.Is(err, tc.ExpectedError) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.ExpectedError)
+			}
+			if tc.ExpectDeleted {
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(tc.Key))); !os.IsNotExist(err) {
+					t.Errorf("invalid upload was not deleted: %v", err)
+				}
+			}
+			if tc.ExpectedError != nil {
+				return
+			}
+
+			if image.ID != imageID || image.URL != "/images/"+tc.Key {
+				t.Errorf("unexpected image: got %+v", image)
+			}
+			// Confirmar de nuevo no duplica la imagen.
+			if _, err := service.ConfirmImageUpload(ctx, testProductID, tc.Key); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			product, _ := products.FindOne(ctx, testProductID)
+			if len(product.Images) != 1 {
+				t.Errorf("unexpected images: got %v want 1", len(product.Images))
+			}
+		})
+	}
+}
+
+// racingRepository registra cada imagen justo antes que quien la confirma, como si
+// otra confirmación concurrente de la misma subida hubiera ganado.
+type racingRepository struct {
+	*repository.ProductRepositoryMocked
+}
+
+func (r racingRepository) AddImage(ctx context.Context, id string, image models.ProductImage) error {
+	if err := r.ProductRepositoryMocked.AddImage(ctx, id, image); err != nil {
+		return err
+	}
+	return r.ProductRepositoryMocked.AddImage(ctx, id, image)
+}
+
+func TestConfirmImageUploadConcurrent(t *testing.T) {
+	ctx := context.Background()
+	service, products, dir := initImageService(t)
+	service.repository = racingRepository{products}
+
+	imageID := "65f0000000000000000000aa"
+	key := "products/" + testProductID + "/" + imageID + ".png"
+	if _, err := service.storage.Put(ctx, key, bytes.NewReader(pngImage(t)), "image/png"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	image, err := service.ConfirmImageUpload(ctx, testProductID, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.ID != imageID {
+		t.Errorf("unexpected image: got %v want %v", image.ID, imageID)
+	}
+	product, _ := products.FindOne(ctx, testProductID)
+	if len(product.Images) != 1 {
+		t.Errorf("unexpected images: got %v want 1", len(product.Images))
+	}
+	// El objeto pertenece a la imagen ya registrada, no debe eliminarse.
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key))); err != nil {
+		t.Errorf("attached upload was deleted: %v", err)
+	}
+}
+
+func TestCreateImageUploadRequiresPresigner(t *testing.T) {
+	service, _, _ := initImageService(t)
+
+	_, err := service.CreateImageUpload(context.Background(), testProductID, models.ImageUploadRequest{ContentType: "image/png", Size: 10})
+	if !errors.Is(err, models.ErrPresignUnsupported) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrPresignUnsupported)
+	}
+}
+
+func TestGenerateThumbnail(t *testing.T) {
+	ctx := context.Background()
+	service, products, dir := initImageService(t)
+
+	uploaded, err := service.AddProductImage(ctx, testProductID, bytes.NewReader(pngImage(t)), int64(len(pngImage(t))))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Una imagen ilegible se marca como fallida en lugar de reintentarse.
+	corrupt := models.ProductImage{ID: "corrupt", Key: "products/" + testProductID + "/corrupt.png"}
+	service.storage.Put(ctx, corrupt.Key, bytes.NewReader([]byte("\x89PNG broken")), "image/png")
+	products.AddImage(ctx, testProductID, corrupt)
+
+	service.generateThumbnail(ctx, <-service.thumbnails)
+	service.generateThumbnail(ctx, thumbnailJob{productID: testProductID, imageID: corrupt.ID})
+
+	product, _ := products.FindOne(ctx, testProductID)
+	image := findImage(product, uploaded.ID)
+	if image.ThumbnailKey == nil || image.ThumbnailURL != "/images/"+*image.ThumbnailKey {
+		t.Fatalf("unexpected thumbnail: got %v, %v", image.ThumbnailKey, image.ThumbnailURL)
+	}
+	if failed := findImage(product, corrupt.ID); failed.ThumbnailKey == nil || *failed.ThumbnailKey != "" {
+		t.Errorf("corrupt image was not marked as failed: got %v", failed.ThumbnailKey)
+	}
+	if pending, _ := products.FindPendingThumbnails(ctx, 10); len(pending) != 0 {
+		t.Errorf("unexpected pending thumbnails: got %v", len(pending))
+	}
+
+	thumbPath := filepath.Join(dir, filepath.FromSlash(*image.ThumbnailKey))
+	if _, err := os.Stat(thumbPath); err != nil {
+		t.Fatalf("thumbnail was not stored: %v", err)
+	}
+	if err := service.DeleteProductImage(ctx, testProductID, uploaded.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(thumbPath); !os.IsNotExist(err) {
+		t.Errorf("thumbnail was not deleted: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// maxImportLine es el tamaño máximo de una línea de un archivo NDJSON.
+const maxImportLine = 1 << 20
+
+// decodeImportCSV lee un CSV con encabezado cuyas columnas, en cualquier orden, son algunas de
+// models.ProductExportColumns, de modo que un archivo exportado se puede volver a importar; la columna
+// sku es obligatoria.
+func decodeImportCSV(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Las hojas de cálculo suelen anteponer el BOM de UTF-8.
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(models.ProductExportColumns, name) {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("duplicate csv column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.New("the csv header needs a sku column")
+	}
+
+	rows := []importRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("error reading csv: %w", err)
+		}
+
+		row := importRow{line: line, err: err}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return unescapeCSVFormula(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+		row.product.SKU = field("sku")
+		if row.err == nil {
+			row.product, row.err = csvProduct(field)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// csvProduct arma un producto con los campos de una fila del CSV.
+func csvProduct(field func(name string) string) (models.Product, error) {
+	product := models.Product{
+		SKU:         field("sku"),
+		Title:       field("title"),
+		Description: field("description"),
+		Category:    field("category"),
+		Price:       models.Money{Currency: field("price_currency")},
+	}
+
+	var err error
+	if value := field("price_amount"); value != "" {
+		if product.Price.Amount, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return product, errors.New("price_amount must be an integer in minor units")
+		}
+	}
+	if value := field("stock"); value != "" {
+		stock, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return product, errors.New("stock must be a non-negative integer")
+		}
+		product.Stock = uint(stock)
+	}
+	for _, code := range strings.Split(field("barcodes"), "|") {
+		if code = strings.TrimSpace(code); code != "" {
+			product.Barcodes = append(product.Barcodes, code)
+		}
+	}
+	for name, target := range map[string]**uint{"reorder_point": &product.ReorderPoint, "reorder_quantity": &product.ReorderQuantity} {
+		if value := field(name); value != "" {
+			n, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return product, errors.New(name + " must be a non-negative integer")
+			}
+			quantity := uint(n)
+			*target = &quantity
+		}
+	}
+	return product, nil
+}
+
+// decodeImportNDJSON lee un producto JSON por línea, con los mismos campos que POST /products;
+// las líneas vacías se ignoran.
+func decodeImportNDJSON(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxImportLine)
+
+	rows := []importRow{}
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		row := importRow{line: line}
+		if err := json.Unmarshal(data, &row.product); err != nil {
+			row.err = fmt.Errorf("invalid product json: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ndjson: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// importBatchSize es la cantidad de productos que se consultan y escriben en cada operación por lotes.
+const importBatchSize = 500
+
+// importRow es un producto leído del archivo, o el error que impidió leerlo.
+type importRow struct {
+	line    int
+	product models.Product
+	err     error
+}
+
+// ImportProducts crea o actualiza productos según su SKU a partir de un archivo CSV o NDJSON. Cada fila se
+// valida con las mismas reglas que un producto nuevo; las que fallan se informan sin detener la importación.
+// En los productos existentes solo se reemplazan los datos de catálogo: el stock, las ubicaciones y las
+// variantes cambian mediante sus propios endpoints. Con dryRun se valida todo sin escribir.
+func (s *ProductService) ImportProducts(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	rows, err := decodeImport(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the file has no products")
+	}
+
+	report := &models.ImportReport{DryRun: dryRun, Rows: make([]models.ImportRowResult, len(rows))}
+	fail := func(i int, err error) {
+		report.Rows[i].Status = models.ImportFailed
+		report.Rows[i].Error = err.Error()
+	}
+
+	// Valida cada fila por separado; un SKU repetido en el archivo solo vale la primera vez.
+	seen := make(map[string]int, len(rows))
+	pending := make([]int, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		report.Rows[i].Line = row.line
+		report.Rows[i].SKU = row.product.SKU
+		if row.err != nil {
+			fail(i, row.err)
+			continue
+		}
+		err := s.prepareImportRow(ctx, &row.product)
+		report.Rows[i].SKU = row.product.SKU
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		if line, ok := seen[row.product.SKU]; ok {
+			fail(i, fmt.Errorf("sku %s is repeated from line %d", row.product.SKU, line))
+			continue
+		}
+		seen[row.product.SKU] = row.line
+		pending = append(pending, i)
+	}
+
+	existing, err := s.findExistingSKUs(ctx, rows, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := pending[:0]
+	for _, i := range pending {
+		current, ok := existing[rows[i].product.SKU]
+		if !ok {
+			report.Rows[i].Status = models.ImportCreated
+			valid = append(valid, i)
+			continue
+		}
+		if err := checkImportUpdate(current, rows[i].product); err != nil {
+			fail(i, err)
+			continue
+		}
+		report.Rows[i].Status = models.ImportUpdated
+		report.Rows[i].ID = current.ID
+		valid = append(valid, i)
+	}
+
+	if !dryRun {
+		s.writeImport(ctx, rows, valid, existing, report)
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case models.ImportCreated:
+			report.Created++
+		case models.ImportUpdated:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// prepareImportRow aplica a una fila las validaciones de PostProduct y CreateProduct.
+func (s *ProductService) prepareImportRow(ctx context.Context, product *models.Product) error {
+	if err := s.prepareProduct(ctx, product); err != nil {
+		return err
+	}
+	if !product.IsValidCategory() {
+		return errors.New("invalid product category")
+	}
+	return nil
+}
+
+// findExistingSKUs busca por lotes los productos que ya tienen el SKU de alguna de las filas indicadas.
+func (s *ProductService) findExistingSKUs(ctx context.Context, rows []importRow, indexes []int) (map[string]models.Product, error) {
+	existing := make(map[string]models.Product)
+	for start := 0; start < len(indexes); start += importBatchSize {
+		batch := indexes[start:min(start+importBatchSize, len(indexes))]
+		skus := make([]string, len(batch))
+		for j, i := range batch {
+			skus[j] = rows[i].product.SKU
+		}
+
+		products, err := s.repository.FindBySKUs(ctx, skus)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			existing[product.SKU] = product
+		}
+	}
+	return existing, nil
+}
+
+// checkImportUpdate rechaza los cambios que una fila no puede hacer sobre un producto existente.
+func checkImportUpdate(current, product models.Product) error {
+	if current.DeletedAt != nil {
+		return errors.New("the sku belongs to a product in the trash, restore it first")
+	}
+	if len(product.Variants) > 0 {
+		return errors.New("variants of an existing product must be changed through the variant endpoints")
+	}
+	if len(product.Locations) > 0 {
+		return errors.New("locations of an existing product must be changed through the stock endpoints")
+	}
+	if len(current.Variants) > 0 && product.Price.Currency != current.Price.Currency {
+		return errors.New("the currency of a product with variants cannot change")
+	}
+	return nil
+}
+
+// writeImport escribe por lotes las filas válidas y completa su resultado en el reporte. Los cambios
+// se registran en el historial y el cache se invalida una sola vez al final.
+func (s *ProductService) writeImport(ctx context.Context, rows []importRow, indexes []int, existing map[string]models.Product, report *models.ImportReport) {
+	var updated []string
+	written := false
+	for start := 0; start < len(indexes); start += importBatchSize {
+		batch := indexes[start:min(start+importBatchSize, len(indexes))]
+		products := make([]models.Product, len(batch))
+		for j, i := range batch {
+			products[j] = rows[i].product
+		}
+
+		writes, err := s.repository.UpsertBySKU(ctx, products)
+		if err != nil {
+			for _, i := range batch {
+				report.Rows[i].Status = models.ImportFailed
+				report.Rows[i].Error = err.Error()
+			}
+			continue
+		}
+
+		for j, i := range batch {
+			result := &report.Rows[i]
+			write := writes[j]
+			if write.Err != nil {
+				result.Status, result.ID, result.Error = models.ImportFailed, "", write.Err.Error()
+				continue
+			}
+			written = true
+
+			if write.Created {
+				result.Status, result.ID = models.ImportCreated, write.ID
+				s.recordNewProduct(ctx, write.ID, products[j])
+				continue
+			}
+			// Otro proceso pudo crear el SKU después de la consulta; sin el producto previo no hay precio anterior.
+			result.Status = models.ImportUpdated
+			if current, ok := existing[products[j].SKU]; ok {
+				result.ID = current.ID
+				updated = append(updated, current.ID)
+				s.recordPriceChange(ctx, current.ID, "", &current.Price, products[j].Price)
+			}
+		}
+	}
+
+	if written {
+		s.invalidateCache(ctx, updated...)
+	}
+}
+
+// decodeImport lee las filas del archivo en el formato indicado.
+func decodeImport(r io.Reader, format string) ([]importRow, error) {
+	switch format {
+	case models.ProductFormatCSV:
+		return decodeImportCSV(r)
+	case models.ProductFormatNDJSON:
+		return decodeImportNDJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: %q, use csv or ndjson", models.ErrUnsupportedFormat, format)
+	}
+}
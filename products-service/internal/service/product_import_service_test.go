@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+const importCSV = `sku,title,category,price_amount,price_currency,stock,barcodes
+kb-010,Keyboard v2,electronics,5500,USD,3,
+ms-010,Mouse,electronics,1500,usd,7,036000291452
+hd-010,Headphones,toys-and-plants,9900,USD,1,
+ms-010,Mouse again,electronics,1500,USD,1,
+cb-010,Cable,electronics,abc,USD,1,
+`
+
+func TestImportProducts(t *testing.T) {
+	products := repository.NewProductRepositoryMocked()
+	movements := &repository.MovementRepositoryMocked{}
+	prices := &repository.PriceHistoryRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, movements, prices, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	if err := service.CreateProduct(ctx, models.Product{SKU: "KB-010", Title: "Keyboard", Category: "electronics", Price: models.Money{Amount: 5000, Currency: "USD"}, Stock: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// El modo dry_run reporta lo mismo sin escribir.
+	report, err := service.ImportProducts(ctx, strings.NewReader(importCSV), models.ProductFormatCSV, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Failed != 3 {
+		t.Fatalf("unexpected dry run report: got %+v", report)
+	}
+	if _, err := products.FindBySKU(ctx, "MS-010"); !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("expected the dry run not to create products")
+	}
+
+	report, err = service.ImportProducts(ctx, strings.NewReader(importCSV), models.ProductFormatCSV, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := []struct {
+		Name   string
+		Line   int
+		SKU    string
+		Status string
+	}{
+		{Name: "Existing SKU", Line: 2, SKU: "KB-010", Status: models.ImportUpdated},
+		{Name: "New SKU", Line: 3, SKU: "MS-010", Status: models.ImportCreated},
+		{Name: "Invalid category", Line: 4, SKU: "HD-010", Status: models.ImportFailed},
+		{Name: "Repeated SKU", Line: 5, SKU: "MS-010", Status: models.ImportFailed},
+		{Name: "Invalid price", Line: 6, SKU: "cb-010", Status: models.ImportFailed},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			row := report.Rows[i]
+			if row.Line != tc.Line || row.SKU != tc.SKU || row.Status != tc.Status {
+				t.Fatalf("unexpected row: got %+v want %v %v %v", row, tc.Line, tc.SKU, tc.Status)
+			}
+			if (row.Error != "") != (tc.Status == models.ImportFailed) {
+				t.Fatalf("unexpected row error: got %q", row.Error)
+			}
+		})
+	}
+
+	// Los productos existentes conservan su stock y registran el cambio de precio.
+	keyboard, _ := products.FindBySKU(ctx, "KB-010")
+	if keyboard.Title != "Keyboard v2" || keyboard.Stock != 10 || keyboard.Price.Amount != 5500 {
+		t.Fatalf("unexpected updated product: got %+v", keyboard)
+	}
+	history, _ := service.GetPriceHistory(ctx, keyboard.ID, 1, 10)
+	if len(history) != 2 || history[0].Previous == nil || history[0].Previous.Amount != 5000 {
+		t.Fatalf("unexpected price history: got %+v", history)
+	}
+	mouse, _ := products.FindBySKU(ctx, "MS-010")
+	if mouse.Stock != 7 || mouse.Barcodes[0] != "0036000291452" {
+		t.Fatalf("unexpected created product: got %+v", mouse)
+	}
+	if last := movements.Movements[len(movements.Movements)-1]; last.ProductID != mouse.ID || last.Delta != 7 {
+		t.Fatalf("unexpected movement: got %+v", last)
+	}
+
+	ndjson := `{"sku": "TS-010", "title": "T-shirt", "category": "clothing", "price": {"amount": 2000, "currency": "USD"}, "variants": [{"sku": "TS-010-M", "attributes": {"size": "M"}, "stock": 2}]}
+
+{"sku": "TS-011", "title": 5}
+`
+	report, err = service.ImportProducts(ctx, strings.NewReader(ndjson), models.ProductFormatNDJSON, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Created != 1 || report.Failed != 1 || report.Rows[1].Line != 3 {
+		t.Fatalf("unexpected ndjson report: got %+v", report)
+	}
+
+	if _, err := service.ImportProducts(ctx, strings.NewReader("sku,colour\nA,red\n"), models.ProductFormatCSV, false); err == nil {
+		t.Fatalf("expected an unknown column to be refused")
+	}
+	if _, err := service.ImportProducts(ctx, strings.NewReader(""), "xml", false); !errors.Is(err, models.ErrUnsupportedFormat) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrUnsupportedFormat)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+// GetPriceHistory retorna los cambios de precio de un producto y de sus variantes.
+func (s *ProductService) GetPriceHistory(ctx context.Context, id string, page, size int) ([]models.PriceChange, error) {
+	if _, err := s.repository.FindOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.prices.FindByProduct(ctx, id, page, size)
+}
+
+// normalizePrices valida el precio de un producto nuevo y el de sus variantes, que toman la moneda
+// del producto si no indican otra y no pueden usar una distinta.
+func normalizePrices(product *models.Product) error {
+	price, err := product.Price.Normalize()
+	if err != nil {
+		return err
+	}
+	product.Price = price
+
+	for i := range product.Variants {
+		if product.Variants[i].Price, err = variantPrice(product.Variants[i].Price, price.Currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// variantPrice valida el precio de una variante con la moneda de su producto.
+func variantPrice(price models.Money, currency string) (models.Money, error) {
+	if price.Currency == "" {
+		price.Currency = currency
+	}
+	price, err := price.Normalize()
+	if err != nil {
+		return price, err
+	}
+	if price.Currency != currency {
+		return price, errors.New("variant prices must use the product currency " + currency)
+	}
+	return price, nil
+}
+
+// parseMoney lee un precio de una actualización parcial: un objeto con amount, un entero en unidades
+// menores, y opcionalmente currency, que por defecto es la moneda actual.
+func parseMoney(value interface{}, currency string) (models.Money, error) {
+	invalid := errors.New("price must be an object with an integer amount in minor units and a currency")
+
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return models.Money{}, invalid
+	}
+	for field := range raw {
+		if field != "amount" && field != "currency" {
+			return models.Money{}, errors.New("price cannot have field " + field)
+		}
+	}
+
+	amount, ok := raw["amount"].(float64)
+	if !ok || amount != math.Trunc(amount) || math.Abs(amount) > 1<<53 {
+		return models.Money{}, invalid
+	}
+	price := models.Money{Amount: int64(amount), Currency: currency}
+	if value, ok := raw["currency"]; ok {
+		if price.Currency, ok = value.(string); !ok {
+			return models.Money{}, invalid
+		}
+	}
+	return price.Normalize()
+}
+
+// recordPriceChange agrega un cambio al historial de precios. Un error al registrarlo solo se informa
+// en el log, porque el precio ya cambió.
+func (s *ProductService) recordPriceChange(ctx context.Context, id, variantID string, previous *models.Money, price models.Money) {
+	if previous != nil && *previous == price {
+		return
+	}
+
+	change := models.PriceChange{
+		ProductID:     id,
+		VariantID:     variantID,
+		Previous:      previous,
+		Price:         price,
+		Actor:         actor(ctx),
+		CorrelationID: utils.CorrelationIDFromContext(ctx),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// El cambio se guarda aunque el cliente haya cancelado la solicitud.
+	if err := s.prices.Create(context.WithoutCancel(ctx), change); err != nil {
+		log.Printf("failed to record price change for product %s: %v", id, err)
+	}
+}
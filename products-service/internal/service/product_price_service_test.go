@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestProductPrices(t *testing.T) {
+	products := repository.NewProductRepositoryMocked()
+	prices := &repository.PriceHistoryRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, prices, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	err := service.CreateProduct(ctx, models.Product{SKU: "HP-001", Title: "Headphones", Category: "electronics", Price: models.Money{Amount: 129900, Currency: " cop "}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product, _ := service.GetProductBySKU(ctx, "HP-001")
+	if product.Price != (models.Money{Amount: 129900, Currency: "COP"}) {
+		t.Fatalf("unexpected price: got %+v", product.Price)
+	}
+
+	tc := []struct {
+		Name  string
+		Price interface{}
+		Valid bool
+	}{
+		{Name: "Same currency", Price: map[string]interface{}{"amount": 99900.0}, Valid: true},
+		{Name: "Other currency", Price: map[string]interface{}{"amount": 2999.0, "currency": "usd"}, Valid: true},
+		{Name: "Plain number", Price: 2999.0},
+		{Name: "Decimal amount", Price: map[string]interface{}{"amount": 29.99, "currency": "USD"}},
+		{Name: "Negative amount", Price: map[string]interface{}{"amount": -1.0}},
+		{Name: "Unknown currency", Price: map[string]interface{}{"amount": 100.0, "currency": "XYZ"}},
+		{Name: "Unknown field", Price: map[string]interface{}{"amount": 100.0, "decimals": 2.0}},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			err := service.UpdateProduct(ctx, product.ID, map[string]interface{}{"price": tc.Price})
+			if (err == nil) != tc.Valid {
+				t.Fatalf("unexpected error: got %v want valid %v", err, tc.Valid)
+			}
+		})
+	}
+
+	history, err := service.GetPriceHistory(ctx, product.ID, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("unexpected history length: got %v want %v", len(history), 3)
+	}
+	latest := history[0]
+	if latest.Previous == nil || latest.Previous.Amount != 99900 || latest.Price != (models.Money{Amount: 2999, Currency: "USD"}) {
+		t.Fatalf("unexpected price change: got %+v", latest)
+	}
+	if latest.Actor != models.MovementSystemActor {
+		t.Fatalf("unexpected actor: got %v want %v", latest.Actor, models.MovementSystemActor)
+	}
+	if history[2].Previous != nil {
+		t.Fatalf("expected the initial price to have no previous price")
+	}
+
+	// Las variantes comparten la moneda del producto.
+	_, err = service.AddVariant(ctx, product.ID, models.Variant{SKU: "HP-001-BLK", Attributes: map[string]string{"color": "black"}, Price: models.Money{Amount: 129900, Currency: "COP"}})
+	if err == nil {
+		t.Fatalf("expected a variant in another currency to be refused")
+	}
+}
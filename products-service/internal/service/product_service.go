@@ -2,108 +2,330 @@ package service
 
 import (
 	"context"
-	"strconv"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+	"golang.org/x/sync/singleflight"
 )
 
 type ProductService struct {
-	repository interfaces.ProductMongoRepositoryInterface
-	cache      interfaces.ProductRedisRepositoryInterface
+	repository   interfaces.ProductMongoRepositoryInterface
+	cache        interfaces.ProductRedisRepositoryInterface
+	reservations interfaces.ReservationRepositoryInterface
+	movements    interfaces.MovementRepositoryInterface
+	prices       interfaces.PriceHistoryRepositoryInterface
+	reviews      interfaces.ReviewRepositoryInterface
+	warehouses   interfaces.WarehouseRepositoryInterface
+	notifier     interfaces.AlertNotifierInterface
+	storage      interfaces.ImageStorageInterface
+
+	cacheOptions CacheOptions
+	flight       singleflight.Group
+	refreshing   sync.Map
+	metrics      map[string]*cacheMetrics
+	thumbnails   chan thumbnailJob
 }
 
-func NewProductService(repository interfaces.ProductMongoRepositoryInterface, cache interfaces.ProductRedisRepositoryInterface) *ProductService {
-	return &ProductService{repository: repository, cache: cache}
+func NewProductService(repository interfaces.ProductMongoRepositoryInterface, cache interfaces.ProductRedisRepositoryInterface, reservations interfaces.ReservationRepositoryInterface, movements interfaces.MovementRepositoryInterface, prices interfaces.PriceHistoryRepositoryInterface, reviews interfaces.ReviewRepositoryInterface, warehouses interfaces.WarehouseRepositoryInterface, notifier interfaces.AlertNotifierInterface, storage interfaces.ImageStorageInterface, cacheOptions CacheOptions) *ProductService {
+	return &ProductService{
+		repository:   repository,
+		cache:        cache,
+		reservations: reservations,
+		movements:    movements,
+		prices:       prices,
+		reviews:      reviews,
+		warehouses:   warehouses,
+		notifier:     notifier,
+		storage:      storage,
+		cacheOptions: cacheOptions,
+		metrics:      newCacheMetrics(),
+		thumbnails:   make(chan thumbnailJob, thumbnailQueueSize),
+	}
 }
 
-func (s *ProductService) GetAllProducts(ctx context.Context, page, size int) ([]models.Product, error) {
-	pageStr := strconv.Itoa(page)
+func (s *ProductService) GetAllProducts(ctx context.Context, filter models.ProductFilter, page models.PageRequest) (*models.ProductPage, error) {
+	// La clave incluye la paginación y todos los criterios para no mezclar listados distintos.
+	key := page.CacheKey() + ":" + filter.CacheKey()
 
-	// Intenta obtener los productos del caché de Redis.
-	cacheProducts, err := s.cache.GetAll(ctx, "products_all_page_"+pageStr)
+	// La versión se lee antes de consultar la base de datos: si una escritura la incrementa
+	// mientras tanto, la página se guarda bajo la versión anterior y nunca se vuelve a leer.
+	version, err := s.cache.ListVersion(ctx)
 	if err != nil {
-		return []models.Product{}, err
+		// Sin la versión no se puede usar el cache: se consulta la base de datos, agrupando las cargas.
+		s.metrics[CacheFamilyList].errors.Add(1)
+		logCacheError("read", err)
+		result, err, _ := s.flight.Do(CacheFamilyList+":uncached:"+key, func() (interface{}, error) {
+			return s.findProductPage(context.WithoutCancel(ctx), filter, page)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.(*models.ProductPage), nil
 	}
 
-	// Si hay productos en caché, se retornan.
-	if len(cacheProducts) > 0 {
-		return cacheProducts, nil
-	}
+	return cached(ctx, s, cacheLoader[models.ProductPage]{
+		family: CacheFamilyList,
+		key:    fmt.Sprintf("%s:v%d:%s", CacheFamilyList, version, key),
+		ttl:    s.cacheOptions.ListTTL,
+		get: func(ctx context.Context) (*models.ProductPage, time.Time, error) {
+			return s.cache.GetPage(ctx, version, key)
+		},
+		version: func(ctx context.Context) (int64, error) {
+			return version, nil
+		},
+		load: func(ctx context.Context) (*models.ProductPage, error) {
+			return s.findProductPage(ctx, filter, page)
+		},
+		set: func(ctx context.Context, version int64, result *models.ProductPage, ttl time.Duration) error {
+			return s.cache.SetPage(ctx, version, key, result, ttl)
+		},
+	})
+}
 
-	// Si no hay productos en caché, búscalos en la base de datos.
-	products, err := s.repository.FindAll(ctx, page, size)
+// findProductPage consulta una página del listado en la base de datos.
+func (s *ProductService) findProductPage(ctx context.Context, filter models.ProductFilter, page models.PageRequest) (*models.ProductPage, error) {
+	products, total, err := s.repository.FindAll(ctx, filter, page)
 	if err != nil {
-		return []models.Product{}, err
+		return nil, err
 	}
 
-	// Guarda los productos en Redis y maneja el error si lo hay.
-	if err := s.cache.Set(ctx, "products_all_page_"+pageStr, products); err != nil {
-		return []models.Product{}, err
+	result := &models.ProductPage{Items: products, Total: total, Size: page.Size}
+	if page.CursorMode {
+		// El repositorio pide un producto de más para saber si existe otra página.
+		if len(products) > page.Size {
+			result.Items = products[:page.Size]
+			result.NextCursor = result.Items[page.Size-1].ID
+		}
+	} else {
+		result.Page = page.Page
 	}
 
-	return products, nil
+	return result, nil
 }
 
 func String(page int) {
 	panic("unimplemented")
 }
 func (s *ProductService) GetOneProduct(ctx context.Context, id string) (*models.Product, error) {
-	// Intenta obtener el producto del caché de Redis.
-	cacheProduct, err := s.cache.GetOne(ctx, "product_"+id)
-	if err != nil {
-		return nil, err
+	return cached(ctx, s, cacheLoader[models.Product]{
+		family: CacheFamilyProduct,
+		key:    CacheFamilyProduct + ":" + id,
+		ttl:    s.cacheOptions.ProductTTL,
+		get: func(ctx context.Context) (*models.Product, time.Time, error) {
+			return s.cache.GetOne(ctx, id)
+		},
+		// La clave del producto no incluye la versión: el cache la compara al guardar.
+		version: s.cache.ListVersion,
+		load: func(ctx context.Context) (*models.Product, error) {
+			return s.repository.FindOne(ctx, id)
+		},
+		set: func(ctx context.Context, version int64, product *models.Product, ttl time.Duration) error {
+			return s.cache.SetOne(ctx, version, id, product, ttl)
+		},
+	})
+}
+
+func (s *ProductService) CreateProduct(ctx context.Context, product models.Product) error {
+	if err := s.prepareProduct(ctx, &product); err != nil {
+		return err
 	}
-	// Si exite el producto en caché, se retorna.
-	if cacheProduct != nil {
-		return cacheProduct, nil
+
+	id, err := s.repository.Create(ctx, product)
+	if err != nil {
+		return err
 	}
 
-	// Si no esta el producto en caché, se busca en la base de datos.
-	product, err := s.repository.FindOne(ctx, id)
+	// Un producto nuevo solo cambia los listados.
+	s.invalidateCache(ctx)
+
+	s.recordNewProduct(ctx, id, product)
+	return nil
+}
+
+// prepareProduct valida y normaliza un producto nuevo antes de guardarlo.
+func (s *ProductService) prepareProduct(ctx context.Context, product *models.Product) error {
+	// El ID lo asigna MongoDB, un producto nuevo no puede nacer con unidades reservadas,
+	// las imágenes solo se agregan subiéndolas, la calificación sale de las reseñas y nace fuera de la papelera.
+	product.ID = ""
+	product.Reserved = 0
+	product.Images = nil
+	product.Rating = models.RatingSummary{}
+	product.DeletedAt = nil
+	// Los productos referencian la categoría por su slug.
+	product.Category = strings.ToLower(strings.TrimSpace(product.Category))
+
+	sku, err := utils.NormalizeSKU(product.SKU)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	product.SKU = sku
+	if product.Barcodes, err = normalizeBarcodes(product.Barcodes); err != nil {
+		return err
 	}
 
-	// Guarda el producto en Redis y maneja el error si lo hay.
-	if err := s.cache.Set(ctx, "product_"+id, product); err != nil {
-		return nil, err
+	if err := s.checkLocations(ctx, *product); err != nil {
+		return err
+	}
+	if err := prepareVariants(product); err != nil {
+		return err
+	}
+	return normalizePrices(product)
+}
+
+// recordNewProduct registra el precio y el stock iniciales de un producto recién creado.
+func (s *ProductService) recordNewProduct(ctx context.Context, id string, product models.Product) {
+	// Los precios iniciales abren el historial de precios.
+	s.recordPriceChange(ctx, id, "", nil, product.Price)
+	for _, variant := range product.Variants {
+		s.recordPriceChange(ctx, id, variant.ID, nil, variant.Price)
+	}
+
+	// El stock inicial se registra por variante o, sin variantes, por almacén y el remanente como stock sin asignar.
+	for _, variant := range product.Variants {
+		if variant.Stock > 0 {
+			s.recordMovement(ctx, id, "", variant.ID, int64(variant.Stock), 0, models.MovementInitialStock)
+		}
+	}
+	if len(product.Variants) > 0 {
+		return
 	}
 
-	return product, nil
+	unassigned := int64(product.Stock)
+	for _, location := range product.Locations {
+		if location.Quantity > 0 {
+			s.recordMovement(ctx, id, location.WarehouseID, "", int64(location.Quantity), 0, models.MovementInitialStock)
+			unassigned -= int64(location.Quantity)
+		}
+	}
+	if unassigned > 0 {
+		s.recordMovement(ctx, id, "", "", unassigned, 0, models.MovementInitialStock)
+	}
 }
-func (s *ProductService) CreateProduct(ctx context.Context, product models.Product) error {
-	// Limpia el cache existente y maneja el error si lo hay.
-	if err := s.cache.Clean(ctx); err != nil {
-		return err
+
+// checkLocations valida que las ubicaciones iniciales existan, no se repitan y no superen el stock total.
+func (s *ProductService) checkLocations(ctx context.Context, product models.Product) error {
+	seen := make(map[string]bool, len(product.Locations))
+	var total uint
+	for _, location := range product.Locations {
+		if location.WarehouseID == "" {
+			return errors.New("location warehouse_id is required")
+		}
+		if seen[location.WarehouseID] {
+			return errors.New("duplicate location for warehouse " + location.WarehouseID)
+		}
+		seen[location.WarehouseID] = true
+
+		if err := s.checkWarehouse(ctx, location.WarehouseID); err != nil {
+			return err
+		}
+		total += location.Quantity
 	}
 
-	return s.repository.Create(ctx, product)
+	if total > product.Stock {
+		return errors.New("locations exceed the product stock")
+	}
+	return nil
 }
 
 func (s *ProductService) DeleteProduct(ctx context.Context, id string) error {
-	// Elimina el producto en la base de datos y maneja el error si lo hay.
+	// Envía el producto a la papelera; sus imágenes se eliminan al purgarlo.
 	if err := s.repository.Delete(ctx, id); err != nil {
 		return err
 	}
 
-	// Limpia el cache existente y maneja el error si lo hay.
-	if err := s.cache.Clean(ctx); err != nil {
-		return err
-	}
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
 
 	return nil
 }
 
 func (s *ProductService) UpdateProduct(ctx context.Context, id string, product map[string]interface{}) error {
+	if err := validateProductUpdate(product); err != nil {
+		return err
+	}
+
+	// Un cambio de precio se valida contra el actual, que además queda en el historial.
+	var previous *models.Product
+	if value, ok := product["price"]; ok {
+		current, err := s.repository.FindOne(ctx, id)
+		if err != nil {
+			return err
+		}
+		price, err := parseMoney(value, current.Price.Currency)
+		if err != nil {
+			return err
+		}
+		if len(current.Variants) > 0 && price.Currency != current.Price.Currency {
+			return errors.New("the currency of a product with variants cannot change")
+		}
+		product["price"] = price
+		previous = current
+	}
+
 	if err := s.repository.Update(ctx, id, product); err != nil {
 		return err
 	}
 
-	// Limpia el cache existente y maneja el error si lo hay.
-	if err := s.cache.Clean(ctx); err != nil {
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	if previous != nil {
+		s.recordPriceChange(ctx, id, "", &previous.Price, product["price"].(models.Money))
+	}
+
+	return nil
+}
+
+// validateProductUpdate rechaza los campos que no se modifican directamente y normaliza el resto,
+// salvo el precio, que depende del producto.
+func validateProductUpdate(product map[string]interface{}) error {
+	// El stock solo cambia mediante las operaciones atómicas de inventario.
+	for _, field := range []string{"stock", "reserved", "locations"} {
+		if _, ok := product[field]; ok {
+			return errors.New(field + " must be changed through the stock endpoints")
+		}
+	}
+	if _, ok := product["images"]; ok {
+		return errors.New("images must be changed through the image endpoints")
+	}
+	if _, ok := product["variants"]; ok {
+		return errors.New("variants must be changed through the variant endpoints")
+	}
+	for _, field := range []string{"rating", "rating_summary"} {
+		if _, ok := product[field]; ok {
+			return errors.New(field + " is computed from the product reviews")
+		}
+	}
+	if _, ok := product["deleted_at"]; ok {
+		return errors.New("deleted_at must be changed through the delete and restore endpoints")
+	}
+
+	if value, ok := product["category"]; ok {
+		category, isString := value.(string)
+		if !isString || !utils.IsValidCategory(category) {
+			return errors.New("invalid product category")
+		}
+		product["category"] = strings.ToLower(strings.TrimSpace(category))
+	}
+	if err := normalizeIdentifierFields(product); err != nil {
 		return err
 	}
 
+	// Las reglas de reposición son cantidades de unidades: enteros no negativos.
+	for _, field := range []string{"reorder_point", "reorder_quantity"} {
+		if value, ok := product[field]; ok {
+			n, isNumber := value.(float64)
+			if !isNumber || n < 0 || n != math.Trunc(n) {
+				return errors.New(field + " must be a non-negative integer")
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+const testProductID = "65f000000000000000000001"
+
+func initProductService(t *testing.T, cache *repository.ProductCacheMocked) (*ProductService, *repository.ProductRepositoryMocked) {
+	t.Helper()
+
+	products := repository.NewProductRepositoryMocked(models.Product{
+		ID:       testProductID,
+		Title:    "Keyboard",
+		Category: "electronics",
+		Price:    models.Money{Amount: 10000, Currency: "USD"},
+		Stock:    50,
+	})
+	service := NewProductService(products, cache, nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+
+	return service, products
+}
+
+// gatedRepository simula lecturas lentas: FindOne y FindAll leen el producto y, antes de retornarlo,
+// avisan en started y esperan a que se cierre gate.
+type gatedRepository struct {
+	*repository.ProductRepositoryMocked
+	loads   atomic.Int32
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func newGatedRepository(products *repository.ProductRepositoryMocked) *gatedRepository {
+	return &gatedRepository{ProductRepositoryMocked: products, started: make(chan struct{}, 16), gate: make(chan struct{})}
+}
+
+func (r *gatedRepository) wait() {
+	r.loads.Add(1)
+	r.started <- struct{}{}
+	<-r.gate
+}
+
+func (r *gatedRepository) FindOne(ctx context.Context, id string) (*models.Product, error) {
+	product, err := r.ProductRepositoryMocked.FindOne(ctx, id)
+	r.wait()
+	return product, err
+}
+
+func (r *gatedRepository) FindAll(ctx context.Context, filter models.ProductFilter, page models.PageRequest) ([]models.Product, int64, error) {
+	products, total, err := r.ProductRepositoryMocked.FindAll(ctx, filter, page)
+	r.wait()
+	return products, total, err
+}
+
+func TestProductServiceWithoutCache(t *testing.T) {
+	tc := []struct {
+		Name string
+		Run  func(ctx context.Context, s *ProductService) error
+	}{
+		{
+			Name: "Get one product",
+			Run: func(ctx context.Context, s *ProductService) error {
+				_, err := s.GetOneProduct(ctx, testProductID)
+				return err
+			},
+		},
+		{
+			Name: "Get all products",
+			Run: func(ctx context.Context, s *ProductService) error {
+				_, err := s.GetAllProducts(ctx, models.ProductFilter{}, models.PageRequest{Page: 1, Size: 10})
+				return err
+			},
+		},
+		{
+			Name: "Create product",
+			Run: func(ctx context.Context, s *ProductService) error {
+				return s.CreateProduct(ctx, models.Product{SKU: "MS-001", Title: "Mouse", Category: "electronics", Price: models.Money{Amount: 2500, Currency: "USD"}, Stock: 5})
+			},
+		},
+		{
+			Name: "Update product",
+			Run: func(ctx context.Context, s *ProductService) error {
+				return s.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"})
+			},
+		},
+		{
+			Name: "Delete product",
+			Run: func(ctx context.Context, s *ProductService) error {
+				return s.DeleteProduct(ctx, testProductID)
+			},
+		},
+		{
+			Name: "Increment stock",
+			Run: func(ctx context.Context, s *ProductService) error {
+				_, err := s.IncrementStock(ctx, testProductID, "", "", 5)
+				return err
+			},
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			cache := repository.NewProductCacheMocked()
+			cache.SetFailing(true)
+			service, _ := initProductService(t, cache)
+
+			if err := tc.Run(context.Background(), service); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cache.CallCount() == 0 {
+				t.Errorf("expected the cache to be called")
+			}
+		})
+	}
+}
+
+func TestProductServiceReadsFromMongoWhenCacheFails(t *testing.T) {
+	cache := repository.NewProductCacheMocked()
+	service, _ := initProductService(t, cache)
+	ctx := context.Background()
+
+	cache.SetFailing(true)
+	product, err := service.GetOneProduct(ctx, testProductID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Title != "Keyboard" {
+		t.Errorf("unexpected product: got %v want %v", product.Title, "Keyboard")
+	}
+
+	stats := service.CacheStatus().Families[CacheFamilyProduct]
+	if stats.Errors == 0 || stats.Misses != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProductServiceWritesSurviveOpenBreaker(t *testing.T) {
+	cache := repository.NewProductCacheMocked()
+	breaker := repository.NewProductCacheBreaker(cache, repository.BreakerOptions{FailureThreshold: 2, OpenTimeout: time.Hour})
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Stock: 50})
+	service := NewProductService(products, breaker, nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	// Se cachea el producto y luego Redis cae.
+	if _, err := service.GetOneProduct(ctx, testProductID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cache.SetFailing(true)
+
+	for i := 0; i < 3; i++ {
+		if err := service.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if state := service.CacheStatus().State; state != models.CacheStateOpen {
+		t.Fatalf("unexpected state: got %v want %v", state, models.CacheStateOpen)
+	}
+
+	// Con el circuito abierto el cache ya no se llama, pero las lecturas siguen funcionando.
+	calls := cache.CallCount()
+	product, err := service.GetOneProduct(ctx, testProductID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Title != "Mechanical keyboard" {
+		t.Errorf("unexpected product: got %v want %v", product.Title, "Mechanical keyboard")
+	}
+	if cache.CallCount() != calls {
+		t.Errorf("unexpected cache calls: got %v want %v", cache.CallCount(), calls)
+	}
+}
+
+func TestGetAllProductsCursor(t *testing.T) {
+	products := repository.NewProductRepositoryMocked()
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	for _, sku := range []string{"P-1", "P-2", "P-3", "P-4", "P-5"} {
+		if err := service.CreateProduct(ctx, models.Product{SKU: sku, Title: sku, Category: "electronics", Price: models.Money{Amount: 100, Currency: "USD"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tc := []struct {
+		Name    string
+		Titles  []string
+		HasNext bool
+	}{
+		{Name: "First page", Titles: []string{"P-1", "P-2"}, HasNext: true},
+		{Name: "Second page", Titles: []string{"P-3", "P-4"}, HasNext: true},
+		{Name: "Last page", Titles: []string{"P-5"}},
+	}
+
+	after := ""
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			page, err := service.GetAllProducts(ctx, models.ProductFilter{}, models.PageRequest{Size: 2, CursorMode: true, After: after})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			titles := []string{}
+			for _, product := range page.Items {
+				titles = append(titles, product.Title)
+			}
+			if !reflect.DeepEqual(titles, tc.Titles) {
+				t.Fatalf("unexpected items: got %v want %v", titles, tc.Titles)
+			}
+			if (page.NextCursor != "") != tc.HasNext {
+				t.Fatalf("unexpected next cursor: got %q", page.NextCursor)
+			}
+			if tc.HasNext && page.NextCursor != page.Items[len(page.Items)-1].ID {
+				t.Fatalf("unexpected next cursor: got %v want %v", page.NextCursor, page.Items[len(page.Items)-1].ID)
+			}
+			if page.Total != 5 || page.Page != 0 {
+				t.Fatalf("unexpected page: got total %v page %v", page.Total, page.Page)
+			}
+			after = page.NextCursor
+		})
+	}
+}
+
+func TestProductFilterCacheKey(t *testing.T) {
+	minPrice, otherMinPrice := uint(1000), uint(2000)
+
+	tc := []struct {
+		Name  string
+		A, B  models.ProductFilter
+		Equal bool
+	}{
+		{Name: "Query case is ignored", A: models.ProductFilter{Query: "Keyboard"}, B: models.ProductFilter{Query: "keyboard"}, Equal: true},
+		{Name: "Attribute order is ignored", A: models.ProductFilter{Attributes: map[string]string{"size": "M", "color": "blue"}}, B: models.ProductFilter{Attributes: map[string]string{"color": "blue", "size": "M"}}, Equal: true},
+		{Name: "Different sort", A: models.ProductFilter{Sort: "price"}, B: models.ProductFilter{Sort: "-price"}},
+		{Name: "Different price", A: models.ProductFilter{MinPrice: &minPrice}, B: models.ProductFilter{MinPrice: &otherMinPrice}},
+		{Name: "Stock filter", A: models.ProductFilter{InStock: true}, B: models.ProductFilter{}},
+		{Name: "Quoted query", A: models.ProductFilter{Query: `a";category=books`}, B: models.ProductFilter{Query: "a", Category: "books"}},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if equal := tc.A.CacheKey() == tc.B.CacheKey(); equal != tc.Equal {
+				t.Fatalf("unexpected keys: got %q and %q", tc.A.CacheKey(), tc.B.CacheKey())
+			}
+		})
+	}
+
+	// Cada combinación de filtro y página se cachea por separado.
+	cache := repository.NewProductCacheMocked()
+	service, _ := initProductService(t, cache)
+	ctx := context.Background()
+	for _, filter := range []models.ProductFilter{{}, {Category: "electronics"}, {Category: "books"}} {
+		for _, page := range []models.PageRequest{{Page: 1, Size: 10}, {Page: 2, Size: 10}, {Size: 10, CursorMode: true}} {
+			if _, err := service.GetAllProducts(ctx, filter, page); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if stats := service.CacheStatus().Families[CacheFamilyList]; stats.Misses != 9 || stats.Hits != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if _, err := service.GetAllProducts(ctx, models.ProductFilter{Category: "books"}, models.PageRequest{Page: 2, Size: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats := service.CacheStatus().Families[CacheFamilyList]; stats.Hits != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestListCacheInvalidation(t *testing.T) {
+	tc := []struct {
+		Name  string
+		Write func(ctx context.Context, s *ProductService) error
+		Check func(page *models.ProductPage) bool
+	}{
+		{
+			Name: "Create product",
+			Write: func(ctx context.Context, s *ProductService) error {
+				return s.CreateProduct(ctx, models.Product{SKU: "MS-001", Title: "Mouse", Category: "electronics", Price: models.Money{Amount: 2500, Currency: "USD"}})
+			},
+			Check: func(page *models.ProductPage) bool { return page.Total == 2 },
+		},
+		{
+			Name: "Update product",
+			Write: func(ctx context.Context, s *ProductService) error {
+				return s.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"})
+			},
+			Check: func(page *models.ProductPage) bool { return page.Items[0].Title == "Mechanical keyboard" },
+		},
+		{
+			Name: "Delete product",
+			Write: func(ctx context.Context, s *ProductService) error {
+				return s.DeleteProduct(ctx, testProductID)
+			},
+			Check: func(page *models.ProductPage) bool { return page.Total == 0 },
+		},
+		{
+			Name: "Stock change",
+			Write: func(ctx context.Context, s *ProductService) error {
+				_, err := s.DecrementStock(ctx, testProductID, "", "", 5)
+				return err
+			},
+			Check: func(page *models.ProductPage) bool { return page.Items[0].Stock == 45 },
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			cache := repository.NewProductCacheMocked()
+			service, _ := initProductService(t, cache)
+			ctx := context.Background()
+			all := func() *models.ProductPage {
+				page, err := service.GetAllProducts(ctx, models.ProductFilter{}, models.PageRequest{Page: 1, Size: 10})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return page
+			}
+
+			all()
+			all()
+			version, _ := cache.ListVersion(ctx)
+
+			if err := tc.Write(ctx, service); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if current, _ := cache.ListVersion(ctx); current <= version {
+				t.Fatalf("unexpected list version: got %v want more than %v", current, version)
+			}
+
+			// La página cacheada antes de la escritura queda bajo la versión anterior y no se vuelve a servir.
+			if page := all(); !tc.Check(page) {
+				t.Fatalf("unexpected page after the write: %+v", page)
+			}
+			if stats := service.CacheStatus().Families[CacheFamilyList]; stats.Hits != 1 || stats.Misses != 2 {
+				t.Fatalf("unexpected stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestListLoadedBeforeWriteIsNotServed(t *testing.T) {
+	products := newGatedRepository(repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 50}))
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+	page := models.PageRequest{Page: 1, Size: 10}
+
+	// Una lectura consulta la base de datos antes de la escritura y guarda su resultado después.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := service.GetAllProducts(ctx, models.ProductFilter{}, page); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	<-products.started
+	if err := service.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(products.gate)
+	<-done
+
+	result, err := service.GetAllProducts(ctx, models.ProductFilter{}, page)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title := result.Items[0].Title; title != "Mechanical keyboard" {
+		t.Fatalf("unexpected title: got %v want %v", title, "Mechanical keyboard")
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+const (
+	// DefaultReservationTTL es la vigencia de una reserva cuando la solicitud no indica una.
+	DefaultReservationTTL = 15 * time.Minute
+	// MaxReservationTTL es la vigencia máxima permitida para una reserva.
+	MaxReservationTTL = 24 * time.Hour
+)
+
+// IncrementStock suma unidades al stock disponible del producto en el almacén indicado,
+// o al stock sin asignar si warehouseID está vacío. En los productos con variantes se indica variantID.
+func (s *ProductService) IncrementStock(ctx context.Context, id, warehouseID, variantID string, quantity uint) (*models.Product, error) {
+	if err := s.checkStockTarget(ctx, warehouseID, variantID); err != nil {
+		return nil, err
+	}
+	return s.applyStockChange(ctx, id, warehouseID, variantID, int64(quantity), 0, models.MovementIncrement)
+}
+
+// DecrementStock resta unidades del stock disponible; falla con models.ErrInsufficientStock si no alcanzan.
+func (s *ProductService) DecrementStock(ctx context.Context, id, warehouseID, variantID string, quantity uint) (*models.Product, error) {
+	if err := s.checkStockTarget(ctx, warehouseID, variantID); err != nil {
+		return nil, err
+	}
+	return s.applyStockChange(ctx, id, warehouseID, variantID, -int64(quantity), 0, models.MovementDecrement)
+}
+
+// TransferStock mueve unidades disponibles de un almacén a otro sin cambiar el stock total.
+// Un almacén vacío representa el stock sin asignar.
+func (s *ProductService) TransferStock(ctx context.Context, id string, transfer models.StockTransfer) (*models.Product, error) {
+	if transfer.FromWarehouseID == transfer.ToWarehouseID {
+		return nil, errors.New("source and destination warehouses must be different")
+	}
+	for _, warehouseID := range []string{transfer.FromWarehouseID, transfer.ToWarehouseID} {
+		if err := s.checkWarehouse(ctx, warehouseID); err != nil {
+			return nil, err
+		}
+	}
+
+	quantity := int64(transfer.Quantity)
+	product, err := s.repository.TransferStock(ctx, id, transfer.FromWarehouseID, transfer.ToWarehouseID, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordMovement(ctx, id, transfer.FromWarehouseID, "", -quantity, 0, models.MovementTransferOut)
+	s.recordMovement(ctx, id, transfer.ToWarehouseID, "", quantity, 0, models.MovementTransferIn)
+
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	return product, nil
+}
+
+// ReserveStock aparta unidades del stock disponible durante ttl. Las unidades pasan de disponibles
+// a reservadas y vuelven a estar disponibles si la reserva se libera o expira.
+func (s *ProductService) ReserveStock(ctx context.Context, id, warehouseID, variantID string, quantity uint, ttl time.Duration) (*models.Reservation, *models.Product, error) {
+	if err := s.checkStockTarget(ctx, warehouseID, variantID); err != nil {
+		return nil, nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultReservationTTL
+	}
+	if ttl > MaxReservationTTL {
+		ttl = MaxReservationTTL
+	}
+
+	product, err := s.applyStockChange(ctx, id, warehouseID, variantID, -int64(quantity), int64(quantity), models.MovementReservation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	reservation, err := s.reservations.Create(ctx, models.Reservation{
+		ProductID:   id,
+		WarehouseID: warehouseID,
+		VariantID:   variantID,
+		Quantity:    quantity,
+		Status:      models.ReservationActive,
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		// Sin reserva registrada nadie liberaría las unidades: se devuelven al stock disponible.
+		if _, undoErr := s.applyStockChange(ctx, id, warehouseID, variantID, int64(quantity), -int64(quantity), models.MovementReservationRelease); undoErr != nil {
+			log.Printf("failed to undo reservation stock for product %s: %v", id, undoErr)
+		}
+		return nil, nil, err
+	}
+
+	return reservation, product, nil
+}
+
+// CommitReservation confirma la reserva: las unidades reservadas salen definitivamente del inventario.
+// Una reserva vencida falla con models.ErrReservationClosed.
+func (s *ProductService) CommitReservation(ctx context.Context, id, reservationID string) (*models.Product, error) {
+	return s.closeReservation(ctx, id, reservationID, models.ReservationCommitted, false, models.MovementReservationCommit)
+}
+
+// ReleaseReservation cancela la reserva y devuelve sus unidades al stock disponible.
+func (s *ProductService) ReleaseReservation(ctx context.Context, id, reservationID string) (*models.Product, error) {
+	return s.closeReservation(ctx, id, reservationID, models.ReservationReleased, true, models.MovementReservationRelease)
+}
+
+// closeReservation pasa la reserva activa del producto al estado to y saca sus unidades de las reservadas,
+// devolviéndolas al stock disponible si restock es verdadero. Si el stock no se puede actualizar, la reserva
+// vuelve a quedar activa para que sus unidades no queden reservadas para siempre.
+func (s *ProductService) closeReservation(ctx context.Context, id, reservationID, to string, restock bool, reason string) (*models.Product, error) {
+	reservation, err := s.reservations.Transition(ctx, id, reservationID, models.ReservationActive, to)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := int64(reservation.Quantity)
+	var stockDelta int64
+	if restock {
+		stockDelta = quantity
+	}
+	product, err := s.applyStockChange(ctx, id, reservation.WarehouseID, reservation.VariantID, stockDelta, -quantity, reason)
+	if err != nil {
+		if _, undoErr := s.reservations.Transition(ctx, id, reservationID, to, models.ReservationActive); undoErr != nil {
+			log.Printf("failed to reopen reservation %s: %v", reservationID, undoErr)
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// ReleaseExpiredReservations devuelve al stock disponible las unidades de las reservas vencidas.
+func (s *ProductService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	reservations, err := s.reservations.FindExpired(ctx, time.Now().UTC(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, r := range reservations {
+		// La transición es condicional, así que una reserva confirmada en paralelo no se libera.
+		_, err := s.closeReservation(ctx, r.ProductID, r.ID, models.ReservationExpired, true, models.MovementReservationExpired)
+		if err != nil {
+			if !errors.Is(err, models.ErrReservationClosed) {
+				log.Printf("failed to release expired reservation %s: %v", r.ID, err)
+			}
+			continue
+		}
+		released++
+	}
+
+	return released, nil
+}
+
+// RunReservationSweeper libera periódicamente las reservas expiradas hasta que ctx se cancele.
+func (s *ProductService) RunReservationSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ReleaseExpiredReservations(ctx); err != nil {
+				log.Printf("reservation sweeper: %v", err)
+			}
+		}
+	}
+}
+
+// GetMovements retorna el historial de movimientos de inventario de un producto.
+func (s *ProductService) GetMovements(ctx context.Context, id string, filter models.MovementFilter, page, size int) ([]models.Movement, error) {
+	if _, err := s.repository.FindOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.movements.FindByProduct(ctx, id, filter, page, size)
+}
+
+// checkStockTarget verifica el destino de una operación de stock: un almacén existente o el stock
+// sin asignar, o bien una variante, cuyo stock no se asigna a almacenes.
+func (s *ProductService) checkStockTarget(ctx context.Context, warehouseID, variantID string) error {
+	if variantID != "" && warehouseID != "" {
+		return errors.New("variant stock cannot be assigned to a warehouse")
+	}
+	return s.checkWarehouse(ctx, warehouseID)
+}
+
+// checkWarehouse verifica que el almacén exista; un ID vacío representa el stock sin asignar.
+func (s *ProductService) checkWarehouse(ctx context.Context, warehouseID string) error {
+	if warehouseID == "" {
+		return nil
+	}
+	_, err := s.warehouses.FindOne(ctx, warehouseID)
+	return err
+}
+
+func (s *ProductService) applyStockChange(ctx context.Context, id, warehouseID, variantID string, stockDelta, reservedDelta int64, reason string) (*models.Product, error) {
+	product, err := s.repository.ApplyStockChange(ctx, id, warehouseID, variantID, stockDelta, reservedDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordMovement(ctx, id, warehouseID, variantID, stockDelta, reservedDelta, reason)
+	s.checkReorderPoint(ctx, product, stockDelta)
+
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	return product, nil
+}
+
+// checkReorderPoint emite una alerta si el cambio dejó el stock disponible en o por debajo del
+// punto de reorden partiendo de un valor por encima; así cada cruce se notifica una sola vez.
+func (s *ProductService) checkReorderPoint(ctx context.Context, product *models.Product, stockDelta int64) {
+	if stockDelta >= 0 || !product.IsLowStock() {
+		return
+	}
+	previous := int64(product.Stock) - stockDelta
+	if previous <= int64(product.ReorderRule().Point) {
+		return
+	}
+
+	alert := models.NewLowStockAlert(product, time.Now().UTC())
+	// El notificador puede ser lento (webhook): se llama en segundo plano sin bloquear la operación de stock.
+	go func(ctx context.Context) {
+		if err := s.notifier.NotifyLowStock(ctx, alert); err != nil {
+			log.Printf("failed to notify low stock for product %s: %v", alert.ProductID, err)
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+// GetLowStockAlerts retorna, con paginación, los productos que están en o por debajo de su punto de reorden.
+func (s *ProductService) GetLowStockAlerts(ctx context.Context, page, size int) ([]models.LowStockAlert, error) {
+	products, err := s.repository.FindLowStock(ctx, page, size)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	alerts := make([]models.LowStockAlert, 0, len(products))
+	for i := range products {
+		alerts = append(alerts, models.NewLowStockAlert(&products[i], now))
+	}
+	return alerts, nil
+}
+
+// recordMovement agrega el cambio de stock al historial. El stock ya cambió cuando se llama,
+// así que un fallo se registra en el log en lugar de reportarse al cliente.
+func (s *ProductService) recordMovement(ctx context.Context, id, warehouseID, variantID string, stockDelta, reservedDelta int64, reason string) {
+	movement := models.Movement{
+		ProductID:     id,
+		WarehouseID:   warehouseID,
+		VariantID:     variantID,
+		Delta:         stockDelta,
+		ReservedDelta: reservedDelta,
+		Reason:        reason,
+		Actor:         actor(ctx),
+		CorrelationID: utils.CorrelationIDFromContext(ctx),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	// El movimiento se guarda aunque el cliente haya cancelado la solicitud.
+	if err := s.movements.Create(context.WithoutCancel(ctx), movement); err != nil {
+		log.Printf("failed to record movement for product %s: %v", id, err)
+	}
+}
+
+// actor identifica al usuario autenticado que origina un cambio, o al sistema si no lo hay.
+func actor(ctx context.Context) string {
+	if claims, ok := auth.ClaimsFromContext(ctx); ok {
+		return claims.Subject
+	}
+	return models.MovementSystemActor
+}
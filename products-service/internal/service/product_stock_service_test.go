@@ -0,0 +1,504 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+)
+
+const otherProductID = "65f000000000000000000002"
+
+func initStockService(t *testing.T) (*ProductService, *repository.ProductRepositoryMocked, *repository.ReservationRepositoryMocked) {
+	t.Helper()
+
+	products := repository.NewProductRepositoryMocked(
+		models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 10},
+		models.Product{ID: otherProductID, Title: "Mouse", Category: "electronics", Stock: 10},
+	)
+	reservations := &repository.ReservationRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), reservations, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+
+	return service, products, reservations
+}
+
+func TestCloseReservationScope(t *testing.T) {
+	service, products, reservations := initStockService(t)
+	ctx := context.Background()
+
+	reservation, _, err := service.ReserveStock(ctx, testProductID, "", "", 4, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Una reserva de otro producto no se cierra ni mueve stock.
+	if _, err := service.CommitReservation(ctx, otherProductID, reservation.ID); !errors.Is(err, models.ErrReservationNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrReservationNotFound)
+	}
+	if status := reservations.Reservations[0].Status; status != models.ReservationActive {
+		t.Fatalf("unexpected status: got %v want %v", status, models.ReservationActive)
+	}
+
+	// Si el stock no se puede actualizar, la reserva vuelve a quedar activa.
+	broken, _ := reservations.Create(ctx, models.Reservation{ProductID: testProductID, VariantID: "missing", Quantity: 1, Status: models.ReservationActive, ExpiresAt: time.Now().Add(time.Minute)})
+	if _, err := service.ReleaseReservation(ctx, testProductID, broken.ID); !errors.Is(err, models.ErrVariantNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrVariantNotFound)
+	}
+	if status := reservations.Reservations[1].Status; status != models.ReservationActive {
+		t.Fatalf("unexpected status: got %v want %v", status, models.ReservationActive)
+	}
+
+	// Una reserva vencida no se confirma aunque el barrido aún no la haya liberado.
+	expired, _ := reservations.Create(ctx, models.Reservation{ProductID: testProductID, Quantity: 1, Status: models.ReservationActive, ExpiresAt: time.Now().Add(-time.Second)})
+	if _, err := service.CommitReservation(ctx, testProductID, expired.ID); !errors.Is(err, models.ErrReservationClosed) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrReservationClosed)
+	}
+
+	product, err := service.ReleaseReservation(ctx, testProductID, reservation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Stock != 10 || product.Reserved != 0 {
+		t.Fatalf("unexpected stock: got %v/%v want %v/%v", product.Stock, product.Reserved, 10, 0)
+	}
+	if other, _ := products.FindOne(ctx, otherProductID); other.Stock != 10 {
+		t.Fatalf("unexpected stock of the other product: got %v", other.Stock)
+	}
+}
+
+func TestStockAdjustments(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Run      func(ctx context.Context, s *ProductService) (*models.Product, error)
+		Err      error
+		Stock    uint
+		Reserved uint
+	}{
+		{
+			Name: "Increment",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				return s.IncrementStock(ctx, testProductID, "", "", 5)
+			},
+			Stock: 15,
+		},
+		{
+			Name: "Decrement",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				return s.DecrementStock(ctx, testProductID, "", "", 10)
+			},
+			Stock: 0,
+		},
+		{
+			Name: "Oversell",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				return s.DecrementStock(ctx, testProductID, "", "", 11)
+			},
+			Err:   models.ErrInsufficientStock,
+			Stock: 10,
+		},
+		{
+			Name: "Reserve",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				_, product, err := s.ReserveStock(ctx, testProductID, "", "", 4, time.Minute)
+				return product, err
+			},
+			Stock:    6,
+			Reserved: 4,
+		},
+		{
+			Name: "Reserve more than available",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				_, product, err := s.ReserveStock(ctx, testProductID, "", "", 11, time.Minute)
+				return product, err
+			},
+			Err:   models.ErrInsufficientStock,
+			Stock: 10,
+		},
+		{
+			Name: "Variant on a product without variants",
+			Run: func(ctx context.Context, s *ProductService) (*models.Product, error) {
+				return s.IncrementStock(ctx, testProductID, "", "missing", 1)
+			},
+			Err:   models.ErrVariantNotFound,
+			Stock: 10,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			service, products, _ := initStockService(t)
+			ctx := context.Background()
+
+			if _, err := tc.Run(ctx, service); !errors.Is(err, tc.Err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.Err)
+			}
+			product, _ := products.FindOne(ctx, testProductID)
+			if product.Stock != tc.Stock || product.Reserved != tc.Reserved {
+				t.Fatalf("unexpected stock: got %v/%v want %v/%v", product.Stock, product.Reserved, tc.Stock, tc.Reserved)
+			}
+		})
+	}
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	tc := []struct {
+		Name     string
+		Close    func(ctx context.Context, s *ProductService, id string) (*models.Product, error)
+		Status   string
+		Stock    uint
+		Reserved uint
+	}{
+		{Name: "Commit", Close: func(ctx context.Context, s *ProductService, id string) (*models.Product, error) {
+			return s.CommitReservation(ctx, testProductID, id)
+		}, Status: models.ReservationCommitted, Stock: 6},
+		{Name: "Release", Close: func(ctx context.Context, s *ProductService, id string) (*models.Product, error) {
+			return s.ReleaseReservation(ctx, testProductID, id)
+		}, Status: models.ReservationReleased, Stock: 10},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			service, _, reservations := initStockService(t)
+			ctx := context.Background()
+
+			reservation, _, err := service.ReserveStock(ctx, testProductID, "", "", 4, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ttl := time.Until(reservation.ExpiresAt); ttl <= DefaultReservationTTL-time.Minute || ttl > DefaultReservationTTL {
+				t.Fatalf("unexpected ttl: got %v want %v", ttl, DefaultReservationTTL)
+			}
+
+			product, err := tc.Close(ctx, service, reservation.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if product.Stock != tc.Stock || product.Reserved != 0 {
+				t.Fatalf("unexpected stock: got %v/%v want %v/%v", product.Stock, product.Reserved, tc.Stock, 0)
+			}
+			if status := reservations.Reservations[0].Status; status != tc.Status {
+				t.Fatalf("unexpected status: got %v want %v", status, tc.Status)
+			}
+
+			// Una reserva cerrada no se vuelve a cerrar.
+			if _, err := service.CommitReservation(ctx, testProductID, reservation.ID); !errors.Is(err, models.ErrReservationClosed) {
+				t.Fatalf("unexpected error: got %v want %v", err, models.ErrReservationClosed)
+			}
+			if _, err := service.ReleaseReservation(ctx, testProductID, reservation.ID); !errors.Is(err, models.ErrReservationClosed) {
+				t.Fatalf("unexpected error: got %v want %v", err, models.ErrReservationClosed)
+			}
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	service, products, reservations := initStockService(t)
+	ctx := context.Background()
+
+	expired, _, err := service.ReserveStock(ctx, testProductID, "", "", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := service.ReserveStock(ctx, otherProductID, "", "", 2, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reservations.Reservations[0].ExpiresAt = time.Now().Add(-time.Second)
+
+	released, err := service.ReleaseExpiredReservations(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("unexpected released: got %v want %v", released, 1)
+	}
+	if status := reservations.Reservations[0].Status; status != models.ReservationExpired {
+		t.Fatalf("unexpected status: got %v want %v", status, models.ReservationExpired)
+	}
+	if status := reservations.Reservations[1].Status; status != models.ReservationActive {
+		t.Fatalf("unexpected status: got %v want %v", status, models.ReservationActive)
+	}
+
+	keyboard, _ := products.FindOne(ctx, testProductID)
+	mouse, _ := products.FindOne(ctx, otherProductID)
+	if keyboard.Stock != 10 || keyboard.Reserved != 0 || mouse.Stock != 8 || mouse.Reserved != 2 {
+		t.Fatalf("unexpected stock: got %v/%v and %v/%v", keyboard.Stock, keyboard.Reserved, mouse.Stock, mouse.Reserved)
+	}
+
+	// Una segunda pasada no encuentra nada que liberar y una reserva expirada ya no se confirma.
+	if released, _ := service.ReleaseExpiredReservations(ctx); released != 0 {
+		t.Fatalf("unexpected released: got %v want %v", released, 0)
+	}
+	if _, err := service.CommitReservation(ctx, testProductID, expired.ID); !errors.Is(err, models.ErrReservationClosed) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrReservationClosed)
+	}
+}
+
+func TestMovementLedger(t *testing.T) {
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 10})
+	reservations := &repository.ReservationRepositoryMocked{}
+	movements := &repository.MovementRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), reservations, movements, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+
+	ctx := auth.ContextWithClaims(context.Background(), &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"}})
+	ctx = utils.ContextWithCorrelationID(ctx, "req-1")
+
+	if _, err := service.IncrementStock(ctx, testProductID, "", "", 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Un cambio rechazado no deja rastro en el historial.
+	if _, err := service.DecrementStock(ctx, testProductID, "", "", 100); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrInsufficientStock)
+	}
+	committed, _, err := service.ReserveStock(ctx, testProductID, "", "", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.CommitReservation(ctx, testProductID, committed.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := service.ReserveStock(ctx, testProductID, "", "", 2, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Las reservas vencidas las libera el sistema, sin usuario ni correlación.
+	reservations.Reservations[1].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := service.ReleaseExpiredReservations(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := service.GetMovements(ctx, testProductID, models.MovementFilter{}, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tc := []struct {
+		Reason        string
+		Delta         int64
+		ReservedDelta int64
+		Actor         string
+		CorrelationID string
+	}{
+		{Reason: models.MovementIncrement, Delta: 5, Actor: "user-1", CorrelationID: "req-1"},
+		{Reason: models.MovementReservation, Delta: -3, ReservedDelta: 3, Actor: "user-1", CorrelationID: "req-1"},
+		{Reason: models.MovementReservationCommit, ReservedDelta: -3, Actor: "user-1", CorrelationID: "req-1"},
+		{Reason: models.MovementReservation, Delta: -2, ReservedDelta: 2, Actor: "user-1", CorrelationID: "req-1"},
+		{Reason: models.MovementReservationExpired, Delta: 2, ReservedDelta: -2, Actor: models.MovementSystemActor},
+	}
+	if len(got) != len(tc) {
+		t.Fatalf("unexpected movements: got %v want %v", len(got), len(tc))
+	}
+
+	var total int64
+	for i := range tc {
+		tc := tc[i]
+		movement := got[i]
+		total += movement.Delta
+
+		t.Run(tc.Reason, func(t *testing.T) {
+			if movement.Reason != tc.Reason || movement.Delta != tc.Delta || movement.ReservedDelta != tc.ReservedDelta {
+				t.Fatalf("unexpected movement: got %v %v/%v want %v %v/%v", movement.Reason, movement.Delta, movement.ReservedDelta, tc.Reason, tc.Delta, tc.ReservedDelta)
+			}
+			if movement.Actor != tc.Actor || movement.CorrelationID != tc.CorrelationID {
+				t.Fatalf("unexpected origin: got %v/%v want %v/%v", movement.Actor, movement.CorrelationID, tc.Actor, tc.CorrelationID)
+			}
+			if movement.CreatedAt.IsZero() {
+				t.Fatalf("expected the movement to have a date")
+			}
+		})
+	}
+
+	// El historial explica el stock actual.
+	product, _ := products.FindOne(ctx, testProductID)
+	if int64(product.Stock) != 10+total {
+		t.Fatalf("unexpected stock: got %v want %v", product.Stock, 10+total)
+	}
+
+	if _, err := service.GetMovements(ctx, otherProductID, models.MovementFilter{}, 1, 10); !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductNotFound)
+	}
+}
+
+func TestTransferStock(t *testing.T) {
+	const (
+		north = "65f0000000000000000000a1"
+		south = "65f0000000000000000000a2"
+	)
+
+	tc := []struct {
+		Name     string
+		Transfer models.StockTransfer
+		Err      error
+		North    uint
+		South    uint
+	}{
+		{Name: "Unassigned to warehouse", Transfer: models.StockTransfer{ToWarehouseID: north, Quantity: 3}, North: 7, South: 2},
+		{Name: "Between warehouses", Transfer: models.StockTransfer{FromWarehouseID: north, ToWarehouseID: south, Quantity: 4}, North: 0, South: 6},
+		{Name: "Warehouse to unassigned", Transfer: models.StockTransfer{FromWarehouseID: south, Quantity: 2}, North: 4, South: 0},
+		{Name: "More than the warehouse holds", Transfer: models.StockTransfer{FromWarehouseID: north, ToWarehouseID: south, Quantity: 5}, Err: models.ErrInsufficientStock, North: 4, South: 2},
+		{Name: "More than is unassigned", Transfer: models.StockTransfer{ToWarehouseID: south, Quantity: 5}, Err: models.ErrInsufficientStock, North: 4, South: 2},
+		{Name: "Unknown warehouse", Transfer: models.StockTransfer{FromWarehouseID: north, ToWarehouseID: otherProductID, Quantity: 1}, Err: models.ErrWarehouseNotFound, North: 4, South: 2},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 10, Locations: []models.StockLocation{
+				{WarehouseID: north, Quantity: 4},
+				{WarehouseID: south, Quantity: 2},
+			}})
+			warehouses := &repository.WarehouseRepositoryMocked{Warehouses: []models.Warehouse{{ID: north, Code: "NORTH"}, {ID: south, Code: "SOUTH"}}}
+			movements := &repository.MovementRepositoryMocked{}
+			service := NewProductService(products, repository.NewProductCacheMocked(), nil, movements, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, warehouses, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+			ctx := context.Background()
+
+			if _, err := service.TransferStock(ctx, testProductID, tc.Transfer); !errors.Is(err, tc.Err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.Err)
+			}
+
+			// El total no cambia y sigue siendo la suma de las ubicaciones más el stock sin asignar.
+			product, _ := products.FindOne(ctx, testProductID)
+			located := map[string]uint{}
+			for _, location := range product.Locations {
+				located[location.WarehouseID] = location.Quantity
+			}
+			if product.Stock != 10 || located[north] != tc.North || located[south] != tc.South {
+				t.Fatalf("unexpected stock: got %v (%v/%v) want %v (%v/%v)", product.Stock, located[north], located[south], 10, tc.North, tc.South)
+			}
+
+			var delta int64
+			for _, movement := range movements.Movements {
+				delta += movement.Delta
+			}
+			if delta != 0 || (tc.Err == nil) != (len(movements.Movements) == 2) {
+				t.Fatalf("unexpected movements: got %+v", movements.Movements)
+			}
+		})
+	}
+}
+
+func TestWarehouseStockAdjustments(t *testing.T) {
+	const north = "65f0000000000000000000a1"
+
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 10})
+	warehouses := &repository.WarehouseRepositoryMocked{Warehouses: []models.Warehouse{{ID: north, Code: "NORTH"}}}
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, warehouses, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	product, err := service.IncrementStock(ctx, testProductID, north, "", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Stock != 13 || len(product.Locations) != 1 || product.Locations[0].Quantity != 3 {
+		t.Fatalf("unexpected stock: got %v %+v", product.Stock, product.Locations)
+	}
+
+	// El stock sin asignar no cubre las unidades de un almacén, ni un almacén las de otro.
+	if _, err := service.DecrementStock(ctx, testProductID, "", "", 11); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrInsufficientStock)
+	}
+	if _, err := service.DecrementStock(ctx, testProductID, north, "", 4); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrInsufficientStock)
+	}
+
+	warehouseService := NewWarehouseService(warehouses, products, repository.NewProductCacheMocked())
+	if err := warehouseService.DeleteWarehouse(ctx, north); !errors.Is(err, models.ErrWarehouseInUse) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrWarehouseInUse)
+	}
+	if _, err := service.DecrementStock(ctx, testProductID, north, "", 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := warehouseService.DeleteWarehouse(ctx, north); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product, _ := products.FindOne(ctx, testProductID); product.Stock != 10 || len(product.Locations) != 0 {
+		t.Fatalf("unexpected stock: got %v %+v", product.Stock, product.Locations)
+	}
+}
+
+// waitForAlerts espera a que el notificador reciba want alertas, que se envían en segundo plano.
+func waitForAlerts(t *testing.T, n *notifier.MemoryNotifier, want int) []models.LowStockAlert {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for len(n.Alerts()) < want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Da tiempo a que llegue una alerta de más antes de comparar.
+	time.Sleep(20 * time.Millisecond)
+	return n.Alerts()
+}
+
+func TestLowStockAlerts(t *testing.T) {
+	point, quantity, disabled := uint(5), uint(20), uint(0)
+	products := repository.NewProductRepositoryMocked(
+		models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 10, ReorderPoint: &point, ReorderQuantity: &quantity},
+		models.Product{ID: otherProductID, Title: "Mouse", Category: "electronics", Stock: 10, ReorderPoint: &disabled},
+	)
+	alerts := notifier.NewMemoryNotifier()
+	service := NewProductService(products, repository.NewProductCacheMocked(), &repository.ReservationRepositoryMocked{}, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, alerts, nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	// Los casos se aplican en orden sobre el mismo stock: solo se avisa al cruzar el punto de reorden.
+	tc := []struct {
+		Name   string
+		Run    func(s *ProductService) (*models.Product, error)
+		Alerts int
+	}{
+		{Name: "Above the reorder point", Run: func(s *ProductService) (*models.Product, error) {
+			return s.DecrementStock(ctx, testProductID, "", "", 3)
+		}, Alerts: 0},
+		{Name: "Reaches the reorder point", Run: func(s *ProductService) (*models.Product, error) {
+			return s.DecrementStock(ctx, testProductID, "", "", 2)
+		}, Alerts: 1},
+		{Name: "Stays below the reorder point", Run: func(s *ProductService) (*models.Product, error) {
+			return s.DecrementStock(ctx, testProductID, "", "", 1)
+		}, Alerts: 1},
+		{Name: "Restocked", Run: func(s *ProductService) (*models.Product, error) {
+			return s.IncrementStock(ctx, testProductID, "", "", 6)
+		}, Alerts: 1},
+		{Name: "Reservation crosses again", Run: func(s *ProductService) (*models.Product, error) {
+			_, product, err := s.ReserveStock(ctx, testProductID, "", "", 6, time.Minute)
+			return product, err
+		}, Alerts: 2},
+		{Name: "Alerts disabled", Run: func(s *ProductService) (*models.Product, error) {
+			return s.DecrementStock(ctx, otherProductID, "", "", 10)
+		}, Alerts: 2},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if _, err := tc.Run(service); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := waitForAlerts(t, alerts, tc.Alerts); len(got) != tc.Alerts {
+				t.Fatalf("unexpected alerts: got %v want %v", len(got), tc.Alerts)
+			}
+		})
+	}
+
+	alert := alerts.Alerts()[0]
+	if alert.ProductID != testProductID || alert.Stock != 5 || alert.ReorderPoint != point || alert.ReorderQuantity != quantity {
+		t.Fatalf("unexpected alert: got %+v", alert)
+	}
+
+	low, err := service.GetLowStockAlerts(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(low) != 1 || low[0].ProductID != testProductID || low[0].Stock != 4 {
+		t.Fatalf("unexpected low stock: got %+v", low)
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+const (
+	// DefaultTrashRetention es el tiempo que un producto permanece en la papelera antes de purgarse.
+	DefaultTrashRetention = 30 * 24 * time.Hour
+	// trashPurgeBatchSize es la cantidad de productos que la purga lee en cada consulta.
+	trashPurgeBatchSize = 100
+)
+
+// GetTrash retorna, con paginación, los productos de la papelera, primero los borrados más recientemente.
+func (s *ProductService) GetTrash(ctx context.Context, page, size int) ([]models.Product, error) {
+	return s.repository.FindDeleted(ctx, page, size)
+}
+
+// RestoreProduct saca un producto de la papelera con sus reseñas, su stock y su historial intactos.
+func (s *ProductService) RestoreProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := s.repository.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	return product, nil
+}
+
+// PurgeTrash elimina definitivamente los productos que llevan en la papelera más de retention, junto
+// con sus reseñas e imágenes, y retorna cuántos eliminó. Un producto restaurado mientras tanto o con
+// unidades reservadas se conserva.
+func (s *ProductService) PurgeTrash(ctx context.Context, retention time.Duration) (int, error) {
+	before := time.Now().UTC().Add(-retention)
+
+	purged := 0
+	for {
+		products, err := s.repository.FindDeletedBefore(ctx, before, trashPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, product := range products {
+			if err := s.repository.Purge(ctx, product.ID, before); err != nil {
+				if errors.Is(err, models.ErrProductNotFound) {
+					continue
+				}
+				return purged, err
+			}
+			purged++
+			if _, err := s.reviews.DeleteByProduct(ctx, product.ID); err != nil {
+				log.Printf("failed to delete reviews of purged product %s: %v", product.ID, err)
+			}
+			s.deleteImageObjects(ctx, product.Images...)
+		}
+
+		if len(products) < trashPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// RunTrashPurger purga periódicamente la papelera hasta que ctx se cancele.
+func (s *ProductService) RunTrashPurger(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeTrash(ctx, retention); err != nil {
+				log.Printf("trash purger: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestProductTrash(t *testing.T) {
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics", Stock: 50})
+	reviews := &repository.ReviewRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, &repository.MovementRepositoryMocked{}, &repository.PriceHistoryRepositoryMocked{}, reviews, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	if err := service.CreateProduct(ctx, models.Product{SKU: "MS-001", Title: "Mouse", Category: "electronics", Price: models.Money{Amount: 2500, Currency: "USD"}, Stock: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteProduct(ctx, testProductID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Las lecturas y escrituras habituales no ven el producto en la papelera.
+	tc := []struct {
+		Name string
+		Run  func() error
+	}{
+		{Name: "Get one product", Run: func() error {
+			_, err := service.GetOneProduct(ctx, testProductID)
+			return err
+		}},
+		{Name: "Delete again", Run: func() error {
+			return service.DeleteProduct(ctx, testProductID)
+		}},
+		{Name: "Update title", Run: func() error {
+			return service.UpdateProduct(ctx, testProductID, map[string]interface{}{"title": "Mechanical keyboard"})
+		}},
+		{Name: "Increment stock", Run: func() error {
+			_, err := service.IncrementStock(ctx, testProductID, "", "", 5)
+			return err
+		}},
+		{Name: "Add variant", Run: func() error {
+			_, err := service.AddVariant(ctx, testProductID, models.Variant{SKU: "KB-RED", Attributes: map[string]string{"color": "red"}})
+			return err
+		}},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if err := tc.Run(); !errors.Is(err, models.ErrProductNotFound) {
+				t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductNotFound)
+			}
+		})
+	}
+
+	page, err := service.GetAllProducts(ctx, models.ProductFilter{}, models.PageRequest{Page: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].SKU != "MS-001" {
+		t.Fatalf("unexpected listing: got %+v", page)
+	}
+	if err := service.UpdateProduct(ctx, testProductID, map[string]interface{}{"deleted_at": nil}); err == nil {
+		t.Fatalf("expected deleted_at to be refused in an update")
+	}
+
+	// Un producto con reservas abiertas no se borra, ni uno a uno ni en bloque.
+	mouse, _ := products.FindBySKU(ctx, "MS-001")
+	if _, err := products.ApplyStockChange(ctx, mouse.ID, "", "", -1, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteProduct(ctx, mouse.ID); !errors.Is(err, models.ErrProductReserved) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductReserved)
+	}
+	if result, err := service.BulkProducts(ctx, models.BulkRequest{IDs: []string{mouse.ID}, Delete: true}); err != nil || result.Skipped != 1 {
+		t.Fatalf("unexpected bulk result: got %+v (%v)", result, err)
+	}
+	if _, err := products.ApplyStockChange(ctx, mouse.ID, "", "", 1, -1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Un SKU en la papelera no se reutiliza al importar.
+	if _, err := reviews.Create(ctx, models.Review{ProductID: mouse.ID, Author: "ana", Rating: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteProduct(ctx, mouse.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := service.ImportProducts(ctx, strings.NewReader("sku,title,category,price_amount,price_currency\nMS-001,Mouse v2,electronics,2600,USD\n"), models.ProductFormatCSV, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Failed != 1 {
+		t.Fatalf("unexpected import report: got %+v", report)
+	}
+
+	trash, err := service.GetTrash(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trash) != 2 || trash[0].ID != mouse.ID || trash[1].DeletedAt == nil {
+		t.Fatalf("unexpected trash: got %+v", trash)
+	}
+
+	// Al restaurarlo conserva su stock.
+	restored, err := service.RestoreProduct(ctx, testProductID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Stock != 50 {
+		t.Fatalf("unexpected restored product: got %+v", restored)
+	}
+	if _, err := service.RestoreProduct(ctx, testProductID); !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrProductNotFound)
+	}
+
+	// La purga solo elimina los productos que superaron la retención.
+	if purged, err := service.PurgeTrash(ctx, time.Hour); err != nil || purged != 0 {
+		t.Fatalf("unexpected purge: got %v (%v) want %v", purged, err, 0)
+	}
+	if purged, err := service.PurgeTrash(ctx, 0); err != nil || purged != 1 {
+		t.Fatalf("unexpected purge: got %v (%v) want %v", purged, err, 1)
+	}
+	if found, _ := products.FindBySKUs(ctx, []string{"MS-001"}); len(found) != 0 {
+		t.Fatalf("expected the purged product to be gone: got %+v", found)
+	}
+	if _, total, _ := reviews.FindByProduct(ctx, mouse.ID, true, 1, 10); total != 0 {
+		t.Fatalf("expected the reviews of the purged product to be deleted: got %v", total)
+	}
+	if _, err := service.GetOneProduct(ctx, testProductID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
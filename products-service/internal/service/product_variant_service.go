@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddVariant agrega una variante al producto; su stock inicial se suma al del producto y se registra como movimiento.
+func (s *ProductService) AddVariant(ctx context.Context, id string, variant models.Variant) (*models.Product, error) {
+	if err := normalizeVariant(&variant); err != nil {
+		return nil, err
+	}
+	current, err := s.repository.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if variant.Price, err = variantPrice(variant.Price, current.Price.Currency); err != nil {
+		return nil, err
+	}
+	variant.ID = primitive.NewObjectID().Hex()
+	variant.Reserved = 0
+
+	product, err := s.repository.AddVariant(ctx, id, variant)
+	if err != nil {
+		return nil, err
+	}
+
+	if variant.Stock > 0 {
+		s.recordMovement(ctx, id, "", variant.ID, int64(variant.Stock), 0, models.MovementInitialStock)
+	}
+	s.recordPriceChange(ctx, id, variant.ID, nil, variant.Price)
+	s.invalidateCache(ctx, id)
+
+	return product, nil
+}
+
+// UpdateVariant modifica el SKU, los atributos o el precio de una variante, que debe mantener la moneda
+// del producto; su stock solo cambia mediante las operaciones de inventario.
+func (s *ProductService) UpdateVariant(ctx context.Context, id, variantID string, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return errors.New("no fields to update")
+	}
+
+	var previous *models.Money
+	for field, value := range fields {
+		switch field {
+		case "sku":
+			sku, ok := value.(string)
+			if !ok {
+				return errors.New("sku must be a string")
+			}
+			normalized, err := utils.NormalizeSKU(sku)
+			if err != nil {
+				return err
+			}
+			fields[field] = normalized
+		case "price":
+			product, err := s.repository.FindOne(ctx, id)
+			if err != nil {
+				return err
+			}
+			variant, ok := product.FindVariant(variantID)
+			if !ok {
+				return models.ErrVariantNotFound
+			}
+			price, err := parseMoney(value, product.Price.Currency)
+			if err != nil {
+				return err
+			}
+			if fields[field], err = variantPrice(price, product.Price.Currency); err != nil {
+				return err
+			}
+			previous = &variant.Price
+		case "attributes":
+			raw, ok := value.(map[string]interface{})
+			if !ok {
+				return errors.New("attributes must be an object of strings")
+			}
+			attributes := make(map[string]string, len(raw))
+			for name, value := range raw {
+				str, ok := value.(string)
+				if !ok {
+					return errors.New("attributes must be an object of strings")
+				}
+				attributes[name] = str
+			}
+			normalized, err := normalizeAttributes(attributes)
+			if err != nil {
+				return err
+			}
+			fields[field] = normalized
+		case "stock", "reserved":
+			return errors.New(field + " must be changed through the stock endpoints")
+		default:
+			return errors.New(field + " cannot be updated")
+		}
+	}
+
+	if err := s.repository.UpdateVariant(ctx, id, variantID, fields); err != nil {
+		return err
+	}
+
+	// Invalida el producto y los listados en el cache.
+	s.invalidateCache(ctx, id)
+
+	if previous != nil {
+		s.recordPriceChange(ctx, id, variantID, previous, fields["price"].(models.Money))
+	}
+
+	return nil
+}
+
+// DeleteVariant elimina una variante sin unidades reservadas; su stock disponible sale del inventario.
+func (s *ProductService) DeleteVariant(ctx context.Context, id, variantID string) error {
+	variant, err := s.repository.RemoveVariant(ctx, id, variantID)
+	if err != nil {
+		return err
+	}
+
+	if variant.Stock > 0 {
+		s.recordMovement(ctx, id, "", variantID, -int64(variant.Stock), 0, models.MovementVariantRemoved)
+	}
+	s.invalidateCache(ctx, id)
+
+	return nil
+}
+
+// prepareVariants normaliza las variantes de un producto nuevo y deriva de ellas su stock.
+func prepareVariants(product *models.Product) error {
+	if len(product.Variants) == 0 {
+		product.Variants = nil
+		return nil
+	}
+	if len(product.Locations) > 0 {
+		return errors.New("products with variants cannot have warehouse locations")
+	}
+
+	seen := make(map[string]bool, len(product.Variants))
+	product.Stock = 0
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if err := normalizeVariant(variant); err != nil {
+			return err
+		}
+		if seen[variant.SKU] {
+			return errors.New("duplicate variant sku " + variant.SKU)
+		}
+		seen[variant.SKU] = true
+
+		variant.ID = primitive.NewObjectID().Hex()
+		variant.Reserved = 0
+		product.Stock += variant.Stock
+	}
+	return nil
+}
+
+// normalizeVariant valida y normaliza el SKU y los atributos de una variante.
+func normalizeVariant(variant *models.Variant) error {
+	sku, err := utils.NormalizeSKU(variant.SKU)
+	if err != nil {
+		return err
+	}
+	variant.SKU = sku
+
+	variant.Attributes, err = normalizeAttributes(variant.Attributes)
+	return err
+}
+
+// normalizeAttributes valida los atributos de una variante; debe tener al menos uno.
+func normalizeAttributes(attributes map[string]string) (map[string]string, error) {
+	if len(attributes) == 0 {
+		return nil, errors.New("variants need at least one attribute")
+	}
+
+	normalized := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		name, value, err := utils.NormalizeAttribute(name, value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := normalized[name]; ok {
+			return nil, errors.New("duplicate attribute " + name)
+		}
+		normalized[name] = value
+	}
+	return normalized, nil
+}
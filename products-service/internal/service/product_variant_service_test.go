@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/notifier"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func TestProductVariants(t *testing.T) {
+	products := repository.NewProductRepositoryMocked()
+	movements := &repository.MovementRepositoryMocked{}
+	service := NewProductService(products, repository.NewProductCacheMocked(), nil, movements, &repository.PriceHistoryRepositoryMocked{}, &repository.ReviewRepositoryMocked{}, nil, notifier.NewMemoryNotifier(), nil, DefaultCacheOptions())
+	ctx := context.Background()
+
+	err := service.CreateProduct(ctx, models.Product{SKU: "TS-001", Title: "T-shirt", Category: "clothing", Price: models.Money{Amount: 2000, Currency: "usd"}, Variants: []models.Variant{
+		{SKU: "ts-001-m-blue", Attributes: map[string]string{"Size": "M", "color": " blue "}, Price: models.Money{Amount: 2000}, Stock: 4},
+		{SKU: "ts-001-l-blue", Attributes: map[string]string{"size": "L", "color": "blue"}, Price: models.Money{Amount: 2200, Currency: "USD"}, Stock: 6},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	product, err := service.GetProductBySKU(ctx, "TS-001-M-BLUE")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Stock != 10 {
+		t.Fatalf("unexpected stock: got %v want %v", product.Stock, 10)
+	}
+	medium := product.Variants[0]
+	if medium.ID == "" || medium.Attributes["size"] != "M" || medium.Attributes["color"] != "blue" {
+		t.Fatalf("unexpected variant: got %+v", medium)
+	}
+
+	if _, err := service.IncrementStock(ctx, product.ID, "", "", 1); !errors.Is(err, models.ErrVariantRequired) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrVariantRequired)
+	}
+	if _, err := service.DecrementStock(ctx, product.ID, "", medium.ID, 5); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrInsufficientStock)
+	}
+	product, err = service.DecrementStock(ctx, product.ID, "", medium.ID, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if product.Stock != 7 || product.Variants[0].Stock != 1 {
+		t.Fatalf("unexpected stock: got %v/%v want %v/%v", product.Stock, product.Variants[0].Stock, 7, 1)
+	}
+
+	if err := service.CreateProduct(ctx, models.Product{SKU: "KB-001", Title: "Keyboard", Category: "electronics", Price: models.Money{Amount: 4500, Currency: "USD"}, Stock: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keyboard, _ := service.GetProductBySKU(ctx, "KB-001")
+
+	tc := []struct {
+		Name      string
+		ProductID string
+		Variant   models.Variant
+		Err       error
+	}{
+		{Name: "Duplicate SKU", ProductID: product.ID, Variant: models.Variant{SKU: "TS-001-L-BLUE", Attributes: map[string]string{"size": "XL"}}, Err: models.ErrDuplicateSKU},
+		{Name: "Product with its own stock", ProductID: keyboard.ID, Variant: models.Variant{SKU: "KB-001-RED", Attributes: map[string]string{"color": "red"}}, Err: models.ErrProductHasStock},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if _, err := service.AddVariant(ctx, tc.ProductID, tc.Variant); !errors.Is(err, tc.Err) {
+				t.Fatalf("unexpected error: got %v want %v", err, tc.Err)
+			}
+		})
+	}
+
+	if err := service.UpdateVariant(ctx, product.ID, medium.ID, map[string]interface{}{"stock": 10.0}); err == nil {
+		t.Fatalf("expected the variant stock to be read-only")
+	}
+
+	// Una variante con unidades reservadas no se elimina.
+	if _, err := products.ApplyStockChange(ctx, product.ID, "", medium.ID, -1, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.DeleteVariant(ctx, product.ID, medium.ID); !errors.Is(err, models.ErrVariantReserved) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrVariantReserved)
+	}
+
+	large := product.Variants[1]
+	if err := service.DeleteVariant(ctx, product.ID, large.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product, _ = products.FindOne(ctx, product.ID)
+	if product.Stock != 0 || len(product.Variants) != 1 {
+		t.Fatalf("unexpected product: got stock %v and %v variants", product.Stock, len(product.Variants))
+	}
+
+	last := movements.Movements[len(movements.Movements)-1]
+	if last.Reason != models.MovementVariantRemoved || last.VariantID != large.ID || last.Delta != -6 {
+		t.Fatalf("unexpected movement: got %+v", last)
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// ReviewService contiene la lógica de negocio de las reseñas. Cada cambio que afecta a una reseña
+// visible ajusta de forma incremental la calificación agregada del producto.
+type ReviewService struct {
+	repository interfaces.ReviewRepositoryInterface
+	products   interfaces.ProductMongoRepositoryInterface
+	cache      interfaces.ProductRedisRepositoryInterface
+}
+
+// NewReviewService crea una nueva instancia de ReviewService.
+func NewReviewService(repository interfaces.ReviewRepositoryInterface, products interfaces.ProductMongoRepositoryInterface, cache interfaces.ProductRedisRepositoryInterface) *ReviewService {
+	return &ReviewService{repository: repository, products: products, cache: cache}
+}
+
+// GetReviews retorna una página de reseñas del producto. Solo los moderadores pueden incluir las ocultas.
+func (s *ReviewService) GetReviews(ctx context.Context, productID string, includeHidden bool, page, size int) (*models.ReviewPage, error) {
+	if includeHidden && !isModerator(ctx) {
+		return nil, fmt.Errorf("%w: only moderators can see hidden reviews", models.ErrForbidden)
+	}
+	if _, err := s.products.FindOne(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	reviews, total, err := s.repository.FindByProduct(ctx, productID, includeHidden, page, size)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ReviewPage{Items: reviews, Total: total, Page: page, Size: size}, nil
+}
+
+// CreateReview publica la reseña del usuario autenticado; cada usuario puede reseñar un producto una vez.
+func (s *ReviewService) CreateReview(ctx context.Context, productID string, request models.ReviewRequest) (*models.Review, error) {
+	claims, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.products.FindOne(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	review, err := s.repository.Create(ctx, models.Review{
+		ProductID: productID,
+		Author:    claims.Subject,
+		Rating:    request.Rating,
+		Text:      request.Text,
+		Status:    models.ReviewVisible,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyRatingChange(ctx, productID, int64(review.Rating), 1)
+	return review, nil
+}
+
+// UpdateReview aplica los cambios del autor a su reseña.
+func (s *ReviewService) UpdateReview(ctx context.Context, productID, id string, update models.ReviewUpdate) (*models.Review, error) {
+	claims, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	review, err := s.repository.FindOne(ctx, productID, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Author != claims.Subject {
+		return nil, fmt.Errorf("%w: only the author can edit a review", models.ErrForbidden)
+	}
+
+	before, err := s.repository.Update(ctx, productID, id, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Rating != nil && before.Status == models.ReviewVisible {
+		s.applyRatingChange(ctx, productID, int64(*update.Rating)-int64(before.Rating), 0)
+	}
+	return s.repository.FindOne(ctx, productID, id)
+}
+
+// ModerateReview oculta una reseña o la vuelve a mostrar; las ocultas no cuentan en la calificación.
+func (s *ReviewService) ModerateReview(ctx context.Context, productID, id, status string) (*models.Review, error) {
+	before, err := s.repository.SetStatus(ctx, productID, id, status)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case before.Status == models.ReviewVisible && status == models.ReviewHidden:
+		s.applyRatingChange(ctx, productID, -int64(before.Rating), -1)
+	case before.Status == models.ReviewHidden && status == models.ReviewVisible:
+		s.applyRatingChange(ctx, productID, int64(before.Rating), 1)
+	}
+
+	review := *before
+	review.Status = status
+	return &review, nil
+}
+
+// DeleteReview elimina una reseña; puede hacerlo su autor o un moderador.
+func (s *ReviewService) DeleteReview(ctx context.Context, productID, id string) error {
+	claims, err := caller(ctx)
+	if err != nil {
+		return err
+	}
+	review, err := s.repository.FindOne(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+	if review.Author != claims.Subject && !isModerator(ctx) {
+		return fmt.Errorf("%w: only the author or a moderator can delete a review", models.ErrForbidden)
+	}
+
+	deleted, err := s.repository.Delete(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+
+	if deleted.Status == models.ReviewVisible {
+		s.applyRatingChange(ctx, productID, -int64(deleted.Rating), -1)
+	}
+	return nil
+}
+
+// applyRatingChange ajusta la calificación del producto e invalida su cache. La reseña ya cambió,
+// así que un fallo se registra en lugar de reportarse al cliente.
+func (s *ReviewService) applyRatingChange(ctx context.Context, productID string, sumDelta, countDelta int64) {
+	if err := s.products.ApplyRatingChange(context.WithoutCancel(ctx), productID, sumDelta, countDelta); err != nil {
+		log.Printf("failed to update rating of product %s by %+d/%+d: %v", productID, sumDelta, countDelta, err)
+		return
+	}
+	if err := s.cache.InvalidateProducts(ctx, productID); err != nil {
+		logCacheError("invalidate", err)
+	}
+}
+
+// caller retorna la identidad del usuario autenticado.
+func caller(ctx context.Context) (*auth.Claims, error) {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok || claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing caller identity", models.ErrForbidden)
+	}
+	return claims, nil
+}
+
+// isModerator indica si el usuario autenticado puede moderar reseñas.
+func isModerator(ctx context.Context) bool {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	return ok && claims.Role == constants.RoleAdmin
+}
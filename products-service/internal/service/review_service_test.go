@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/auth"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/repository"
+)
+
+func asUser(subject, role string) context.Context {
+	return auth.ContextWithClaims(context.Background(), &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: subject}, Role: role})
+}
+
+func initReviewService(t *testing.T) (*ReviewService, *repository.ProductRepositoryMocked, *repository.ProductCacheMocked) {
+	t.Helper()
+
+	products := repository.NewProductRepositoryMocked(models.Product{ID: testProductID, Title: "Keyboard", Category: "electronics"})
+	cache := repository.NewProductCacheMocked()
+	return NewReviewService(&repository.ReviewRepositoryMocked{}, products, cache), products, cache
+}
+
+func TestReviewRatingAggregate(t *testing.T) {
+	service, products, cache := initReviewService(t)
+	alice := asUser("alice", constants.RoleCustomer)
+	bob := asUser("bob", constants.RoleCustomer)
+	admin := asUser("root", constants.RoleAdmin)
+
+	expectRating := func(t *testing.T, average float64, count int64) {
+		t.Helper()
+		product, _ := products.FindOne(context.Background(), testProductID)
+		if product.Rating.Average != average || product.Rating.Count != count {
+			t.Fatalf("unexpected rating: got %v/%v want %v/%v", product.Rating.Average, product.Rating.Count, average, count)
+		}
+	}
+
+	first, err := service.CreateReview(alice, testProductID, models.ReviewRequest{Rating: 5, Text: "Great"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Author != "alice" || first.Status != models.ReviewVisible {
+		t.Fatalf("unexpected review: got %+v", first)
+	}
+	second, err := service.CreateReview(bob, testProductID, models.ReviewRequest{Rating: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 3.5, 2)
+
+	if _, err := service.CreateReview(alice, testProductID, models.ReviewRequest{Rating: 1}); !errors.Is(err, models.ErrDuplicateReview) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrDuplicateReview)
+	}
+
+	rating := uint(3)
+	if _, err := service.UpdateReview(bob, testProductID, first.ID, models.ReviewUpdate{Rating: &rating}); !errors.Is(err, models.ErrForbidden) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrForbidden)
+	}
+	if _, err := service.UpdateReview(alice, testProductID, first.ID, models.ReviewUpdate{Rating: &rating}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 2.5, 2)
+
+	if _, err := service.ModerateReview(admin, testProductID, second.ID, models.ReviewHidden); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 3, 1)
+
+	// Ocultar dos veces no descuenta la reseña de nuevo.
+	if _, err := service.ModerateReview(admin, testProductID, second.ID, models.ReviewHidden); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 3, 1)
+
+	page, err := service.GetReviews(alice, testProductID, false, 1, 10)
+	if err != nil || page.Total != 1 {
+		t.Fatalf("unexpected visible reviews: got %v, %v", page, err)
+	}
+	if _, err := service.GetReviews(alice, testProductID, true, 1, 10); !errors.Is(err, models.ErrForbidden) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrForbidden)
+	}
+
+	// Eliminar una reseña oculta no cambia la calificación.
+	if err := service.DeleteReview(bob, testProductID, second.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 3, 1)
+
+	if err := service.DeleteReview(bob, testProductID, first.ID); !errors.Is(err, models.ErrForbidden) {
+		t.Fatalf("unexpected error: got %v want %v", err, models.ErrForbidden)
+	}
+	if err := service.DeleteReview(admin, testProductID, first.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectRating(t, 0, 0)
+
+	if len(cache.Invalidated) == 0 {
+		t.Errorf("product cache was not invalidated")
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/interfaces"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// WarehouseService contiene la lógica de negocio de los almacenes.
+type WarehouseService struct {
+	repository interfaces.WarehouseRepositoryInterface
+	products   interfaces.ProductMongoRepositoryInterface
+	cache      interfaces.ProductRedisRepositoryInterface
+}
+
+// NewWarehouseService crea una nueva instancia de WarehouseService.
+func NewWarehouseService(repository interfaces.WarehouseRepositoryInterface, products interfaces.ProductMongoRepositoryInterface, cache interfaces.ProductRedisRepositoryInterface) *WarehouseService {
+	return &WarehouseService{repository: repository, products: products, cache: cache}
+}
+
+// GetAllWarehouses retorna todos los almacenes.
+func (s *WarehouseService) GetAllWarehouses(ctx context.Context) ([]models.Warehouse, error) {
+	return s.repository.FindAll(ctx)
+}
+
+// GetOneWarehouse retorna un almacén por su ID.
+func (s *WarehouseService) GetOneWarehouse(ctx context.Context, id string) (*models.Warehouse, error) {
+	return s.repository.FindOne(ctx, id)
+}
+
+// CreateWarehouse registra un almacén nuevo; el código se normaliza en mayúsculas.
+func (s *WarehouseService) CreateWarehouse(ctx context.Context, warehouse models.Warehouse) (*models.Warehouse, error) {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	if warehouse.Code == "" {
+		return nil, errors.New("code is required")
+	}
+	warehouse.CreatedAt = time.Now().UTC()
+
+	return s.repository.Create(ctx, warehouse)
+}
+
+// UpdateWarehouse modifica el código, nombre o dirección de un almacén.
+func (s *WarehouseService) UpdateWarehouse(ctx context.Context, id string, fields map[string]interface{}) error {
+	for field, value := range fields {
+		switch field {
+		case "name", "address":
+		case "code":
+			code, ok := value.(string)
+			if !ok || strings.TrimSpace(code) == "" {
+				return errors.New("code must be a non-empty string")
+			}
+			fields[field] = strings.ToUpper(strings.TrimSpace(code))
+		default:
+			return errors.New(field + " cannot be updated")
+		}
+	}
+
+	return s.repository.Update(ctx, id, fields)
+}
+
+// DeleteWarehouse elimina un almacén que ya no guarda stock y quita sus ubicaciones vacías de los productos.
+func (s *WarehouseService) DeleteWarehouse(ctx context.Context, id string) error {
+	if _, err := s.repository.FindOne(ctx, id); err != nil {
+		return err
+	}
+
+	count, err := s.products.CountByWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrWarehouseInUse
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+	productIDs, err := s.products.RemoveWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Invalida en el cache los productos que tenían la ubicación. El almacén ya se eliminó,
+	// así que un fallo del cache se registra en lugar de reportarse al cliente.
+	if err := s.cache.InvalidateProducts(ctx, productIDs...); err != nil {
+		log.Printf("failed to invalidate cache after deleting warehouse %s: %v", id, err)
+	}
+	return nil
+}
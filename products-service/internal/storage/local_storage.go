@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// LocalStorage guarda las imágenes en un directorio local; pensado para desarrollo y pruebas.
+// No admite subidas directas.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage crea un almacenamiento en dir cuyos objetos se sirven bajo baseURL.
+func NewLocalStorage(dir, baseURL string) *LocalStorage {
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Dir retorna el directorio donde se guardan los objetos.
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Put escribe el objeto en el directorio, creando los subdirectorios de la clave
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("error uploading image: %v", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error uploading image: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("error uploading image: %v", err)
+	}
+	return s.URL(key), nil
+}
+
+// Open abre el objeto del directorio
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, fmt.Errorf("%w: %s", models.ErrImageNotFound, key)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading image: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("error reading image: %v", err)
+	}
+	return file, info.Size(), nil
+}
+
+// URL retorna la URL pública del objeto
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Delete elimina el objeto del directorio
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting image: %v", err)
+	}
+	return nil
+}
+
+// path resuelve la clave dentro del directorio sin permitir que salga de él
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid image key: %s", key)
+	}
+	return path, nil
+}
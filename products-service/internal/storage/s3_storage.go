@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// S3Storage guarda las imágenes en un bucket de S3 o de un servicio compatible como MinIO.
+type S3Storage struct {
+	client    *s3.S3
+	presigner *s3.S3
+	bucket    string
+	baseURL   string
+}
+
+// NewS3Storage crea un almacenamiento sobre bucket. presigner firma las URLs de subida directa y
+// permite que usen un endpoint distinto al que usa el servicio (el público de MinIO, por ejemplo);
+// si es nil se usa client. baseURL es la URL pública bajo la que se sirven los objetos (un CDN,
+// por ejemplo); si está vacía se usa la URL del bucket.
+func NewS3Storage(client, presigner *s3.S3, bucket, baseURL string) *S3Storage {
+	if presigner == nil {
+		presigner = client
+	}
+	if baseURL == "" {
+		baseURL = bucketURL(presigner, bucket)
+	}
+	return &S3Storage{client: client, presigner: presigner, bucket: bucket, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Put sube el objeto al bucket
+func (s *S3Storage) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) (string, error) {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error uploading image: %v", err)
+	}
+	return s.URL(key), nil
+}
+
+// Open descarga el objeto del bucket
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, 0, fmt.Errorf("%w: %s", models.ErrImageNotFound, key)
+		}
+		return nil, 0, fmt.Errorf("error reading image: %v", err)
+	}
+	return output.Body, aws.Int64Value(output.ContentLength), nil
+}
+
+// URL retorna la URL pública del objeto
+func (s *S3Storage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Delete elimina el objeto del bucket; S3 no reporta error si no existe
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting image: %v", err)
+	}
+	return nil
+}
+
+// PresignPut firma una URL de subida. El tipo forma parte de la firma, así que S3 rechaza una subida
+// con otro Content-Type; el tamaño no, por eso se verifica al confirmar la subida
+func (s *S3Storage) PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (string, error) {
+	req, _ := s.presigner.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	req.SetContext(ctx)
+
+	url, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("error signing upload URL: %v", err)
+	}
+	return url, nil
+}
+
+// bucketURL retorna la URL del bucket según el estilo de direccionamiento del cliente
+func bucketURL(client *s3.S3, bucket string) string {
+	if aws.BoolValue(client.Config.S3ForcePathStyle) {
+		return strings.TrimSuffix(client.Endpoint, "/") + "/" + bucket
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, aws.StringValue(client.Config.Region))
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/models"
+)
+
+// initS3Server simula un servicio compatible con S3 con direccionamiento por ruta.
+func initS3Server(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>`)
+				return
+			}
+			w.Write(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func initS3Storage(t *testing.T, endpoint string) *S3Storage {
+	t.Helper()
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials("key", "secret", ""),
+	}))
+	return NewS3Storage(s3.New(sess), nil, "images", "")
+}
+
+func TestS3StorageDirectUpload(t *testing.T) {
+	ctx := context.Background()
+	server := initS3Server(t)
+	storage := initS3Storage(t, server.URL)
+
+	url, err := storage.PresignPut(ctx, "products/1/a.png", "image/png", time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(url, server.URL+"/images/products/1/a.png?") || !strings.Contains(url, "X-Amz-Signature=") {
+		t.Fatalf("unexpected upload URL: %v", url)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, url, bytes.NewReader([]byte("content")))
+	req.Header.Set("Content-Type", "image/png")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	object, size, err := storage.Open(ctx, "products/1/a.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(object)
+	object.Close()
+	if string(body) != "content" || size != int64(len("content")) {
+		t.Errorf("unexpected object: got %q (%v bytes)", body, size)
+	}
+
+	if got := storage.URL("products/1/a.png"); got != server.URL+"/images/products/1/a.png" {
+		t.Errorf("unexpected URL: got %v", got)
+	}
+
+	if err := storage.Delete(ctx, "products/1/a.png"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := storage.Open(ctx, "products/1/a.png"); !errors.Is(err, models.ErrImageNotFound) {
+		t.Errorf("unexpected error: got %v want %v", err, models.ErrImageNotFound)
+	}
+}
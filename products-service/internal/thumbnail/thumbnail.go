@@ -0,0 +1,68 @@
+// Package thumbnail genera miniaturas de imágenes sin dependencias nativas.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Decodificadores registrados para image.Decode.
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ContentType es el tipo de las miniaturas generadas.
+const ContentType = "image/jpeg"
+
+// MaxPixels limita el tamaño de las imágenes que se decodifican; una imagen pequeña en bytes
+// puede ocupar gigabytes una vez decodificada.
+const MaxPixels = 40_000_000
+
+// ErrTooManyPixels indica que la imagen supera MaxPixels.
+var ErrTooManyPixels = errors.New("image dimensions are too large")
+
+// Generate decodifica una imagen JPEG, PNG, GIF o WebP y retorna una miniatura JPEG que cabe en un
+// cuadrado de size píxeles, conservando la proporción. Las imágenes más pequeñas no se amplían y las
+// transparencias se rellenan de blanco.
+func Generate(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %v", err)
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %v", err)
+	}
+
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), size)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fit calcula las dimensiones que caben en size×size conservando la proporción
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
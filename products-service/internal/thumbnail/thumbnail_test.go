@@ -0,0 +1,86 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encode(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, src)
+	case "gif":
+		err = gif.Encode(&buf, src, nil)
+	default:
+		err = jpeg.Encode(&buf, src, nil)
+	}
+	if err != nil {
+		t.Fatalf("could not encode image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	tc := []struct {
+		Name           string
+		Data           []byte
+		ExpectedWidth  int
+		ExpectedHeight int
+		ExpectedError  bool
+	}{
+		{
+			Name:           "Landscape PNG",
+			Data:           encode(t, "png", 800, 400),
+			ExpectedWidth:  200,
+			ExpectedHeight: 100,
+		},
+		{
+			Name:           "Portrait JPEG",
+			Data:           encode(t, "jpeg", 300, 600),
+			ExpectedWidth:  100,
+			ExpectedHeight: 200,
+		},
+		{
+			Name:           "Small GIF is not enlarged",
+			Data:           encode(t, "gif", 50, 40),
+			ExpectedWidth:  50,
+			ExpectedHeight: 40,
+		},
+		{
+			Name:          "Not an image",
+			Data:          []byte("not an image"),
+			ExpectedError: true,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := Generate(tc.Data, 200)
+			if (err != nil) != tc.ExpectedError {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.ExpectedError {
+				return
+			}
+
+			thumb, err := jpeg.DecodeConfig(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("thumbnail is not a JPEG: %v", err)
+			}
+			if thumb.Width != tc.ExpectedWidth || thumb.Height != tc.ExpectedHeight {
+				t.Errorf("unexpected size: got %vx%v want %vx%v", thumb.Width, thumb.Height, tc.ExpectedWidth, tc.ExpectedHeight)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package xlsx escribe libros de Excel de una sola hoja fila por fila, sin mantenerlos en memoria.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType es el tipo de los archivos generados.
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Partes fijas del paquete; solo la hoja depende de los datos.
+var staticParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// Writer escribe las filas de una hoja a medida que llegan.
+type Writer struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	rows  int
+}
+
+// NewWriter escribe en w un libro con una hoja llamada sheet y deja la hoja abierta para agregar filas.
+func NewWriter(w io.Writer, sheet string) (*Writer, error) {
+	archive := zip.NewWriter(w)
+
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+		`<sheet name="` + escape(sheet) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	parts := append([]struct{ name, content string }{{"xl/workbook.xml", workbook}}, staticParts...)
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("error writing xlsx: %v", err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, fmt.Errorf("error writing xlsx: %v", err)
+		}
+	}
+
+	// La hoja es la última parte del archivo, así que se puede escribir sin conocer su tamaño.
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("error writing xlsx: %v", err)
+	}
+	sheetWriter := bufio.NewWriter(f)
+	sheetWriter.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	return &Writer{zip: archive, sheet: sheetWriter}, nil
+}
+
+// WriteRow agrega una fila. Los enteros y decimales se guardan como números y el resto como texto.
+func (w *Writer) WriteRow(cells ...interface{}) error {
+	w.rows++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.rows)
+	for _, cell := range cells {
+		switch value := cell.(type) {
+		case int:
+			fmt.Fprintf(w.sheet, `<c><v>%d</v></c>`, value)
+		case int64:
+			fmt.Fprintf(w.sheet, `<c><v>%d</v></c>`, value)
+		case uint:
+			fmt.Fprintf(w.sheet, `<c><v>%d</v></c>`, value)
+		case float64:
+			fmt.Fprintf(w.sheet, `<c><v>%s</v></c>`, strconv.FormatFloat(value, 'f', -1, 64))
+		case nil:
+			w.sheet.WriteString(`<c/>`)
+		default:
+			fmt.Fprintf(w.sheet, `<c t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, escape(fmt.Sprint(value)))
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close cierra la hoja y el archivo; sin él, el libro queda incompleto.
+func (w *Writer) Close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return fmt.Errorf("error writing xlsx: %v", err)
+	}
+	return w.zip.Close()
+}
+
+// escape codifica el texto para XML; los caracteres que XML no admite se reemplazan por U+FFFD.
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Products & stock")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteRow("sku", "stock"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteRow("KB-<01>", uint(7), int64(-3), 1.5, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, _ := f.Open()
+		data, _ := io.ReadAll(r)
+		r.Close()
+		parts[f.Name] = string(data)
+	}
+
+	tc := []struct {
+		Name     string
+		Part     string
+		Contains string
+	}{
+		{Name: "Content types", Part: "[Content_Types].xml", Contains: `PartName="/xl/worksheets/sheet1.xml"`},
+		{Name: "Escaped sheet name", Part: "xl/workbook.xml", Contains: `name="Products &amp; stock"`},
+		{Name: "Text cell", Part: "xl/worksheets/sheet1.xml", Contains: `<t xml:space="preserve">KB-&lt;01&gt;</t>`},
+		{Name: "Number cells", Part: "xl/worksheets/sheet1.xml", Contains: `<c><v>7</v></c><c><v>-3</v></c><c><v>1.5</v></c><c/>`},
+		{Name: "Second row", Part: "xl/worksheets/sheet1.xml", Contains: `<row r="2">`},
+		{Name: "Closed sheet", Part: "xl/worksheets/sheet1.xml", Contains: `</sheetData></worksheet>`},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			if !strings.Contains(parts[tc.Part], tc.Contains) {
+				t.Fatalf("unexpected %s: got %q want it to contain %q", tc.Part, parts[tc.Part], tc.Contains)
+			}
+		})
+	}
+}
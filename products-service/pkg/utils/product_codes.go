@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,63}$`)
+
+// NormalizeSKU valida un SKU y lo retorna en mayúsculas: hasta 64 letras, dígitos, puntos, guiones
+// o guiones bajos, empezando por letra o dígito.
+func NormalizeSKU(sku string) (string, error) {
+	sku = strings.ToUpper(strings.TrimSpace(sku))
+	if sku == "" {
+		return "", errors.New("sku is required")
+	}
+	if !skuPattern.MatchString(sku) {
+		return "", errors.New("sku must have up to 64 letters, digits, '.', '-' or '_'")
+	}
+	return sku, nil
+}
+
+// NormalizeBarcode valida un código EAN-13 o UPC-A con su dígito de control y lo retorna como EAN-13.
+// Un UPC-A es un EAN-13 con un cero inicial, así que ambas formas identifican al mismo artículo.
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", errors.New("barcode must contain only digits")
+		}
+	}
+
+	switch len(code) {
+	case 12:
+		code = "0" + code
+	case 13:
+	default:
+		return "", errors.New("barcode must be an EAN-13 or UPC-A code")
+	}
+
+	// Los dígitos se ponderan 1 y 3 alternadamente desde la izquierda; el de control completa la decena.
+	sum := 0
+	for i, r := range code[:12] {
+		digit := int(r - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	if check := (10 - sum%10) % 10; check != int(code[12]-'0') {
+		return "", errors.New("invalid barcode check digit")
+	}
+	return code, nil
+}
+
+var attributeNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// NormalizeAttribute valida un atributo de variante: el nombre se guarda en minúsculas y solo admite
+// letras, dígitos y guiones bajos porque forma parte de la ruta del campo en MongoDB; el valor no puede
+// quedar vacío.
+func NormalizeAttribute(name, value string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !attributeNamePattern.MatchString(name) {
+		return "", "", errors.New("attribute names must have up to 32 letters, digits or '_'")
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", "", errors.New("attribute " + name + " must have a value")
+	}
+	return name, value, nil
+}
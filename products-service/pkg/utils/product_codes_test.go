@@ -0,0 +1,61 @@
+package utils
+
+import "testing"
+
+func TestNormalizeBarcode(t *testing.T) {
+	tc := []struct {
+		Name  string
+		Code  string
+		Want  string
+		Valid bool
+	}{
+		{Name: "EAN-13", Code: "4006381333931", Want: "4006381333931", Valid: true},
+		{Name: "UPC-A", Code: "036000291452", Want: "0036000291452", Valid: true},
+		{Name: "Surrounding spaces", Code: " 036000291452 ", Want: "0036000291452", Valid: true},
+		{Name: "Wrong check digit", Code: "4006381333932"},
+		{Name: "Wrong length", Code: "40063813339"},
+		{Name: "Not digits", Code: "40063813339A1"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := NormalizeBarcode(tc.Code)
+			if (err == nil) != tc.Valid {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.Want {
+				t.Fatalf("unexpected barcode: got %v want %v", got, tc.Want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSKU(t *testing.T) {
+	tc := []struct {
+		Name  string
+		SKU   string
+		Want  string
+		Valid bool
+	}{
+		{Name: "Uppercased", SKU: " kb-001.blk ", Want: "KB-001.BLK", Valid: true},
+		{Name: "Empty", SKU: "  "},
+		{Name: "Leading dash", SKU: "-KB"},
+		{Name: "Spaces inside", SKU: "KB 001"},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			got, err := NormalizeSKU(tc.SKU)
+			if (err == nil) != tc.Valid {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.Want {
+				t.Fatalf("unexpected sku: got %v want %v", got, tc.Want)
+			}
+		})
+	}
+}
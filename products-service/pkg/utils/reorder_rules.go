@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"sync/atomic"
+
+	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
+)
+
+var reorderRulesLookup atomic.Pointer[func() map[string]constants.ReorderRule]
+
+// SetReorderRulesLookup registra la función que retorna la regla de reposición de cada categoría por su slug.
+// Mientras no se registre ninguna, las reglas son constants.CategoryReorderRules.
+func SetReorderRulesLookup(lookup func() map[string]constants.ReorderRule) {
+	reorderRulesLookup.Store(&lookup)
+}
+
+// CategoryReorderRules retorna la regla de reposición por defecto de cada categoría; no debe modificarse.
+func CategoryReorderRules() map[string]constants.ReorderRule {
+	if lookup := reorderRulesLookup.Load(); lookup != nil && *lookup != nil {
+		return (*lookup)()
+	}
+	return constants.CategoryReorderRules
+}
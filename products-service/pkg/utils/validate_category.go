@@ -2,12 +2,24 @@ package utils
 
 import (
 	"strings"
+	"sync/atomic"
 
 	"github.com/jaider-nieto/ecommerce-go/products-service/internal/constants"
 )
 
+var categoryLookup atomic.Pointer[func(string) bool]
+
+// SetCategoryLookup registra la función que resuelve si existe la categoría con un slug dado.
+// Mientras no se registre ninguna, las categorías válidas son constants.AllowCategories.
+func SetCategoryLookup(lookup func(slug string) bool) {
+	categoryLookup.Store(&lookup)
+}
+
 func IsValidCategory(category string) bool {
-	category = strings.ToLower(category)
+	category = strings.ToLower(strings.TrimSpace(category))
+	if lookup := categoryLookup.Load(); lookup != nil && *lookup != nil {
+		return (*lookup)(category)
+	}
 	for _, c := range constants.AllowCategories {
 		if c == category {
 			return true
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// jwksCopies son los directorios, relativos a la raíz del repositorio, con una copia del cliente JWKS.
+var jwksCopies = []string{"products-service/internal/auth", "user-service/auth"}
+
+// TestJWKSCopiesMatch verifica que las copias del cliente JWKS sigan siendo idénticas. Se omite
+// cuando el servicio se construye fuera del repositorio completo, por ejemplo en su imagen Docker.
+func TestJWKSCopiesMatch(t *testing.T) {
+	root, ok := repositoryRoot()
+	if !ok {
+		t.Skip("the other services are not available")
+	}
+
+	for _, name := range []string{"jwks.go", "jwks_test.go", "jwks_copy_test.go"} {
+		expected, err := os.ReadFile(filepath.Join(root, jwksCopies[0], name))
+		if err != nil {
+			t.Fatalf("could not read %s: %v", name, err)
+		}
+		for _, dir := range jwksCopies[1:] {
+			copied, err := os.ReadFile(filepath.Join(root, dir, name))
+			if err != nil {
+				t.Fatalf("could not read %s: %v", name, err)
+			}
+			if !bytes.Equal(copied, expected) {
+				t.Errorf("%s differs from %s", filepath.Join(dir, name), filepath.Join(jwksCopies[0], name))
+			}
+		}
+	}
+}
+
+// repositoryRoot busca, desde el directorio actual hacia arriba, el que contiene todas las copias.
+func repositoryRoot() (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		found := true
+		for _, copyDir := range jwksCopies {
+			if _, err := os.Stat(filepath.Join(dir, copyDir, "jwks.go")); err != nil {
+				found = false
+				break
+			}
+		}
+		if found {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
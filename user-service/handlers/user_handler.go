@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
 	"github.com/jaider-nieto/ecommerce-go/user-service/interfaces"
 	"github.com/jaider-nieto/ecommerce-go/user-service/models"
 	"github.com/jaider-nieto/ecommerce-go/user-service/utils"
@@ -22,15 +23,25 @@ func NewUserHandler(UserRepository interfaces.UserRepositoryInterface) *userHand
 }
 
 func (h *userHandler) GetUsersHandler(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userRepository.FindAllUsers()
+	page, size, err := utils.ParsePagination(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	users, total, err := h.userRepository.FindAllUsers(page, size)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte("internal server error"))
 		return
 	}
 
+	result := models.UserPage{Items: users, Total: total, Page: page, Size: size}
+	result.Next, result.Prev = utils.PageLinks(r.URL, page, size, total)
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(users)
+	json.NewEncoder(w).Encode(result)
 }
 func (h *userHandler) GetUserHandler(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -73,6 +84,7 @@ func (h *userHandler) RegisterUserHandlder(w http.ResponseWriter, r *http.Reques
 	}
 
 	user.Password = hashPassword
+	user.Role = models.RoleCustomer
 
 	user, dbErr := h.userRepository.CreateUser(user)
 	if dbErr != nil {
@@ -111,7 +123,7 @@ func (h *userHandler) LoginUserHanlder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("user login"))
+	json.NewEncoder(w).Encode(models.UserIdentity{ID: user.ID, Email: user.Email, Role: user.Role})
 }
 func (h *userHandler) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	var params = mux.Vars(r)
@@ -169,6 +181,20 @@ func (h *userHandler) PatchUserHandler(w http.ResponseWriter, r *http.Request) {
 	if email, ok := input["email"].(string); ok {
 		user.Email = email
 	}
+	if role, ok := input["role"].(string); ok {
+		claims, _ := auth.ClaimsFromContext(r.Context())
+		if claims == nil || claims.Role != models.RoleAdmin {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("only admins can change roles"))
+			return
+		}
+		if !models.IsValidRole(role) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid role"))
+			return
+		}
+		user.Role = role
+	}
 
 	if err := h.userRepository.UpdateUser(user); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
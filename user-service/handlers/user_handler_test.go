@@ -12,6 +12,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/gorilla/mux"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
 	"github.com/jaider-nieto/ecommerce-go/user-service/middlewares"
 	"github.com/jaider-nieto/ecommerce-go/user-service/models"
 	"github.com/jaider-nieto/ecommerce-go/user-service/repository"
@@ -35,33 +36,113 @@ func initRequest(method string, url string, body io.Reader) (*httptest.ResponseR
 }
 
 func TestGetUsersHandler(t *testing.T) {
+	jaider := models.User{
+		FirstName: "Jaider",
+		LastName:  "Nieto",
+		Email:     "email@example.com",
+		Password:  "hashPassword",
+	}
+	augusto := models.User{
+		FirstName: "Augusto",
+		LastName:  "Criollo",
+		Email:     "email2@example.com",
+		Password:  "hashPassword",
+	}
+	link := func(s string) *string { return &s }
+
 	testCases := []struct {
 		Name              string
+		URL               string
 		ExpectedStatus    int
 		ExpectedError     string
-		ExpectedUsers     []models.User
+		ExpectedPage      models.UserPage
 		ShouldReturnError bool
 	}{
 		{
 			Name:           "Get all users",
+			URL:            "/users",
+			ExpectedStatus: http.StatusOK,
+			ExpectedPage: models.UserPage{
+				Items: []models.User{jaider, augusto},
+				Total: 2,
+				Page:  1,
+				Size:  10,
+			},
+		},
+		{
+			Name:           "First page",
+			URL:            "/users?size=1",
+			ExpectedStatus: http.StatusOK,
+			ExpectedPage: models.UserPage{
+				Items: []models.User{jaider},
+				Total: 2,
+				Page:  1,
+				Size:  1,
+				Next:  link("/users?page=2&size=1"),
+			},
+		},
+		{
+			Name:           "Last page",
+			URL:            "/users?page=2&size=1",
+			ExpectedStatus: http.StatusOK,
+			ExpectedPage: models.UserPage{
+				Items: []models.User{augusto},
+				Total: 2,
+				Page:  2,
+				Size:  1,
+				Prev:  link("/users?page=1&size=1"),
+			},
+		},
+		{
+			Name:           "Invalid page",
+			URL:            "/users?page=0",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid page parameter",
+		},
+		{
+			Name:           "Invalid size",
+			URL:            "/users?size=abc",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid size parameter",
+		},
+		{
+			Name:           "Maximum size",
+			URL:            "/users?size=100",
+			ExpectedStatus: http.StatusOK,
+			ExpectedPage: models.UserPage{
+				Items: []models.User{jaider, augusto},
+				Total: 2,
+				Page:  1,
+				Size:  100,
+			},
+		},
+		{
+			Name:           "Size above the maximum",
+			URL:            "/users?size=1000000000",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid size parameter",
+		},
+		{
+			Name:           "Last page whose offset fits",
+			URL:            "/users?page=922337203685477580",
 			ExpectedStatus: http.StatusOK,
-			ExpectedUsers: []models.User{
-				{
-					FirstName: "Jaider",
-					LastName:  "Nieto",
-					Email:     "email@example.com",
-					Password:  "hashPassword",
-				},
-				{
-					FirstName: "Augusto",
-					LastName:  "Criollo",
-					Email:     "email2@example.com",
-					Password:  "hashPassword",
-				},
+			ExpectedPage: models.UserPage{
+				Items: []models.User{},
+				Total: 2,
+				Page:  922337203685477580,
+				Size:  10,
+				Prev:  link("/users?page=922337203685477579&size=10"),
 			},
 		},
+		{
+			Name:           "Page whose offset overflows",
+			URL:            "/users?page=922337203685477581",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid page parameter",
+		},
 		{
 			Name:              "Server error",
+			URL:               "/users",
 			ExpectedStatus:    http.StatusInternalServerError,
 			ExpectedError:     "internal server error",
 			ShouldReturnError: true,
@@ -73,24 +154,24 @@ func TestGetUsersHandler(t *testing.T) {
 
 		t.Run(tc.Name, func(t *testing.T) {
 			h := initHandlerUsers(t, tc.ShouldReturnError)
-			rr, req := initRequest(http.MethodGet, "/users", nil)
+			rr, req := initRequest(http.MethodGet, tc.URL, nil)
 
 			h.GetUsersHandler(rr, req)
 
 			if rr.Code != tc.ExpectedStatus {
 				t.Errorf("expected status %v, got %v", tc.ExpectedStatus, rr.Code)
 			}
-			if rr.Code == http.StatusInternalServerError {
+			if rr.Code != http.StatusOK {
 				if rr.Body.String() != tc.ExpectedError {
-					t.Errorf("unexpected error: got %v, want %v", rr.Body.String(), tc.ExpectedUsers)
+					t.Errorf("unexpected error: got %v, want %v", rr.Body.String(), tc.ExpectedError)
 				}
-			} else if rr.Code == http.StatusOK {
-				var gotUser []models.User
-				if err := json.Unmarshal(rr.Body.Bytes(), &gotUser); err != nil {
+			} else {
+				var gotPage models.UserPage
+				if err := json.Unmarshal(rr.Body.Bytes(), &gotPage); err != nil {
 					t.Fatalf("failed to unmarshal response body: %v", err)
 				}
-				if !reflect.DeepEqual(gotUser, tc.ExpectedUsers) {
-					t.Errorf("unexpected response body: got %v, want %v", gotUser, tc.ExpectedUsers)
+				if !reflect.DeepEqual(gotPage, tc.ExpectedPage) {
+					t.Errorf("unexpected response body: got %v, want %v", gotPage, tc.ExpectedPage)
 				}
 			}
 
@@ -298,7 +379,7 @@ func TestLoginUserHanlder(t *testing.T) {
 		ShouldReturnError bool
 		ExpectedError     string
 		ExpectedStatus    int
-		ExpectedMessage   string
+		ExpectedIdentity  models.UserIdentity
 		UserLogin         models.UserLogin
 		UserBad           any
 	}{
@@ -309,7 +390,10 @@ func TestLoginUserHanlder(t *testing.T) {
 				Email:    "email@valid.com",
 				Password: "hashpassword",
 			},
-			ExpectedMessage: "user login",
+			ExpectedIdentity: models.UserIdentity{
+				ID:    1,
+				Email: "email@example.com",
+			},
 		},
 		{
 			Name:           "invalid email",
@@ -375,9 +459,12 @@ func TestLoginUserHanlder(t *testing.T) {
 			}
 
 			if rr.Code == http.StatusOK {
-				if rr.Body.String() != tc.ExpectedMessage {
-					t.Fatalf("unexpected message: got %v want %v",
-						rr.Body.String(), tc.ExpectedMessage)
+				var gotIdentity models.UserIdentity
+				if err := json.Unmarshal(rr.Body.Bytes(), &gotIdentity); err != nil {
+					t.Fatalf("failed to unmarshal response body: %v", err)
+				}
+				if gotIdentity != tc.ExpectedIdentity {
+					t.Fatalf("unexpected identity: got %v want %v", gotIdentity, tc.ExpectedIdentity)
 				}
 			} else {
 				if rr.Body.String() != tc.ExpectedError {
@@ -462,6 +549,7 @@ func TestPatchUserHandler(t *testing.T) {
 		ExpectedStatus    int
 		UserID            string
 		UserBody          models.UserUpdate
+		Claims            *auth.Claims
 		ExpectedUser      models.User
 	}{
 		{
@@ -481,6 +569,43 @@ func TestPatchUserHandler(t *testing.T) {
 				Password:  "hashPassword",
 			},
 		},
+		{
+			Name:           "Admin changes role",
+			ExpectedStatus: http.StatusOK,
+			UserID:         "1",
+			UserBody: models.UserUpdate{
+				Role: models.RoleInventoryManager,
+			},
+			Claims: &auth.Claims{Role: models.RoleAdmin},
+			ExpectedUser: models.User{
+				Model:     gorm.Model{ID: 1},
+				FirstName: "Jaider",
+				LastName:  "Nieto",
+				Email:     "email@example.com",
+				Password:  "hashPassword",
+				Role:      models.RoleInventoryManager,
+			},
+		},
+		{
+			Name:           "Owner cannot change role",
+			ExpectedStatus: http.StatusForbidden,
+			ExpectedError:  "only admins can change roles",
+			UserID:         "1",
+			UserBody: models.UserUpdate{
+				Role: models.RoleAdmin,
+			},
+			Claims: &auth.Claims{Role: models.RoleCustomer},
+		},
+		{
+			Name:           "Invalid role",
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedError:  "invalid role",
+			UserID:         "1",
+			UserBody: models.UserUpdate{
+				Role: "superuser",
+			},
+			Claims: &auth.Claims{Role: models.RoleAdmin},
+		},
 		{
 			Name:           "User not found",
 			ExpectedStatus: http.StatusNotFound,
@@ -521,6 +646,9 @@ func TestPatchUserHandler(t *testing.T) {
 			req = mux.SetURLVars(req, map[string]string{
 				"id": tc.UserID,
 			})
+			if tc.Claims != nil {
+				req = req.WithContext(auth.ContextWithClaims(req.Context(), tc.Claims))
+			}
 
 			h.PatchUserHandler(rr, req)
 
@@ -16,7 +16,7 @@ type UserHandelrInterface interface {
 }
 
 type UserRepositoryInterface interface {
-	FindAllUsers() ([]models.User, error)
+	FindAllUsers(page, size int) ([]models.User, int64, error)
 	FindUserByID(id string) (models.User, error)
 	FindUserByEmail(email string) (models.User, error)
 	CreateUser(user models.User) (models.User, error)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
 	"github.com/jaider-nieto/ecommerce-go/user-service/db"
 	"github.com/jaider-nieto/ecommerce-go/user-service/models"
 	"github.com/jaider-nieto/ecommerce-go/user-service/routes"
@@ -16,9 +17,14 @@ func main() {
 		log.Fatalf("Error loading .env file")
 	}
 
+	jwksURL := os.Getenv("JWKS_URL")
+	if jwksURL == "" {
+		log.Fatalf("JWKS_URL is not set")
+	}
+
 	db.DBConnection(os.Getenv("DSN"))
 
 	db.DB.AutoMigrate(models.User{})
 
-	http.ListenAndServe(os.Getenv("PORT"), routes.Routes(db.DB))
+	http.ListenAndServe(os.Getenv("PORT"), routes.Routes(db.DB, auth.NewJWKS(jwksURL).KeyFunc))
 }
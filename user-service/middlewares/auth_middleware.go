@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
+	"github.com/jaider-nieto/ecommerce-go/user-service/utils"
+)
+
+func AuthMiddleware(next http.Handler, keyFunc jwt.Keyfunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" {
+			writeJSONError(w, http.StatusUnauthorized, "missing authorization header")
+			return
+		}
+
+		tokenString, err := utils.TokenSeparator(authHeader)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		claims, err := auth.ParseToken(tokenString, keyFunc)
+		if err != nil {
+			writeJSONError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithClaims(r.Context(), claims)))
+	})
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
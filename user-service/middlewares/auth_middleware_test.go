@@ -0,0 +1,213 @@
+package middlewares
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
+)
+
+type testKeys struct {
+	ed    ed25519.PrivateKey
+	other ed25519.PrivateKey
+	rsa   *rsa.PrivateKey
+}
+
+func initKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	return testKeys{ed: ed, other: other, rsa: rsaKey}
+}
+
+// initJWKSServer publica las claves públicas como lo hace auth-service.
+func initJWKSServer(t *testing.T, keys testKeys) *httptest.Server {
+	t.Helper()
+
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "OKP", "kid": "ed", "use": "sig", "alg": "EdDSA", "crv": "Ed25519",
+				"x": base64.RawURLEncoding.EncodeToString(keys.ed.Public().(ed25519.PublicKey)),
+			},
+			{
+				"kty": "RSA", "kid": "rsa", "use": "sig", "alg": "RS256",
+				"n": base64.RawURLEncoding.EncodeToString(keys.rsa.N.Bytes()),
+				"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(keys.rsa.E)).Bytes()),
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("could not sign token: %v", err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "email@example.com",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	keys := initKeys(t)
+	keyFunc := auth.NewJWKS(initJWKSServer(t, keys).URL).KeyFunc
+
+	tc := []struct {
+		Name            string
+		Header          string
+		ExpectedStatus  int
+		ExpectedError   string
+		ExpectedSubject string
+	}{
+		{
+			Name:            "Valid EdDSA token",
+			Header:          "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "ed", keys.ed, validClaims()),
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "email@example.com",
+		},
+		{
+			Name:            "Valid RS256 token",
+			Header:          "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", keys.rsa, validClaims()),
+			ExpectedStatus:  http.StatusOK,
+			ExpectedSubject: "email@example.com",
+		},
+		{
+			Name:           "Missing header",
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "missing authorization header",
+		},
+		{
+			Name:           "Invalid format",
+			Header:         "Token abc",
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid authorization format",
+		},
+		{
+			Name: "Expired token",
+			Header: "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "ed", keys.ed, jwt.MapClaims{
+				"sub": "email@example.com",
+				"exp": time.Now().Add(-time.Hour).Unix(),
+			}),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "token expired",
+		},
+		{
+			Name: "Missing expiration",
+			Header: "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "ed", keys.ed, jwt.MapClaims{
+				"sub": "email@example.com",
+			}),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid token",
+		},
+		{
+			Name: "Missing subject",
+			Header: "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "ed", keys.ed, jwt.MapClaims{
+				"exp": time.Now().Add(time.Hour).Unix(),
+			}),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "token subject is missing",
+		},
+		{
+			Name:           "Wrong key",
+			Header:         "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "ed", keys.other, validClaims()),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid token",
+		},
+		{
+			Name:           "Unknown key id",
+			Header:         "Bearer " + signToken(t, jwt.SigningMethodEdDSA, "missing", keys.ed, validClaims()),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid token",
+		},
+		{
+			Name:           "Shared secret token",
+			Header:         "Bearer " + signToken(t, jwt.SigningMethodHS256, "rsa", keys.rsa.N.Bytes(), validClaims()),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid token",
+		},
+		{
+			Name:           "Unsigned token",
+			Header:         "Bearer " + signToken(t, jwt.SigningMethodNone, "ed", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+			ExpectedStatus: http.StatusUnauthorized,
+			ExpectedError:  "invalid token",
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			var gotSubject string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if claims, ok := auth.ClaimsFromContext(r.Context()); ok {
+					gotSubject = claims.Subject
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tc.Header != "" {
+				req.Header.Set("Authorization", tc.Header)
+			}
+			rr := httptest.NewRecorder()
+
+			AuthMiddleware(next, keyFunc).ServeHTTP(rr, req)
+
+			if rr.Code != tc.ExpectedStatus {
+				t.Fatalf("unexpected status: got %v want %v", rr.Code, tc.ExpectedStatus)
+			}
+
+			if rr.Code == http.StatusOK {
+				if gotSubject != tc.ExpectedSubject {
+					t.Errorf("unexpected subject: got %v want %v", gotSubject, tc.ExpectedSubject)
+				}
+				return
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to unmarshal response body: %v", err)
+			}
+			if body["error"] != tc.ExpectedError {
+				t.Errorf("unexpected error: got %v want %v", body["error"], tc.ExpectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
+)
+
+func RequireRoles(next http.Handler, roles ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok {
+			writeJSONError(w, http.StatusUnauthorized, "missing credentials")
+			return
+		}
+
+		if !hasRole(claims, roles) {
+			writeJSONError(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireOwnerOrRoles permite la solicitud si el usuario de la ruta es el llamador o si tiene alguno de los roles.
+func RequireOwnerOrRoles(next http.Handler, roles ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok {
+			writeJSONError(w, http.StatusUnauthorized, "missing credentials")
+			return
+		}
+
+		if claims.Subject != mux.Vars(r)["id"] && !hasRole(claims, roles) {
+			writeJSONError(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func hasRole(claims *auth.Claims, roles []string) bool {
+	for _, role := range roles {
+		if claims.Role == role {
+			return true
+		}
+	}
+	return false
+}
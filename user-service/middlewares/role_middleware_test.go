@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/jaider-nieto/ecommerce-go/user-service/auth"
+	"github.com/jaider-nieto/ecommerce-go/user-service/models"
+)
+
+func TestRoleMiddlewares(t *testing.T) {
+	tc := []struct {
+		Name           string
+		OwnerAllowed   bool
+		UserID         string
+		Claims         *auth.Claims
+		ExpectedStatus int
+	}{
+		{
+			Name:           "Admin allowed",
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Role: models.RoleAdmin},
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Name:           "Customer forbidden",
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Role: models.RoleCustomer},
+			ExpectedStatus: http.StatusForbidden,
+		},
+		{
+			Name:           "Owner forbidden without owner rule",
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleCustomer},
+			ExpectedStatus: http.StatusForbidden,
+		},
+		{
+			Name:           "Owner allowed",
+			OwnerAllowed:   true,
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Role: models.RoleCustomer},
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Name:           "Other user forbidden",
+			OwnerAllowed:   true,
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Role: models.RoleInventoryManager},
+			ExpectedStatus: http.StatusForbidden,
+		},
+		{
+			Name:           "Admin allowed on other user",
+			OwnerAllowed:   true,
+			UserID:         "1",
+			Claims:         &auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Role: models.RoleAdmin},
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Name:           "Missing claims",
+			OwnerAllowed:   true,
+			UserID:         "1",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for i := range tc {
+		tc := tc[i]
+
+		t.Run(tc.Name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			var handler http.Handler
+			if tc.OwnerAllowed {
+				handler = RequireOwnerOrRoles(next, models.RoleAdmin)
+			} else {
+				handler = RequireRoles(next, models.RoleAdmin)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tc.UserID, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.UserID})
+			if tc.Claims != nil {
+				req = req.WithContext(auth.ContextWithClaims(req.Context(), tc.Claims))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.ExpectedStatus {
+				t.Fatalf("unexpected status: got %v want %v", rr.Code, tc.ExpectedStatus)
+			}
+		})
+	}
+}
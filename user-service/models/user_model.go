@@ -2,12 +2,23 @@ package models
 
 import "gorm.io/gorm"
 
+const (
+	RoleAdmin            = "admin"
+	RoleInventoryManager = "inventory-manager"
+	RoleCustomer         = "customer"
+)
+
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleInventoryManager || role == RoleCustomer
+}
+
 type User struct {
 	gorm.Model
 	FirstName string `gorm:"not null" json:"first_name" validate:"required"`
 	LastName  string `gorm:"not null" json:"last_name" validate:"required"`
 	Email     string `gorm:"not null;unique" json:"email" validate:"required,email"`
 	Password  string `gorm:"not null" json:"password" validate:"required,min=8"`
+	Role      string `gorm:"not null;default:customer" json:"role"`
 }
 
 type UserUpdate struct {
@@ -15,6 +26,22 @@ type UserUpdate struct {
 	LastName  string `json:"last_name,omitempty"`
 	Email     string `json:"email,omitempty"`
 	Password  string `json:"password,omitempty"`
+	Role      string `json:"role,omitempty"`
+}
+
+type UserPage struct {
+	Items []User  `json:"items"`
+	Total int64   `json:"total"`
+	Page  int     `json:"page"`
+	Size  int     `json:"size"`
+	Next  *string `json:"next"`
+	Prev  *string `json:"prev"`
+}
+
+type UserIdentity struct {
+	ID    uint   `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
 }
 
 type UserLogin struct {
@@ -13,12 +13,17 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (r *UserRepository) FindAllUsers() ([]models.User, error) {
-	var users []models.User
+func (r *UserRepository) FindAllUsers(page, size int) ([]models.User, int64, error) {
+	users := []models.User{}
 
-	err := r.DB.Find(&users).Error
+	var total int64
+	if err := r.DB.Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
-	return users, err
+	err := r.DB.Order("id").Offset((page - 1) * size).Limit(size).Find(&users).Error
+
+	return users, total, err
 }
 func (r *UserRepository) FindUserByID(id string) (models.User, error) {
 	var user models.User
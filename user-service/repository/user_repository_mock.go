@@ -11,11 +11,11 @@ type UserRepositoryMocked struct {
 	ShouldReturnError bool
 }
 
-func (rm *UserRepositoryMocked) FindAllUsers() ([]models.User, error) {
+func (rm *UserRepositoryMocked) FindAllUsers(page, size int) ([]models.User, int64, error) {
 	if rm.ShouldReturnError {
-		return nil, errors.New("internal server error")
+		return nil, 0, errors.New("internal server error")
 	}
-	return []models.User{
+	users := []models.User{
 		{
 			FirstName: "Jaider",
 			LastName:  "Nieto",
@@ -28,7 +28,17 @@ func (rm *UserRepositoryMocked) FindAllUsers() ([]models.User, error) {
 			Email:     "email2@example.com",
 			Password:  "hashPassword",
 		},
-	}, nil
+	}
+
+	start := (page - 1) * size
+	if start > len(users) {
+		start = len(users)
+	}
+	end := start + size
+	if end > len(users) {
+		end = len(users)
+	}
+	return users[start:end], int64(len(users)), nil
 }
 func (rm *UserRepositoryMocked) FindUserByID(id string) (models.User, error) {
 	if rm.ShouldReturnError && id == "1" {
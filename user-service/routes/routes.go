@@ -3,6 +3,7 @@ package routes
 import (
 	"net/http"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
 	"github.com/jaider-nieto/ecommerce-go/user-service/handlers"
 	"github.com/jaider-nieto/ecommerce-go/user-service/middlewares"
@@ -11,7 +12,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func Routes(db *gorm.DB) *mux.Router {
+func Routes(db *gorm.DB, keyFunc jwt.Keyfunc) *mux.Router {
 	r := mux.NewRouter()
 
 	//Inicializa los repositorios.
@@ -25,10 +26,11 @@ func Routes(db *gorm.DB) *mux.Router {
 
 	r.Handle("/login", middlewares.ValidationMiddleware(http.HandlerFunc(handlerUsers.LoginUserHanlder), &models.UserLogin{})).Methods("POST")
 
-	r.HandleFunc("/users", handlerUsers.GetUsersHandler).Methods("GET")
-	r.HandleFunc("/users/{id:[0-9]+}", handlerUsers.GetUserHandler).Methods("GET")
-	r.HandleFunc("/users/{id:[0-9]+}", handlerUsers.DeleteUserHandler).Methods("DELETE")
-	r.HandleFunc("/users/{id:[0-9]+}", handlerUsers.PatchUserHandler).Methods("PATCH")
+	//Solo los administradores listan usuarios; el resto de rutas también admite al dueño de la cuenta.
+	r.Handle("/users", middlewares.AuthMiddleware(middlewares.RequireRoles(http.HandlerFunc(handlerUsers.GetUsersHandler), models.RoleAdmin), keyFunc)).Methods("GET")
+	r.Handle("/users/{id:[0-9]+}", middlewares.AuthMiddleware(middlewares.RequireOwnerOrRoles(http.HandlerFunc(handlerUsers.GetUserHandler), models.RoleAdmin), keyFunc)).Methods("GET")
+	r.Handle("/users/{id:[0-9]+}", middlewares.AuthMiddleware(middlewares.RequireOwnerOrRoles(http.HandlerFunc(handlerUsers.DeleteUserHandler), models.RoleAdmin), keyFunc)).Methods("DELETE")
+	r.Handle("/users/{id:[0-9]+}", middlewares.AuthMiddleware(middlewares.RequireOwnerOrRoles(http.HandlerFunc(handlerUsers.PatchUserHandler), models.RoleAdmin), keyFunc)).Methods("PATCH")
 
 	return r
 }
@@ -81,10 +81,11 @@ func TestGetUsers(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Fatalf("expected status code %d, got %d", http.StatusOK, rr.Code)
 	}
-	var responseUsers []models.User
-	if err := json.Unmarshal(rr.Body.Bytes(), &responseUsers); err != nil {
+	var responsePage models.UserPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &responsePage); err != nil {
 		t.Fatalf("failed to unmarshal response body: %v", err)
 	}
+	responseUsers := responsePage.Items
 
 	if len(responseUsers) != len(users) {
 		t.Fatalf("expected %d users, got %d", len(users), len(responseUsers))
@@ -218,7 +219,11 @@ func TestLoginUser(t *testing.T) {
 		log.Println(rr.Body.String())
 		t.Fatalf("expected status code %d, got %d", http.StatusCreated, rr.Code)
 	}
-	if rr.Body.String() != "user login" {
+	var identity models.UserIdentity
+	if err := json.Unmarshal(rr.Body.Bytes(), &identity); err != nil {
+		t.Fatalf("failed to unmarshal response body: %v", err)
+	}
+	if identity.Email != user.Email || identity.Role != models.RoleCustomer {
 		t.Fatalf("failed to login user: %v", rr.Body.String())
 	}
 }
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+const MaxPageSize = 100
+
+func ParsePagination(query url.Values) (int, int, error) {
+	page, size := 1, 10
+
+	if value := query.Get("size"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > MaxPageSize {
+			return 0, 0, errors.New("invalid size parameter")
+		}
+		size = n
+	}
+	if value := query.Get("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		// El offset (page-1)*size tiene que caber en un int.
+		if err != nil || n < 1 || n > math.MaxInt/size {
+			return 0, 0, errors.New("invalid page parameter")
+		}
+		page = n
+	}
+
+	return page, size, nil
+}
+
+func PageLinks(u *url.URL, page, size int, total int64) (next, prev *string) {
+	link := func(p int) *string {
+		target := *u
+		q := target.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("size", strconv.Itoa(size))
+		target.RawQuery = q.Encode()
+		s := target.RequestURI()
+		return &s
+	}
+
+	if size > 0 && int64(page) < (total+int64(size)-1)/int64(size) {
+		next = link(page + 1)
+	}
+	if page > 1 {
+		prev = link(page - 1)
+	}
+	return next, prev
+}